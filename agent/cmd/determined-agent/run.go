@@ -97,6 +97,8 @@ func newRunCmd() *cobra.Command {
 	// Labels flags.
 	cmd.Flags().StringVar(&opts.Label, "label", "",
 		"This field has been deprecated and will be ignored, use ``resource_pool`` instead.")
+	cmd.Flags().StringToStringVar(&opts.Labels, "labels", nil,
+		"Key/value labels the agent advertises to the master, e.g. storage=nvme,rack=a1")
 
 	// ResourcePool flags.
 	cmd.Flags().StringVar(&opts.ResourcePool, "resource-pool", "",
@@ -116,6 +118,18 @@ func newRunCmd() *cobra.Command {
 		"visible-gpus",
 		defaultVisibleGPUs,
 		"GPUs to expose as slots")
+	cmd.Flags().IntVar(&opts.DeviceHealth.CheckInterval, "device-health-check-interval", 60,
+		"Time between device health checks in seconds, 0 to disable")
+
+	// Image flags.
+	cmd.Flags().IntVar(&opts.ImageCache.MaxSizeGB, "image-cache-max-size-gb", 0,
+		"Disk space images may use before Determined images are evicted in GB, 0 to disable")
+
+	// Message spool flags.
+	cmd.Flags().StringVar(&opts.MessageSpool.Path, "message-spool-path", "",
+		"File to spool messages for the master to while disconnected from it")
+	cmd.Flags().IntVar(&opts.MessageSpool.MaxSizeMB, "message-spool-max-size-mb", 64,
+		"Space spooled messages may use before container logs are dropped in MB, 0 to disable")
 
 	// Security flags.
 	cmd.Flags().BoolVar(
@@ -150,6 +164,8 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&opts.BindIP, "bind-ip", "0.0.0.0",
 		"IP address to listen on for API requests")
 	cmd.Flags().IntVar(&opts.BindPort, "bind-port", 9090, "Port to listen on for API requests")
+	cmd.Flags().StringVar(&opts.APIToken, "api-token", "",
+		"Bearer token required by the agent API status endpoints")
 
 	// Proxy flags.
 	cmd.Flags().StringVar(&opts.HTTPProxy, "http-proxy", "",
@@ -170,6 +186,12 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&opts.ContainerRuntime, "container-runtime",
 		options.DockerContainerRuntime, "The container runtime to use")
 
+	// Telemetry flags.
+	cmd.Flags().BoolVar(&opts.Telemetry.OtelEnabled, "telemetry-otel-enabled", false,
+		"enable otel")
+	cmd.Flags().StringVar(&opts.Telemetry.OtelExportedOtlpEndpoint, "telemetry-otel-endpoint",
+		"localhost:4317", "set otel endpoint")
+
 	return cmd
 }
 
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	dclient "github.com/docker/docker/client"
@@ -18,7 +20,11 @@ import (
 	"github.com/determined-ai/determined/agent/internal/container"
 	"github.com/determined-ai/determined/agent/internal/containers"
 	"github.com/determined-ai/determined/agent/internal/detect"
+	"github.com/determined-ai/determined/agent/internal/hooks"
+	"github.com/determined-ai/determined/agent/internal/imagecache"
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/internal/spool"
+	"github.com/determined-ai/determined/agent/pkg/bare"
 	"github.com/determined-ai/determined/agent/pkg/docker"
 	"github.com/determined-ai/determined/agent/pkg/events"
 	"github.com/determined-ai/determined/agent/pkg/podman"
@@ -35,6 +41,7 @@ const (
 	wsSecureScheme   = "wss"
 	eventChanSize    = 64 // same size as the websocket outbox
 	logSourceAgent   = "agent"
+	bytesPerMB       = 1 << 20
 )
 
 // MasterWebsocket is the type for a websocket which communicates with the master.
@@ -49,15 +56,23 @@ type Agent struct {
 	opts    options.Options
 	log     *logrus.Entry
 	wg      errgroupx.Group
+	status  *agentStatus
 }
 
 // NewAgent constructs and runs a new agent according to the provided configuration.
 func NewAgent(parent context.Context, version string, opts options.Options) *Agent {
+	return newAgent(parent, version, opts, &agentStatus{})
+}
+
+func newAgent(
+	parent context.Context, version string, opts options.Options, status *agentStatus,
+) *Agent {
 	a := &Agent{
 		version: version,
 		opts:    opts,
 		log:     logrus.WithField("component", "agent"),
 		wg:      errgroupx.WithContext(parent),
+		status:  status,
 	}
 
 	a.wg.Go(func(ctx context.Context) error {
@@ -87,8 +102,10 @@ func (a *Agent) run(ctx context.Context) error {
 	a.log.Trace("connecting to master")
 	socket, err := a.connect(ctx, false)
 	if err != nil {
+		a.status.disconnected(err)
 		return masterConnectionError{cause: fmt.Errorf("initial connection to master failed: %w", err)}
 	}
+	a.status.connected(false)
 	defer func() {
 		a.log.Trace("cleaning up socket")
 		if cErr := socket.Close(); err != nil {
@@ -115,10 +132,13 @@ func (a *Agent) run(ctx context.Context) error {
 	a.log.Trace("detecting devices")
 	devices, err := detect.Detect(
 		a.opts.SlotType, a.opts.AgentID, a.opts.VisibleGPUs, a.opts.ArtificialSlots,
+		a.opts.DevicePlugin.Command,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to detect devices: %v", devices)
+		return fmt.Errorf("failed to detect devices: %w", err)
 	}
+	labels := detect.Labels(devices, a.opts.Labels)
+	hooks.New(a.opts).OnDevicesDetected(ctx, devices)
 
 	a.log.Tracef("setting up %s runtime", a.opts.ContainerRuntime)
 	var cruntime container.ContainerRuntime
@@ -147,6 +167,17 @@ func (a *Agent) run(ctx context.Context) error {
 			}
 		}()
 		cruntime = acl
+	case options.BareContainerRuntime:
+		acl, bErr := bare.New(a.opts)
+		if bErr != nil {
+			return fmt.Errorf("failed to build bare client: %w", bErr)
+		}
+		defer func() {
+			if cErr := acl.Close(); cErr != nil {
+				a.log.WithError(cErr).Error("failed to close bare client")
+			}
+		}()
+		cruntime = acl
 	case options.DockerContainerRuntime:
 		dcl, dErr := dclient.NewClientWithOpts(dclient.WithAPIVersionNegotiation(), dclient.FromEnv)
 		if dErr != nil {
@@ -172,6 +203,21 @@ func (a *Agent) run(ctx context.Context) error {
 		a.log.Trace("detaching container manager")
 		manager.Detach()
 	}()
+	a.status.setup(devices, labels, manager)
+	defer a.status.setup(devices, labels, nil)
+
+	var spooled *spool.Spool
+	if a.opts.MessageSpool.MaxSizeMB > 0 {
+		a.log.Trace("setting up message spool")
+		if spooled, err = a.newSpool(); err != nil {
+			return fmt.Errorf("error initializing message spool: %w", err)
+		}
+		defer func() {
+			if err := spooled.Close(); err != nil {
+				a.log.WithError(err).Error("failed to close message spool")
+			}
+		}()
+	}
 
 	a.log.Trace("reattaching containers")
 	reattached, err := manager.ReattachContainers(ctx, mopts.ContainersToReattach)
@@ -180,16 +226,36 @@ func (a *Agent) run(ctx context.Context) error {
 	}
 
 	a.log.Trace("writing agent started message")
+	cpus, memory := detect.HostResources()
 	select {
 	case socket.Outbox <- &aproto.MasterMessage{AgentStarted: &aproto.AgentStarted{
 		Version:              a.version,
 		Devices:              devices,
+		Labels:               labels,
+		CPUs:                 cpus,
+		Memory:               memory,
 		ContainersReattached: reattached,
 	}}:
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 
+	a.log.Trace("starting image cache")
+	images := imagecache.New(a.opts.ImageCache, cruntime, outbox)
+	images.Prefetch(mopts.PrefetchImages)
+	a.wg.Go(func(ctx context.Context) error {
+		images.Run(ctx)
+		return nil
+	})
+
+	if a.opts.DeviceHealth.CheckInterval > 0 {
+		a.log.Trace("starting device health checks")
+		a.wg.Go(func(ctx context.Context) error {
+			a.monitorDeviceHealth(ctx, devices, outbox)
+			return nil
+		})
+	}
+
 	a.log.Trace("watching for ws requests and system events")
 	inbox := socket.Inbox
 	for {
@@ -203,6 +269,7 @@ func (a *Agent) run(ctx context.Context) error {
 
 			switch {
 			case msg.StartContainer != nil:
+				images.Used(msg.StartContainer.Spec.RunSpec.ContainerConfig.Image)
 				if err := manager.StartContainer(ctx, *msg.StartContainer); err != nil {
 					a.log.WithError(err).Error("could not start container")
 				}
@@ -215,6 +282,16 @@ func (a *Agent) run(ctx context.Context) error {
 			}
 
 		case msg := <-outbox:
+			select {
+			case <-socket.Done:
+				if spooled != nil {
+					// The message would be lost in the closed socket, so replay it after reconnecting.
+					a.spool(spooled, msg)
+					continue
+				}
+			default:
+			}
+
 			select {
 			case socket.Outbox <- msg:
 			case <-ctx.Done():
@@ -222,19 +299,23 @@ func (a *Agent) run(ctx context.Context) error {
 			}
 
 		case <-socket.Done:
+			a.status.disconnected(socket.Error())
 			if err := socket.Error(); err != nil {
 				a.log.WithError(err).Error("socket disconnected")
 			} else {
 				a.log.Trace("socket disconnected")
 			}
 
-			newSocket, newMopts, err := a.reconnectFlow(ctx, manager, devices, outbox)
+			newSocket, newMopts, err := a.reconnectFlow(
+				ctx, manager, devices, labels, outbox, spooled,
+			)
 			if err != nil {
 				return err
 			}
 			socket = newSocket
 			inbox = socket.Inbox
 			mopts = *newMopts
+			images.Prefetch(mopts.PrefetchImages)
 
 		case <-ctx.Done():
 			a.log.Trace("context canceled")
@@ -318,6 +399,54 @@ func (a *Agent) sender(out chan *aproto.MasterMessage) events.Publisher[containe
 	)
 }
 
+// monitorDeviceHealth periodically checks the health of the devices and reports it to the master
+// when it changes. While any device is unhealthy, the health is reported after every check, so
+// that a master which restarted or lost the agent learns of it again.
+func (a *Agent) monitorDeviceHealth(
+	ctx context.Context, devices []device.Device, outbox chan *aproto.MasterMessage,
+) {
+	t := time.NewTicker(time.Duration(a.opts.DeviceHealth.CheckInterval) * time.Second)
+	defer t.Stop()
+
+	last := make(map[device.ID]aproto.DeviceHealth, len(devices))
+	for _, d := range devices {
+		last[d.ID] = aproto.DeviceHealth{Device: d, Healthy: true}
+	}
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+
+		health := detect.CheckHealth(ctx, devices, a.opts.DeviceHealth.Command)
+		changed, unhealthy := false, false
+		for _, h := range health {
+			if h != last[h.Device.ID] {
+				changed = true
+				if h.Healthy {
+					a.log.Infof("device %s is healthy again", h.Device.String())
+				} else {
+					a.log.Warnf("device %s is unhealthy: %s", h.Device.String(), h.Reason)
+				}
+			}
+			unhealthy = unhealthy || !h.Healthy
+			last[h.Device.ID] = h
+		}
+		if !changed && !unhealthy {
+			continue
+		}
+
+		select {
+		case outbox <- &aproto.MasterMessage{
+			DevicesHealthChanged: &aproto.DevicesHealthChanged{Devices: health},
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (a *Agent) enrichLog(log *aproto.ContainerLog) *aproto.ContainerLog {
 	log.AgentID = &a.opts.AgentID
 	if log.Source == nil {
@@ -327,16 +456,67 @@ func (a *Agent) enrichLog(log *aproto.ContainerLog) *aproto.ContainerLog {
 	return log
 }
 
+func (a *Agent) newSpool() (*spool.Spool, error) {
+	path := a.opts.MessageSpool.Path
+	if path == "" {
+		path = filepath.Join(os.TempDir(), fmt.Sprintf("determined-agent-%s.spool", a.opts.AgentID))
+	}
+	return spool.New(path, int64(a.opts.MessageSpool.MaxSizeMB)*bytesPerMB)
+}
+
+func (a *Agent) spool(spooled *spool.Spool, msg *aproto.MasterMessage) {
+	if err := spooled.Push(msg); err != nil {
+		a.log.WithError(err).Error("failed to spool message, dropping it")
+	}
+}
+
+// spoolMessages moves the messages from the outbox to the spool until the returned func is called,
+// which waits for it to stop.
+func (a *Agent) spoolMessages(
+	spooled *spool.Spool, outbox chan *aproto.MasterMessage,
+) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case msg := <-outbox:
+				a.spool(spooled, msg)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
 func (a *Agent) reconnectFlow(
 	ctx context.Context,
 	manager *containers.Manager,
 	devices []device.Device,
+	labels map[string]string,
 	outbox chan *aproto.MasterMessage,
+	spooled *spool.Spool,
 ) (
 	*MasterWebsocket,
 	*aproto.MasterSetAgentOptions,
 	error,
 ) {
+	stopSpooling := func() {}
+	if spooled != nil {
+		a.log.Trace("spooling messages while disconnected")
+		stopSpooling = a.spoolMessages(spooled, outbox)
+		defer stopSpooling()
+	}
+
 	a.log.Trace("reconnecting master socket...")
 	socket, err := a.reconnect(ctx)
 	if err != nil {
@@ -371,16 +551,53 @@ func (a *Agent) reconnectFlow(
 	a.log.Tracef("reattached containers after reconnect: %+v", reattachedStates)
 
 	a.log.Trace("writing agent started message")
+	cpus, memory := detect.HostResources()
 	select {
 	case socket.Outbox <- &aproto.MasterMessage{AgentStarted: &aproto.AgentStarted{
 		Version:              a.version,
 		Devices:              devices,
+		Labels:               labels,
+		CPUs:                 cpus,
+		Memory:               memory,
 		ContainersReattached: reattached,
 	}}:
 	case <-ctx.Done():
 		return nil, nil, ctx.Err()
 	}
 
+	forward := func(msg *aproto.MasterMessage) error {
+		if csc := msg.ContainerStateChanged; csc != nil {
+			reattachState, ok := reattachedStates[msg.ContainerStateChanged.Container.ID]
+			if ok && csc.Container.State.Before(reattachState) {
+				a.log.Tracef(
+					"dropping %s transition message for %s",
+					csc.Container.ID, csc.Container.State,
+				)
+				return nil
+			}
+		}
+
+		select {
+		case socket.Outbox <- msg:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if spooled != nil {
+		stopSpooling()
+		a.log.Trace("replaying spooled messages")
+		if err := spooled.Replay(ctx, func(msg *aproto.MasterMessage) error {
+			if msg.ContainerLog != nil {
+				msg.ContainerLog = a.enrichLog(msg.ContainerLog)
+			}
+			return forward(msg)
+		}); err != nil {
+			return nil, nil, fmt.Errorf("failed to replay spooled messages: %w", err)
+		}
+	}
+
 	a.log.Trace("sending sentinel message into output stream")
 	a.wg.Go(func(ctx context.Context) error {
 		select {
@@ -400,21 +617,8 @@ func (a *Agent) reconnectFlow(
 				return socket, mopts, nil
 			}
 
-			if csc := msg.ContainerStateChanged; csc != nil {
-				reattachState, ok := reattachedStates[msg.ContainerStateChanged.Container.ID]
-				if ok && csc.Container.State.Before(reattachState) {
-					a.log.Tracef(
-						"dropping %s transition message for %s",
-						csc.Container.ID, csc.Container.State,
-					)
-					continue
-				}
-			}
-
-			select {
-			case socket.Outbox <- msg:
-			case <-ctx.Done():
-				return nil, nil, ctx.Err()
+			if err := forward(msg); err != nil {
+				return nil, nil, err
 			}
 		case <-ctx.Done():
 			return nil, nil, ctx.Err()
@@ -426,6 +630,7 @@ func (a *Agent) reconnect(ctx context.Context) (*MasterWebsocket, error) {
 	for i := 1; ; i++ {
 		switch ws, err := a.connect(ctx, true); {
 		case err == nil:
+			a.status.connected(true)
 			return ws, nil
 		case errors.Is(err, aproto.ErrAgentMustReconnect):
 			a.log.Warn("received ErrAgentMustReconnect, exiting")
@@ -434,6 +639,7 @@ func (a *Agent) reconnect(ctx context.Context) (*MasterWebsocket, error) {
 			a.log.WithError(err).Warn("exhausted reconnect attempts")
 			return nil, masterConnectionError{cause: err}
 		default:
+			a.status.reconnectFailed(err)
 			a.log.WithError(err).Error("error reconnecting to master")
 		}
 
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
@@ -10,7 +11,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
+	"github.com/determined-ai/determined/agent/internal/containers"
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/logger"
 )
 
@@ -20,9 +23,10 @@ type agentAPIServer struct {
 
 	// Internal state.
 	server *echo.Echo
+	status *agentStatus
 }
 
-func newAgentAPIServer(opts options.Options) *agentAPIServer {
+func newAgentAPIServer(opts options.Options, status *agentStatus) *agentAPIServer {
 	server := echo.New()
 	server.Logger = logger.New()
 	server.HidePort = true
@@ -37,10 +41,78 @@ func newAgentAPIServer(opts options.Options) *agentAPIServer {
 	server.Any("/debug/pprof/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
 	server.Any("/debug/pprof/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
 
-	return &agentAPIServer{
+	a := &agentAPIServer{
 		opts:   opts,
 		server: server,
+		status: status,
 	}
+
+	server.GET("/health", a.getHealth)
+
+	api := server.Group("/api/v1", a.requireToken, middleware.KeyAuth(a.validateToken))
+	api.GET("/master", a.getMaster)
+	api.GET("/devices", a.getDevices)
+	api.GET("/containers", a.getContainers)
+	api.GET("/containers/exits", a.getContainerExits)
+	api.GET("/options", a.getOptions)
+
+	return a
+}
+
+// requireToken disables the status endpoints if no API token is configured.
+func (a *agentAPIServer) requireToken(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if a.opts.APIToken == "" {
+			return echo.NewHTTPError(http.StatusForbidden, "agent API token is not configured")
+		}
+		return next(c)
+	}
+}
+
+// validateToken authenticates requests to the status endpoints by the configured API token.
+func (a *agentAPIServer) validateToken(token string, _ echo.Context) (bool, error) {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.opts.APIToken)) == 1, nil
+}
+
+// getHealth reports the agent is alive, for liveness probes. It is healthy even while
+// disconnected from the master, since the agent exits once it gives up reconnecting.
+func (a *agentAPIServer) getHealth(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]any{
+		"status":           "ok",
+		"master_connected": a.status.masterStatus().Connected,
+	})
+}
+
+func (a *agentAPIServer) getMaster(c echo.Context) error {
+	return c.JSON(http.StatusOK, a.status.masterStatus())
+}
+
+func (a *agentAPIServer) getDevices(c echo.Context) error {
+	devices, labels := a.status.deviceStatus()
+	return c.JSON(http.StatusOK, map[string]any{
+		"devices": devices,
+		"labels":  labels,
+	})
+}
+
+func (a *agentAPIServer) getContainers(c echo.Context) error {
+	manager := a.status.containerManager()
+	if manager == nil {
+		return c.JSON(http.StatusOK, []containers.ContainerStatus{})
+	}
+	return c.JSON(http.StatusOK, manager.Containers())
+}
+
+func (a *agentAPIServer) getContainerExits(c echo.Context) error {
+	manager := a.status.containerManager()
+	if manager == nil {
+		return c.JSON(http.StatusOK, []aproto.ContainerStateChanged{})
+	}
+	return c.JSON(http.StatusOK, manager.RecentExits())
+}
+
+func (a *agentAPIServer) getOptions(c echo.Context) error {
+	return c.JSON(http.StatusOK, a.opts.Redacted())
 }
 
 func (a *agentAPIServer) serve() error {
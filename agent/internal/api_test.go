@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestAgentAPIServer(t *testing.T) {
+	status := &agentStatus{}
+	status.connected(false)
+	status.setup([]device.Device{{ID: 0, Type: device.CPU}}, map[string]string{"rack": "a1"}, nil)
+
+	get := func(api *agentAPIServer, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		api.server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	api := newAgentAPIServer(options.Options{APIToken: "secret"}, status)
+	rec := get(api, "/health", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"status": "ok", "master_connected": true}`, rec.Body.String())
+
+	require.Equal(t, http.StatusBadRequest, get(api, "/api/v1/master", "").Code)
+	require.Equal(t, http.StatusUnauthorized, get(api, "/api/v1/master", "wrong").Code)
+
+	rec = get(api, "/api/v1/master", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var master masterStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &master))
+	require.True(t, master.Connected)
+	require.NotNil(t, master.LastConnected)
+
+	rec = get(api, "/api/v1/devices", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"rack":"a1"`)
+
+	rec = get(api, "/api/v1/containers", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[]`, rec.Body.String())
+
+	rec = get(api, "/api/v1/options", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"api_token":"********"`)
+
+	// The status endpoints are disabled without a token.
+	api = newAgentAPIServer(options.Options{}, status)
+	require.Equal(t, http.StatusForbidden, get(api, "/api/v1/master", "secret").Code)
+	require.Equal(t, http.StatusOK, get(api, "/health", "").Code)
+}
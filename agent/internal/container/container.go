@@ -11,17 +11,25 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/determined-ai/determined/agent/internal/hooks"
 	"github.com/determined-ai/determined/agent/pkg/docker"
 	"github.com/determined-ai/determined/agent/pkg/events"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/model"
+	opentelemetry "github.com/determined-ai/determined/master/pkg/opentelemetry"
 	"github.com/determined-ai/determined/master/pkg/syncx/errgroupx"
 	"github.com/determined-ai/determined/master/pkg/syncx/waitgroupx"
 )
 
+var tracer = otel.Tracer("determined-agent/container")
+
 // Container is a layer for managing a single Docker container. It can be constructed by launching
 // a new container or reattaching an existing one. Once constructed, it provides an interface to
 // interact with a running container.
@@ -31,11 +39,15 @@ type Container struct {
 	allocationID model.AllocationID
 	spec         *cproto.Spec
 	devices      []device.Device
+	image        string
+	// traceCtx carries the trace of the container's allocation, which launch spans continue.
+	traceCtx context.Context
 
 	// System dependencies. Also set in initialization and never modified after.
 	log      *logrus.Entry
 	cruntime ContainerRuntime
 	pub      events.Publisher[Event]
+	hooks    *hooks.Hooks
 
 	// Internal state. Access should be protected.
 	mu       sync.RWMutex
@@ -53,18 +65,22 @@ func Start(
 	req aproto.StartContainer,
 	cl ContainerRuntime,
 	pub events.Publisher[Event],
+	h *hooks.Hooks,
 ) *Container {
 	c := &Container{
 		containerID:  req.Container.ID,
 		allocationID: hackAllocationID(&req.Spec),
 		spec:         &req.Spec,
 		devices:      req.Container.Devices,
+		image:        req.Spec.RunSpec.ContainerConfig.Image,
+		traceCtx:     opentelemetry.ExtractTraceContext(context.Background(), req.TraceContext),
 		log: logrus.WithFields(logrus.Fields{
 			"component": "container",
 			"cproto-id": req.Container.ID,
 		}),
 		cruntime: cl,
 		pub:      pub,
+		hooks:    h,
 		state:    req.Container.State,
 		signals:  make(chan syscall.Signal),
 		done:     make(chan struct{}),
@@ -90,12 +106,14 @@ func Reattach(
 	container cproto.Container,
 	cl ContainerRuntime,
 	pub events.Publisher[Event],
+	h *hooks.Hooks,
 ) *Container {
 	c := &Container{
 		// TODO(Brad): We should be recovering the allocation ID for logging.
 		containerID: container.ID,
 		// We don't need the spec because we only reattach launched containers.
-		devices: container.Devices,
+		devices:  container.Devices,
+		traceCtx: context.Background(),
 		log: logrus.WithFields(logrus.Fields{
 			"component":  "container",
 			"cproto-id":  container.ID,
@@ -103,6 +121,7 @@ func Reattach(
 		}),
 		cruntime: cl,
 		pub:      pub,
+		hooks:    h,
 		state:    container.State,
 		signals:  make(chan syscall.Signal, 16), // Not infinite, but large enough to not drop often.
 		done:     make(chan struct{}),
@@ -130,6 +149,11 @@ func (c *Container) Summary() cproto.Container {
 	return c.summary()
 }
 
+// AllocationID returns the ID of the allocation the container belongs to, if it is known.
+func (c *Container) AllocationID() model.AllocationID {
+	return c.allocationID
+}
+
 // Detach the monitoring loops without affecting the Docker container.
 func (c *Container) Detach() {
 	c.log.Trace("detach called")
@@ -196,11 +220,14 @@ func (c *Container) run(parent context.Context) (err error) {
 		if err = c.transition(ctx, cproto.Pulling, nil, nil); err != nil {
 			return err
 		}
-		if err = c.cruntime.PullImage(ctx, docker.PullImage{
+		endSpan := c.startSpan("container.pull")
+		err = c.cruntime.PullImage(ctx, docker.PullImage{
 			Name:      c.spec.RunSpec.ContainerConfig.Image,
 			Registry:  c.spec.PullSpec.Registry,
 			ForcePull: c.spec.PullSpec.ForcePull,
-		}, c.shimDockerEvents()); err != nil {
+		}, c.shimDockerEvents())
+		endSpan(err)
+		if err != nil {
 			return fmt.Errorf("pulling container image: %w", err)
 		}
 
@@ -209,12 +236,19 @@ func (c *Container) run(parent context.Context) (err error) {
 			return err
 		}
 
+		c.log.Trace("running pre-start hook")
+		if failure := c.hooks.PreContainerStart(ctx, c.hookContainer()); failure != nil {
+			return failure
+		}
+
+		endSpan = c.startSpan("container.create")
 		runtimeID, err := c.cruntime.CreateContainer(
 			ctx,
 			c.containerID,
 			c.spec.RunSpec,
 			c.shimDockerEvents(),
 		)
+		endSpan(err)
 		if err != nil {
 			return fmt.Errorf("creating container: %w", err)
 		}
@@ -233,7 +267,9 @@ func (c *Container) run(parent context.Context) (err error) {
 		}()
 
 		c.log.WithField("docker-id", runtimeID).Trace("starting container")
+		endSpan = c.startSpan("container.start")
 		dc, err := c.cruntime.RunContainer(ctx, parent, runtimeID, c.shimDockerEvents())
+		endSpan(err)
 		if err != nil {
 			return fmt.Errorf("starting container: %w", err)
 		}
@@ -274,6 +310,7 @@ func (c *Container) reattach(ctx context.Context) error {
 	switch dc, exitCode, err := c.cruntime.ReattachContainer(
 		ctx,
 		c.containerID,
+		c.shimDockerEvents(),
 	); {
 	case errors.Is(err, context.Canceled):
 		return err
@@ -290,6 +327,9 @@ func (c *Container) reattach(ctx context.Context) error {
 
 func (c *Container) wait(ctx context.Context, dc *docker.Container) error {
 	c.log.Trace("in monitoring loop")
+	// killed is whether we killed the container, in which case it wasn't killed by running out of
+	// memory.
+	var killed bool
 	for {
 		select {
 		case exit := <-dc.ContainerWaiter.Waiter:
@@ -297,7 +337,13 @@ func (c *Container) wait(ctx context.Context, dc *docker.Container) error {
 			if exit.Error != nil {
 				return fmt.Errorf("receiving container exit: %s", exit.Error.Message)
 			}
-			return aproto.NewContainerExit(aproto.ExitCode(exit.StatusCode))
+			code := aproto.ExitCode(exit.StatusCode)
+			if code != aproto.SuccessExitCode && !killed && dc.ContainerWaiter.OOMKilled != nil &&
+				dc.ContainerWaiter.OOMKilled(ctx, code) {
+				c.log.Infof("container was killed because it ran out of memory")
+				return aproto.NewContainerOOMKilled(code)
+			}
+			return aproto.NewContainerExit(code)
 
 		case err := <-dc.ContainerWaiter.Errs:
 			c.log.Trace("container waiter failed")
@@ -305,6 +351,7 @@ func (c *Container) wait(ctx context.Context, dc *docker.Container) error {
 
 		case signal := <-c.signals:
 			c.log.Tracef("container signaled: %s", signal)
+			killed = killed || signal == syscall.SIGKILL
 			if err := c.cruntime.SignalContainer(ctx, dc.ContainerInfo.ID, signal); err != nil {
 				c.log.WithError(err).Errorf(
 					"failed to signal %v with %v", dc.ContainerInfo.ID, signal,
@@ -342,12 +389,40 @@ func (c *Container) finalize(ctx context.Context, err error) {
 		stop = aproto.ContainerError(aproto.TaskError, err)
 	}
 
+	c.log.Trace("running post-exit hook")
+	c.hooks.PostContainerExit(ctx, c.hookContainer(), stop)
+
 	if err := c.terminated(ctx, stop); err != nil {
 		c.log.WithError(err).Error("finalizing container")
 	}
 	return
 }
 
+// startSpan starts a span for a step of launching the container, continuing the trace of its
+// allocation. The returned func ends the span, recording the step's error if it failed.
+func (c *Container) startSpan(name string) func(error) {
+	_, span := tracer.Start(c.traceCtx, name, trace.WithAttributes(
+		opentelemetry.AllocationIDKey.String(string(c.allocationID)),
+		attribute.String("determined.container_id", string(c.containerID)),
+	))
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func (c *Container) hookContainer() hooks.Container {
+	return hooks.Container{
+		ID:           c.containerID,
+		AllocationID: c.allocationID,
+		Image:        c.image,
+		Devices:      c.devices,
+	}
+}
+
 func (c *Container) summary() cproto.Container {
 	return cproto.Container{
 		ID:      c.containerID,
@@ -17,6 +17,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/internal/hooks"
+	"github.com/determined-ai/determined/agent/internal/options"
 	"github.com/determined-ai/determined/agent/pkg/docker"
 	"github.com/determined-ai/determined/agent/pkg/events"
 	"github.com/determined-ai/determined/master/pkg/aproto"
@@ -152,7 +154,7 @@ func TestContainer(t *testing.T) {
 						HostConfig: dcontainer.HostConfig{AutoRemove: true},
 					},
 				},
-			}, cl, events.NilPublisher[container.Event]{})
+			}, cl, events.NilPublisher[container.Event]{}, hooks.New(options.Options{}))
 			defer c.Stop()
 
 			t.Log("setup canceler")
@@ -195,7 +197,9 @@ func TestContainer(t *testing.T) {
 				subg.Wait()
 
 				t.Log("reattaching container")
-				c = container.Reattach(c.Summary(), cl, events.NilPublisher[container.Event]{})
+				c = container.Reattach(
+					c.Summary(), cl, events.NilPublisher[container.Event]{}, hooks.New(options.Options{}),
+				)
 				exit = c.Wait()
 			}
 
@@ -290,7 +294,7 @@ func TestContainerStatus(t *testing.T) {
 						HostConfig: dcontainer.HostConfig{AutoRemove: true},
 					},
 				},
-			}, cl, events.NilPublisher[container.Event]{})
+			}, cl, events.NilPublisher[container.Event]{}, hooks.New(options.Options{}))
 			defer c.Stop()
 			containerCh <- c
 		}()
@@ -18,6 +18,7 @@ type ContainerRuntime interface {
 	ReattachContainer(
 		ctx context.Context,
 		id cproto.ID,
+		p events.Publisher[docker.Event],
 	) (*docker.Container, *aproto.ExitCode, error)
 
 	PullImage(ctx context.Context, req docker.PullImage, p events.Publisher[docker.Event]) error
@@ -44,3 +45,11 @@ type ContainerRuntime interface {
 
 	ListRunningContainers(ctx context.Context, fs filters.Args) (map[cproto.ID]types.Container, error)
 }
+
+// ImageCache is implemented by container runtimes whose images the agent can list and remove, so
+// that it can report the images cached on the host and evict the least recently used ones.
+type ImageCache interface {
+	ListImages(ctx context.Context) ([]*types.ImageSummary, int64, error)
+
+	RemoveImage(ctx context.Context, id string) error
+}
@@ -14,12 +14,14 @@ import (
 	"golang.org/x/sys/unix"
 
 	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/internal/hooks"
 	"github.com/determined-ai/determined/agent/internal/options"
 	"github.com/determined-ai/determined/agent/pkg/docker"
 	"github.com/determined-ai/determined/agent/pkg/events"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/syncx/waitgroupx"
 )
 
@@ -30,6 +32,12 @@ const (
 	RecentExitsCacheSize = 32
 )
 
+// ContainerStatus describes a container being managed, for the agent's local API.
+type ContainerStatus struct {
+	cproto.Container
+	AllocationID model.AllocationID `json:"allocation_id,omitempty"`
+}
+
 // Manager manages containers. It is able to start and signal them and tracks some updates to their
 // state.
 type Manager struct {
@@ -42,6 +50,7 @@ type Manager struct {
 	log      *log.Entry
 	cruntime container.ContainerRuntime
 	pub      events.Publisher[container.Event]
+	hooks    *hooks.Hooks
 
 	// Internal state. Access should be protected.
 	containers  map[cproto.ID]*container.Container
@@ -65,6 +74,7 @@ func New(
 		log:         log.WithField("component", "container-manager"),
 		cruntime:    cl,
 		pub:         pub,
+		hooks:       hooks.New(opts),
 		containers:  make(map[cproto.ID]*container.Container),
 		recentExits: ring.New(RecentExitsCacheSize),
 		wg:          waitgroupx.WithContext(context.Background()), // Manager-scoped group.
@@ -210,7 +220,7 @@ func (m *Manager) StartContainer(ctx context.Context, req aproto.StartContainer)
 		m.mu.Unlock()
 		return fmt.Errorf("container already created: %s", req.Container.ID)
 	}
-	c := container.Start(req, m.cruntime, m.pub)
+	c := container.Start(req, m.cruntime, m.pub, m.hooks)
 	m.containers[req.Container.ID] = c
 	m.mu.Unlock()
 
@@ -277,6 +287,36 @@ func (m *Manager) NumContainers() int {
 	return len(m.containers)
 }
 
+// Containers returns the status of the containers being managed.
+func (m *Manager) Containers() []ContainerStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]ContainerStatus, 0, len(m.containers))
+	for _, c := range m.containers {
+		result = append(result, ContainerStatus{
+			Container:    c.Summary(),
+			AllocationID: c.AllocationID(),
+		})
+	}
+	return result
+}
+
+// RecentExits returns the most recent exits of the containers that were managed, newest first.
+func (m *Manager) RecentExits() []aproto.ContainerStateChanged {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := []aproto.ContainerStateChanged{}
+	m.recentExits.Do(func(v any) {
+		if v == nil {
+			return
+		}
+		result = append(result, *v.(*aproto.ContainerStateChanged))
+	})
+	return result
+}
+
 func (m *Manager) reattachContainer(
 	ctx context.Context, containerPrevState cproto.Container, containerInfo types.Container,
 ) (*cproto.Container, error) {
@@ -305,7 +345,7 @@ func (m *Manager) reattachContainer(
 		c.Signal(ctx, syscall.SIGKILL)
 		return nil, errors.New(errorMsg)
 	}
-	c := container.Reattach(*containerCurrState, m.cruntime, m.pub)
+	c := container.Reattach(*containerCurrState, m.cruntime, m.pub, m.hooks)
 	m.containers[cID] = c
 	m.mu.Unlock()
 
@@ -1,11 +1,17 @@
 package containers
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/require"
 
+	"github.com/determined-ai/determined/agent/internal/detect"
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
 )
 
 func TestAddProxyInfo(t *testing.T) {
@@ -78,3 +84,25 @@ func TestAddProxyInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectPluginDevices(t *testing.T) {
+	discover := filepath.Join(t.TempDir(), "discover")
+	require.NoError(t, os.WriteFile(discover, []byte(`#!/bin/sh
+echo '[
+  {"id": 0, "uuid": "npu-a", "type": "npu",
+   "paths": ["/dev/npu0"], "env": {"NPU_VISIBLE_DEVICES": "0", "NPU_MODE": "full"}},
+  {"id": 1, "uuid": "npu-b", "type": "npu",
+   "paths": ["/dev/npu1"], "env": {"NPU_VISIBLE_DEVICES": "1"}}
+]'
+`), 0o700)) //nolint:gosec
+	devices, err := detect.Detect("plugin", "agent", "", 0, []string{discover})
+	require.NoError(t, err)
+
+	var runSpec cproto.RunSpec
+	injectPluginDevices(cproto.Container{Devices: []device.Device{devices[1], devices[0]}}, &runSpec)
+	require.Equal(t, []string{"NPU_MODE=full", "NPU_VISIBLE_DEVICES=1,0"}, runSpec.ContainerConfig.Env)
+	require.Equal(t, []dcontainer.DeviceMapping{
+		{PathOnHost: "/dev/npu1", PathInContainer: "/dev/npu1", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/npu0", PathInContainer: "/dev/npu0", CgroupPermissions: "rwm"},
+	}, runSpec.HostConfig.Devices)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 
 	"github.com/determined-ai/determined/agent/internal/container"
 	"github.com/determined-ai/determined/agent/internal/detect"
@@ -53,6 +54,7 @@ func overwriteSpec(
 			return cproto.Spec{}, err
 		}
 	}
+	injectPluginDevices(cont, &spec.RunSpec)
 
 	spec.RunSpec.HostConfig.LogConfig = dcontainer.LogConfig{}
 
@@ -141,6 +143,39 @@ func injectRocmDeviceRequests(cont cproto.Container, hostConfig *dcontainer.Host
 	return nil
 }
 
+func injectPluginDevices(cont cproto.Container, runSpec *cproto.RunSpec) {
+	var names []string
+	values := map[string][]string{}
+	for _, d := range cont.Devices {
+		pluginDevice := detect.GetPluginDeviceByUUID(d.UUID)
+		if pluginDevice == nil {
+			continue
+		}
+
+		for _, p := range pluginDevice.Paths {
+			runSpec.HostConfig.Devices = append(
+				runSpec.HostConfig.Devices, dcontainer.DeviceMapping{
+					PathOnHost:        p,
+					PathInContainer:   p,
+					CgroupPermissions: "rwm",
+				})
+		}
+
+		for _, name := range maps.Keys(pluginDevice.Env) {
+			if _, ok := values[name]; !ok {
+				names = append(names, name)
+			}
+			values[name] = append(values[name], pluginDevice.Env[name])
+		}
+	}
+
+	slices.Sort(names)
+	for _, name := range names {
+		runSpec.ContainerConfig.Env = append(
+			runSpec.ContainerConfig.Env, name+"="+strings.Join(values[name], ","))
+	}
+}
+
 func containerEnv(cont cproto.Container) []string {
 	var slotIds []string
 	for _, d := range cont.Devices {
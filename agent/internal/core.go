@@ -4,20 +4,22 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"os/exec"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/determined-ai/determined/agent/internal/hooks"
 	"github.com/determined-ai/determined/agent/internal/options"
+	opentelemetry "github.com/determined-ai/determined/master/pkg/opentelemetry"
 	"github.com/determined-ai/determined/master/pkg/syncx/errgroupx"
 )
 
 // Run runs a new agent system and actor with the provided options.
 func Run(parent context.Context, version string, opts options.Options) error {
-	ctx, stop := signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
+	signaled, stop := signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
 	printableConfig, err := opts.Printable()
@@ -26,25 +28,51 @@ func Run(parent context.Context, version string, opts options.Options) error {
 	}
 	log.Infof("agent configuration: %s", printableConfig)
 
-	wg := errgroupx.WithContext(ctx)
+	if opts.Telemetry.OtelEnabled {
+		opentelemetry.ConfigureOtel(opts.Telemetry.OtelExportedOtlpEndpoint, "determined-agent")
+	}
+
+	wg := errgroupx.WithContext(parent)
+	status := &agentStatus{}
+	h := hooks.New(opts)
+
+	// The pre_shutdown hook is run once, either before a signal cancels the agent, so that it runs
+	// while the agent still manages its containers, or after the agent exits on its own.
+	var preShutdown sync.Once
+	runPreShutdown := func(ctx context.Context) {
+		preShutdown.Do(func() { h.PreShutdown(ctx) })
+	}
 
 	log.Trace("starting main agent process")
 	wg.Go(func(ctx context.Context) error {
 		defer wg.Cancel()
 
-		err := NewAgent(ctx, version, opts).Wait()
+		err := newAgent(ctx, version, opts, status).Wait()
 		if _, ok := err.(masterConnectionError); ok {
-			onConnectionLost(ctx, opts)
+			h.OnConnectionLost(ctx)
 		}
+		// The context is canceled if another part of the agent failed, which shouldn't kill the
+		// hook.
+		runPreShutdown(context.WithoutCancel(ctx))
 		return err
 	})
 
+	wg.Go(func(ctx context.Context) error {
+		select {
+		case <-signaled.Done():
+			runPreShutdown(context.WithoutCancel(ctx))
+			wg.Cancel()
+		case <-ctx.Done():
+		}
+		return nil
+	})
+
 	if opts.APIEnabled {
 		log.Trace("starting agent apiserver")
 		wg.Go(func(ctx context.Context) error {
 			defer wg.Cancel()
 
-			api := newAgentAPIServer(opts)
+			api := newAgentAPIServer(opts, status)
 			wg.Go(func(ctx context.Context) error {
 				<-ctx.Done()
 				return api.close()
@@ -63,17 +91,3 @@ func Run(parent context.Context, version string, opts options.Options) error {
 
 	return wg.Wait()
 }
-
-func onConnectionLost(ctx context.Context, opts options.Options) {
-	cmd := opts.Hooks.OnConnectionLost
-	if len(cmd) == 0 {
-		return
-	}
-	out, err := exec.CommandContext(ctx, cmd[0], cmd[1:]...).CombinedOutput() //nolint:gosec
-	if err != nil {
-		log.
-			WithError(err).
-			WithField("output", string(out)).
-			Error("error running connection failure hook")
-	}
-}
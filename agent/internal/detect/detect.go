@@ -15,8 +15,10 @@ import (
 )
 
 // Detect the devices available. If artificial devices are configured, prefers those, otherwise,
-// we detect cuda, rocm, cpu (or no) devices based on the configured slot type.
-func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]device.Device, error) {
+// we detect cuda, rocm, cpu, plugin (or no) devices based on the configured slot type.
+func Detect(
+	slotType, agentID, visibleGPUs string, artificialSlots int, pluginCommand []string,
+) ([]device.Device, error) {
 	// Log detected nvidia version.
 	v, err := getNvidiaVersion()
 	if err != nil {
@@ -75,6 +77,11 @@ func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]devic
 		if err != nil {
 			return nil, err
 		}
+	case slotType == "plugin":
+		detected, err = detectPluginDevices(pluginCommand)
+		if err != nil {
+			return nil, errors.Wrap(err, "error while gathering device info through device plugin")
+		}
 	case slotType == "auto":
 		detected, err = detectCudaGPUs(visibleGPUs)
 		if err != nil {
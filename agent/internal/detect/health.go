@@ -0,0 +1,209 @@
+package detect
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const (
+	healthCommandTimeout = 30 * time.Second
+	maxHealthReasonLen   = 512
+)
+
+var (
+	checkCudaGPUsHealthArgs = []string{
+		"nvidia-smi", "--query-gpu=uuid,ecc.errors.uncorrected.volatile.total",
+		"--format=csv,noheader",
+	}
+	checkRocmGPUsHealthArgs = []string{"rocm-smi", "--showuniqueid", "--json"}
+)
+
+// CheckHealth probes the health of the devices. CUDA and ROCm devices are unhealthy if nvidia-smi
+// or rocm-smi no longer report them, and CUDA devices are also unhealthy if they have uncorrected
+// ECC errors. The devices that pass these checks are then passed one at a time to the health
+// command, if any, which is run with DET_DEVICE_ID, DET_DEVICE_UUID and DET_DEVICE_TYPE set and
+// marks the device unhealthy by exiting with a non-zero status.
+func CheckHealth(
+	ctx context.Context, devices []device.Device, command []string,
+) []aproto.DeviceHealth {
+	var cudaReasons, rocmReasons map[string]string
+	health := make([]aproto.DeviceHealth, 0, len(devices))
+	for _, d := range devices {
+		var reason string
+		switch d.Type {
+		case device.CUDA:
+			if cudaReasons == nil {
+				cudaReasons = checkCudaGPUsHealth(devices)
+			}
+			reason = cudaReasons[d.UUID]
+		case device.ROCM:
+			if rocmReasons == nil {
+				rocmReasons = checkRocmGPUsHealth(devices)
+			}
+			reason = rocmReasons[d.UUID]
+		}
+		if reason == "" && len(command) > 0 {
+			reason = runHealthCommand(ctx, d, command)
+		}
+		health = append(health, aproto.DeviceHealth{
+			Device:  d,
+			Healthy: reason == "",
+			Reason:  reason,
+		})
+	}
+	return health
+}
+
+// checkCudaGPUsHealth returns the reasons the unhealthy CUDA devices are unhealthy, by UUID.
+func checkCudaGPUsHealth(devices []device.Device) map[string]string {
+	// #nosec G204
+	out, err := exec.Command(checkCudaGPUsHealthArgs[0], checkCudaGPUsHealthArgs[1:]...).
+		CombinedOutput()
+	if execError, ok := err.(*exec.Error); ok {
+		return unhealthyDevices(devices, device.CUDA, fmt.Sprintf("nvidia-smi failed: %s", execError))
+	}
+	reasons := parseCudaGPUsHealth(devices, string(out))
+
+	// MIG instances aren't listed by --query-gpu, but are listed by -L along with their GPUs.
+	var migOut []byte
+	for _, d := range devices {
+		if d.Type != device.CUDA || !strings.HasPrefix(d.UUID, "MIG-") {
+			continue
+		}
+		if migOut == nil {
+			// #nosec G204
+			migOut, err = exec.Command(detectCudaDevices[0], detectCudaDevices[1:]...).CombinedOutput()
+			if err != nil {
+				log.WithError(err).WithField("output", string(migOut)).Warn(
+					"error while executing nvidia-smi to check MIG instances")
+			}
+		}
+		if !strings.Contains(string(migOut), d.UUID) {
+			reasons[d.UUID] = "MIG instance not reported by nvidia-smi"
+		} else {
+			delete(reasons, d.UUID)
+		}
+	}
+	return reasons
+}
+
+// parseCudaGPUsHealth parses the output of checkCudaGPUsHealthArgs. A device that is missing from
+// the output is unhealthy, with any error nvidia-smi printed as the reason.
+func parseCudaGPUsHealth(devices []device.Device, out string) map[string]string {
+	ecc := map[string]string{}
+	var errs []string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		fields := strings.Split(line, ",")
+		if len(fields) != 2 || !strings.HasPrefix(strings.TrimSpace(fields[0]), "GPU-") {
+			if line != "" {
+				errs = append(errs, line)
+			}
+			continue
+		}
+		ecc[strings.TrimSpace(fields[0])] = strings.TrimSpace(fields[1])
+	}
+
+	reasons := map[string]string{}
+	for _, d := range devices {
+		if d.Type != device.CUDA {
+			continue
+		}
+		count, ok := ecc[d.UUID]
+		switch {
+		case !ok && len(errs) > 0:
+			reasons[d.UUID] = truncateReason("GPU not reported by nvidia-smi: " +
+				strings.Join(errs, "; "))
+		case !ok:
+			reasons[d.UUID] = "GPU not reported by nvidia-smi"
+		default:
+			// ECC counts are "[N/A]" on GPUs without ECC support.
+			if n, err := strconv.Atoi(count); err == nil && n > 0 {
+				reasons[d.UUID] = fmt.Sprintf("GPU has %d uncorrected ECC errors", n)
+			}
+		}
+	}
+	return reasons
+}
+
+// checkRocmGPUsHealth returns the reasons the unhealthy ROCm devices are unhealthy, by UUID.
+func checkRocmGPUsHealth(devices []device.Device) map[string]string {
+	// #nosec G204
+	out, err := exec.Command(checkRocmGPUsHealthArgs[0], checkRocmGPUsHealthArgs[1:]...).Output()
+	if err != nil {
+		return unhealthyDevices(devices, device.ROCM, truncateReason(
+			fmt.Sprintf("rocm-smi failed: %s: %s", err, strings.TrimSpace(string(out)))))
+	}
+	parsed, err := parseRocmSmi(out)
+	if err != nil {
+		return unhealthyDevices(devices, device.ROCM, fmt.Sprintf("parsing rocm-smi output: %s", err))
+	}
+
+	reported := map[string]bool{}
+	for _, d := range parsed {
+		reported[d.UUID] = true
+	}
+	reasons := map[string]string{}
+	for _, d := range devices {
+		if d.Type == device.ROCM && !reported[d.UUID] {
+			reasons[d.UUID] = "GPU not reported by rocm-smi"
+		}
+	}
+	return reasons
+}
+
+func unhealthyDevices(devices []device.Device, t device.Type, reason string) map[string]string {
+	reasons := map[string]string{}
+	for _, d := range devices {
+		if d.Type == t {
+			reasons[d.UUID] = reason
+		}
+	}
+	return reasons
+}
+
+// runHealthCommand runs the health command for the device and returns why the device is
+// unhealthy, or an empty string if it is healthy.
+func runHealthCommand(ctx context.Context, d device.Device, command []string) string {
+	ctx, cancel := context.WithTimeout(ctx, healthCommandTimeout)
+	defer cancel()
+
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("DET_DEVICE_ID=%d", d.ID),
+		fmt.Sprintf("DET_DEVICE_UUID=%s", d.UUID),
+		fmt.Sprintf("DET_DEVICE_TYPE=%s", d.Type),
+	)
+	out, err := cmd.CombinedOutput()
+	switch {
+	case err == nil:
+		return ""
+	case ctx.Err() == context.DeadlineExceeded:
+		return fmt.Sprintf("health command timed out after %s", healthCommandTimeout)
+	case len(strings.TrimSpace(string(out))) > 0:
+		return truncateReason(fmt.Sprintf("health command failed: %s: %s",
+			err, strings.TrimSpace(string(out))))
+	default:
+		return fmt.Sprintf("health command failed: %s", err)
+	}
+}
+
+func truncateReason(reason string) string {
+	if len(reason) > maxHealthReasonLen {
+		return reason[:maxHealthReasonLen] + "..."
+	}
+	return reason
+}
@@ -0,0 +1,61 @@
+package detect
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestParseCudaGPUsHealth(t *testing.T) {
+	devices := []device.Device{
+		{ID: 0, UUID: "GPU-aaa", Type: device.CUDA},
+		{ID: 1, UUID: "GPU-bbb", Type: device.CUDA},
+		{ID: 2, UUID: "GPU-ccc", Type: device.CUDA},
+		{ID: 3, UUID: "GPU-ddd", Type: device.CUDA},
+	}
+
+	reasons := parseCudaGPUsHealth(devices, `GPU-aaa, 0
+GPU-bbb, [N/A]
+GPU-ccc, 2
+`)
+	assert.DeepEqual(t, reasons, map[string]string{
+		"GPU-ccc": "GPU has 2 uncorrected ECC errors",
+		"GPU-ddd": "GPU not reported by nvidia-smi",
+	})
+
+	reasons = parseCudaGPUsHealth(devices, `GPU-aaa, 0
+Unable to determine the device handle for GPU 0000:3B:00.0: GPU is lost.
+GPU-ccc, 0
+GPU-ddd, 0
+`)
+	assert.DeepEqual(t, reasons, map[string]string{
+		"GPU-bbb": "GPU not reported by nvidia-smi: " +
+			"Unable to determine the device handle for GPU 0000:3B:00.0: GPU is lost.",
+	})
+}
+
+func TestCheckHealthCommand(t *testing.T) {
+	devices := []device.Device{
+		{ID: 0, UUID: "healthy", Type: device.CPU},
+		{ID: 1, UUID: "unhealthy", Type: device.CPU},
+	}
+	command := []string{
+		"sh", "-c", `if [ "$DET_DEVICE_ID" = 1 ]; then echo "$DET_DEVICE_UUID"; exit 1; fi`,
+	}
+
+	health := CheckHealth(context.Background(), devices, command)
+	assert.Equal(t, len(health), 2)
+	assert.Equal(t, health[0].Device, devices[0])
+	assert.Assert(t, health[0].Healthy)
+	assert.Equal(t, health[0].Reason, "")
+	assert.Equal(t, health[1].Device, devices[1])
+	assert.Assert(t, !health[1].Healthy)
+	assert.Assert(t, strings.HasSuffix(health[1].Reason, ": unhealthy"), health[1].Reason)
+
+	health = CheckHealth(context.Background(), devices, nil)
+	assert.Assert(t, health[0].Healthy && health[1].Healthy)
+}
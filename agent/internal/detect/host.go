@@ -0,0 +1,19 @@
+package detect
+
+import (
+	"runtime"
+
+	"github.com/shirou/gopsutil/mem"
+	"github.com/sirupsen/logrus"
+)
+
+// HostResources returns the number of CPUs and bytes of memory of the agent's host, which the
+// master reserves for the containers that request them. The memory is 0 if it can't be detected.
+func HostResources() (cpus int, memory int64) {
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		logrus.WithError(err).Warn("failed to detect host memory, memory requests will be ignored")
+		return runtime.NumCPU(), 0
+	}
+	return runtime.NumCPU(), int64(vm.Total)
+}
@@ -0,0 +1,64 @@
+package detect
+
+import (
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// Keys of the labels the agent detects about its host and devices.
+const (
+	ArchLabel          = "determined.ai/arch"
+	SlotTypeLabel      = "determined.ai/slot-type"
+	GPUModelLabel      = "determined.ai/gpu-model"
+	DriverVersionLabel = "determined.ai/driver-version"
+)
+
+// Labels returns the labels detected about the host and its devices: the architecture, the type
+// of the devices, the model of the GPUs if they are all the same and the version of their driver.
+// Configured labels are merged over them, so they can be overridden.
+func Labels(devices []device.Device, configured map[string]string) map[string]string {
+	labels := map[string]string{ArchLabel: runtime.GOARCH}
+	if len(devices) > 0 {
+		labels[SlotTypeLabel] = string(devices[0].Type)
+	}
+
+	model := ""
+	for _, d := range devices {
+		if !isGPU(d.Type) || (model != "" && d.Brand != model) {
+			model = ""
+			break
+		}
+		model = d.Brand
+	}
+	if model != "" {
+		labels[GPUModelLabel] = model
+	}
+
+	if len(devices) > 0 && isGPU(devices[0].Type) {
+		var version string
+		var err error
+		switch devices[0].Type {
+		case device.CUDA:
+			version, err = getNvidiaVersion()
+		case device.ROCM:
+			version, err = getRocmVersion()
+		}
+		if err != nil {
+			log.WithError(err).Warn("failed to detect driver version label")
+		} else if version != "" {
+			labels[DriverVersionLabel] = version
+		}
+	}
+
+	for k, v := range configured {
+		labels[k] = v
+	}
+	return labels
+}
+
+func isGPU(t device.Type) bool {
+	return t == device.CUDA || t == device.ROCM
+}
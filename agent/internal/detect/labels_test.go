@@ -0,0 +1,24 @@
+package detect
+
+import (
+	"runtime"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestLabels(t *testing.T) {
+	labels := Labels([]device.Device{
+		{ID: 0, Brand: "Intel Xeon", Type: device.CPU},
+	}, map[string]string{"rack": "a1", ArchLabel: "custom"})
+	assert.DeepEqual(t, labels, map[string]string{
+		ArchLabel:     "custom",
+		SlotTypeLabel: "cpu",
+		"rack":        "a1",
+	})
+
+	labels = Labels(nil, nil)
+	assert.DeepEqual(t, labels, map[string]string{ArchLabel: runtime.GOARCH})
+}
@@ -0,0 +1,99 @@
+package detect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// pluginDiscoveryTimeout is the time a device plugin's discovery command may run.
+const pluginDiscoveryTimeout = time.Minute
+
+// PluginDevice is a device discovered by a device plugin, along with how to expose it to the
+// containers it is allocated to.
+type PluginDevice struct {
+	ID    device.ID   `json:"id"`
+	UUID  string      `json:"uuid"`
+	Brand string      `json:"brand"`
+	Type  device.Type `json:"type"`
+	// Paths are the device files on the host that are mapped into the container, at the same path.
+	Paths []string `json:"paths"`
+	// Env are the environment variables set in the container. The values of a variable set by
+	// several of the container's devices are joined with commas, in the order of the devices.
+	Env map[string]string `json:"env"`
+}
+
+// Cache discovered devices for runtime lookups.
+var discoveredPluginDevices []PluginDevice
+
+// detectPluginDevices runs the device plugin's discovery command, which prints the devices as a
+// JSON list of PluginDevice to its standard output.
+func detectPluginDevices(command []string) ([]device.Device, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("no device plugin command configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginDiscoveryTimeout)
+	defer cancel()
+	// #nosec G204 // The command is configured by the agent's operator.
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running device plugin command: %w: %s", err, stderr.String())
+	}
+
+	discovered, err := parsePluginDevices(out)
+	if err != nil {
+		return nil, err
+	}
+	discoveredPluginDevices = discovered
+
+	result := make([]device.Device, 0, len(discovered))
+	for _, d := range discovered {
+		result = append(result, device.Device{ID: d.ID, Brand: d.Brand, UUID: d.UUID, Type: d.Type})
+	}
+	return result, nil
+}
+
+func parsePluginDevices(out []byte) ([]PluginDevice, error) {
+	var discovered []PluginDevice
+	if err := json.Unmarshal(out, &discovered); err != nil {
+		return nil, fmt.Errorf("parsing device plugin output: %w", err)
+	}
+
+	ids := map[device.ID]bool{}
+	uuids := map[string]bool{}
+	for _, d := range discovered {
+		switch {
+		case d.UUID == "":
+			return nil, fmt.Errorf("device plugin reported device %d without a uuid", d.ID)
+		case d.Type == device.ZeroSlot:
+			return nil, fmt.Errorf("device plugin reported device %s without a type", d.UUID)
+		case ids[d.ID]:
+			return nil, fmt.Errorf("device plugin reported duplicate device id %d", d.ID)
+		case uuids[d.UUID]:
+			return nil, fmt.Errorf("device plugin reported duplicate device uuid %s", d.UUID)
+		}
+		ids[d.ID] = true
+		uuids[d.UUID] = true
+	}
+	return discovered, nil
+}
+
+// GetPluginDeviceByUUID gets a PluginDevice by UUID from the singleton discovered plugin devices.
+func GetPluginDeviceByUUID(uuid string) *PluginDevice {
+	for _, d := range discoveredPluginDevices {
+		if d.UUID == uuid {
+			return &d
+		}
+	}
+
+	return nil
+}
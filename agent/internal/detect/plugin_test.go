@@ -0,0 +1,63 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const fakePluginOutput = `[
+  {"id": 0, "uuid": "npu-a", "brand": "Acme N1", "type": "npu",
+   "paths": ["/dev/npu0"], "env": {"NPU_VISIBLE_DEVICES": "0"}},
+  {"id": 1, "uuid": "npu-b", "brand": "Acme N1", "type": "npu",
+   "paths": ["/dev/npu1"], "env": {"NPU_VISIBLE_DEVICES": "1"}}
+]`
+
+func fakePlugin(t *testing.T, script string) []string {
+	path := filepath.Join(t.TempDir(), "discover")
+	assert.NilError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700)) //nolint:gosec
+	return []string{path}
+}
+
+func TestDetectPluginDevices(t *testing.T) {
+	command := fakePlugin(t, "cat <<EOF\n"+fakePluginOutput+"\nEOF\n")
+	devices, err := Detect("plugin", "agent", "", 0, command)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, devices, []device.Device{
+		{ID: 0, UUID: "npu-a", Brand: "Acme N1", Type: "npu"},
+		{ID: 1, UUID: "npu-b", Brand: "Acme N1", Type: "npu"},
+	})
+
+	d := GetPluginDeviceByUUID("npu-b")
+	assert.Assert(t, d != nil)
+	assert.DeepEqual(t, d.Paths, []string{"/dev/npu1"})
+	assert.DeepEqual(t, d.Env, map[string]string{"NPU_VISIBLE_DEVICES": "1"})
+	assert.Assert(t, GetPluginDeviceByUUID("npu-c") == nil)
+}
+
+func TestDetectPluginDevicesErrors(t *testing.T) {
+	_, err := Detect("plugin", "agent", "", 0, fakePlugin(t, "echo no devices >&2; exit 1\n"))
+	assert.ErrorContains(t, err, "no devices")
+
+	_, err = Detect("plugin", "agent", "", 0, fakePlugin(t, "echo '{}'\n"))
+	assert.ErrorContains(t, err, "parsing device plugin output")
+
+	for _, tc := range []struct {
+		output string
+		err    string
+	}{
+		{`[{"id": 0, "type": "npu"}]`, "without a uuid"},
+		{`[{"id": 0, "uuid": "npu-a"}]`, "without a type"},
+		{`[{"id": 0, "uuid": "npu-a", "type": "npu"}, {"id": 0, "uuid": "npu-b", "type": "npu"}]`,
+			"duplicate device id 0"},
+		{`[{"id": 0, "uuid": "npu-a", "type": "npu"}, {"id": 1, "uuid": "npu-a", "type": "npu"}]`,
+			"duplicate device uuid npu-a"},
+	} {
+		_, err := parsePluginDevices([]byte(tc.output))
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
@@ -0,0 +1,202 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// DefaultTimeout is the time a hook may run if no timeout is configured. The on_connection_lost
+	// hook, which predates the timeout, isn't limited unless a timeout is configured.
+	DefaultTimeout = time.Minute
+	maxOutputLen   = 512
+)
+
+// Environment variables set for the hooks, in addition to the agent's environment.
+const (
+	AgentIDEnvVar          = "DET_AGENT_ID"
+	ContainerIDEnvVar      = "DET_CONTAINER_ID"
+	AllocationIDEnvVar     = "DET_ALLOCATION_ID"
+	ContainerImageEnvVar   = "DET_CONTAINER_IMAGE"
+	ContainerDevicesEnvVar = "DET_CONTAINER_DEVICES"
+	ExitCodeEnvVar         = "DET_CONTAINER_EXIT_CODE"
+	FailureEnvVar          = "DET_CONTAINER_FAILURE"
+	DeviceIDsEnvVar        = "DET_DEVICE_IDS"
+	DeviceUUIDsEnvVar      = "DET_DEVICE_UUIDS"
+	DeviceTypeEnvVar       = "DET_DEVICE_TYPE"
+)
+
+// Container describes the container a container hook is run for.
+type Container struct {
+	ID           cproto.ID
+	AllocationID model.AllocationID
+	Image        string
+	Devices      []device.Device
+}
+
+// Hooks runs the commands configured to be run at points in the lifecycle of the agent and its
+// containers. Hooks that aren't configured are skipped.
+type Hooks struct {
+	opts    options.HooksOptions
+	agentID string
+	log     *logrus.Entry
+}
+
+// New returns the hooks configured for the agent.
+func New(opts options.Options) *Hooks {
+	return &Hooks{
+		opts:    opts.Hooks,
+		agentID: opts.AgentID,
+		log:     logrus.WithField("component", "hooks"),
+	}
+}
+
+// OnConnectionLost runs the on_connection_lost hook.
+func (h *Hooks) OnConnectionLost(ctx context.Context) {
+	h.runAndLog(ctx, "on_connection_lost", h.opts.OnConnectionLost, nil, 0)
+}
+
+// OnDevicesDetected runs the on_devices_detected hook with the detected devices.
+func (h *Hooks) OnDevicesDetected(ctx context.Context, devices []device.Device) {
+	var deviceType device.Type
+	if len(devices) > 0 {
+		deviceType = devices[0].Type
+	}
+	h.runAndLog(ctx, "on_devices_detected", h.opts.OnDevicesDetected, map[string]string{
+		DeviceIDsEnvVar:   deviceIDs(devices),
+		DeviceUUIDsEnvVar: deviceUUIDs(devices),
+		DeviceTypeEnvVar:  string(deviceType),
+	}, DefaultTimeout)
+}
+
+// PreShutdown runs the pre_shutdown hook.
+func (h *Hooks) PreShutdown(ctx context.Context) {
+	h.runAndLog(ctx, "pre_shutdown", h.opts.PreShutdown, nil, DefaultTimeout)
+}
+
+// PreContainerStart runs the pre_container_start hook for the container. If the hook fails and is
+// configured to fail the container, the returned failure describes why; otherwise the failure is
+// only logged.
+func (h *Hooks) PreContainerStart(ctx context.Context, c Container) *aproto.ContainerFailure {
+	err := h.run(ctx, h.opts.PreContainerStart, containerEnv(c), DefaultTimeout)
+	switch {
+	case err == nil:
+		return nil
+	case h.opts.FailContainerOnPreStartError:
+		return aproto.NewContainerFailure(
+			aproto.AgentError, fmt.Errorf("pre_container_start hook failed: %w", err),
+		)
+	default:
+		h.log.WithError(err).Warnf("pre_container_start hook failed for container %s", c.ID)
+		return nil
+	}
+}
+
+// PostContainerExit runs the post_container_exit hook for the container with how it stopped.
+func (h *Hooks) PostContainerExit(ctx context.Context, c Container, stop aproto.ContainerStopped) {
+	env := containerEnv(c)
+	if stop.Failure != nil {
+		env[FailureEnvVar] = stop.Failure.ErrMsg
+		if stop.Failure.ExitCode != nil {
+			env[ExitCodeEnvVar] = strconv.Itoa(int(*stop.Failure.ExitCode))
+		}
+	} else {
+		env[ExitCodeEnvVar] = "0"
+	}
+	h.runAndLog(ctx, "post_container_exit", h.opts.PostContainerExit, env, DefaultTimeout)
+}
+
+func (h *Hooks) runAndLog(
+	ctx context.Context, name string, command []string, env map[string]string,
+	defaultTimeout time.Duration,
+) {
+	if err := h.run(ctx, command, env, defaultTimeout); err != nil {
+		h.log.WithError(err).Errorf("%s hook failed", name)
+	}
+}
+
+// run runs the command with the environment variables set, in addition to the agent's, killing it
+// if it runs for longer than its timeout. Errors include the command's output.
+func (h *Hooks) run(
+	ctx context.Context, command []string, env map[string]string, defaultTimeout time.Duration,
+) error {
+	if len(command) == 0 {
+		return nil
+	}
+
+	timeout := h.timeout(defaultTimeout)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", AgentIDEnvVar, h.agentID))
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if len(output) > maxOutputLen {
+		output = output[:maxOutputLen] + "..."
+	}
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() == context.DeadlineExceeded:
+		return fmt.Errorf("timed out after %s", timeout)
+	case output != "":
+		return fmt.Errorf("%w: %s", err, output)
+	default:
+		return err
+	}
+}
+
+// timeout returns the configured timeout, or the default if none is configured. A timeout of 0
+// doesn't limit the hook.
+func (h *Hooks) timeout(defaultTimeout time.Duration) time.Duration {
+	if h.opts.Timeout > 0 {
+		return time.Duration(h.opts.Timeout) * time.Second
+	}
+	return defaultTimeout
+}
+
+func containerEnv(c Container) map[string]string {
+	return map[string]string{
+		ContainerIDEnvVar:      string(c.ID),
+		AllocationIDEnvVar:     string(c.AllocationID),
+		ContainerImageEnvVar:   c.Image,
+		ContainerDevicesEnvVar: deviceUUIDs(c.Devices),
+	}
+}
+
+func deviceIDs(devices []device.Device) string {
+	ids := make([]string, 0, len(devices))
+	for _, d := range devices {
+		ids = append(ids, strconv.Itoa(int(d.ID)))
+	}
+	return strings.Join(ids, ",")
+}
+
+func deviceUUIDs(devices []device.Device) string {
+	uuids := make([]string, 0, len(devices))
+	for _, d := range devices {
+		uuids = append(uuids, d.UUID)
+	}
+	return strings.Join(uuids, ",")
+}
@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+var testContainer = Container{
+	ID:           "container-1",
+	AllocationID: "allocation-1",
+	Image:        "determinedai/environments:tag",
+	Devices: []device.Device{
+		{ID: 0, UUID: "GPU-a", Type: device.CUDA},
+		{ID: 1, UUID: "GPU-b", Type: device.CUDA},
+	},
+}
+
+func TestPreContainerStart(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+	h := New(options.Options{AgentID: "agent-1", Hooks: options.HooksOptions{
+		PreContainerStart: []string{"sh", "-c", "env > " + out},
+	}})
+
+	require.Nil(t, h.PreContainerStart(context.Background(), testContainer))
+	env, err := os.ReadFile(out) //nolint:gosec
+	require.NoError(t, err)
+	require.Contains(t, string(env), "DET_AGENT_ID=agent-1\n")
+	require.Contains(t, string(env), "DET_CONTAINER_ID=container-1\n")
+	require.Contains(t, string(env), "DET_ALLOCATION_ID=allocation-1\n")
+	require.Contains(t, string(env), "DET_CONTAINER_IMAGE=determinedai/environments:tag\n")
+	require.Contains(t, string(env), "DET_CONTAINER_DEVICES=GPU-a,GPU-b\n")
+}
+
+func TestPreContainerStartFailure(t *testing.T) {
+	opts := options.Options{Hooks: options.HooksOptions{
+		PreContainerStart: []string{"sh", "-c", "echo no scratch space; exit 3"},
+	}}
+	require.Nil(t, New(opts).PreContainerStart(context.Background(), testContainer))
+
+	opts.Hooks.FailContainerOnPreStartError = true
+	failure := New(opts).PreContainerStart(context.Background(), testContainer)
+	require.NotNil(t, failure)
+	require.Equal(t, aproto.AgentError, failure.FailureType)
+	require.Equal(t,
+		"pre_container_start hook failed: exit status 3: no scratch space", failure.ErrMsg)
+
+	opts.Hooks.PreContainerStart = []string{"sleep", "10"}
+	opts.Hooks.Timeout = 1
+	failure = New(opts).PreContainerStart(context.Background(), testContainer)
+	require.NotNil(t, failure)
+	require.Equal(t, "pre_container_start hook failed: timed out after 1s", failure.ErrMsg)
+}
+
+func TestPostContainerExit(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+	h := New(options.Options{Hooks: options.HooksOptions{
+		PostContainerExit: []string{"sh", "-c", "env > " + out},
+	}})
+
+	h.PostContainerExit(context.Background(), testContainer, aproto.ContainerExited(137))
+	env, err := os.ReadFile(out) //nolint:gosec
+	require.NoError(t, err)
+	require.Contains(t, string(env), "DET_CONTAINER_ID=container-1\n")
+	require.Contains(t, string(env), "DET_CONTAINER_EXIT_CODE=137\n")
+	require.Contains(t, string(env),
+		"DET_CONTAINER_FAILURE=container failed with non-zero exit code: 137\n")
+}
+
+func TestOnDevicesDetected(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+	h := New(options.Options{Hooks: options.HooksOptions{
+		OnDevicesDetected: []string{"sh", "-c", "env > " + out},
+	}})
+
+	h.OnDevicesDetected(context.Background(), testContainer.Devices)
+	env, err := os.ReadFile(out) //nolint:gosec
+	require.NoError(t, err)
+	require.Contains(t, string(env), "DET_DEVICE_IDS=0,1\n")
+	require.Contains(t, string(env), "DET_DEVICE_UUIDS=GPU-a,GPU-b\n")
+	require.Contains(t, string(env), "DET_DEVICE_TYPE=cuda\n")
+}
+
+func TestTimeout(t *testing.T) {
+	h := New(options.Options{})
+	require.Equal(t, DefaultTimeout, h.timeout(DefaultTimeout))
+	// on_connection_lost isn't limited unless a timeout is configured.
+	require.Zero(t, h.timeout(0))
+
+	h = New(options.Options{Hooks: options.HooksOptions{Timeout: 5}})
+	require.Equal(t, 5*time.Second, h.timeout(DefaultTimeout))
+	require.Equal(t, 5*time.Second, h.timeout(0))
+}
@@ -0,0 +1,268 @@
+package imagecache
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+const (
+	refreshInterval = 5 * time.Minute
+	// determinedRepoPrefix is the prefix of the repositories of the default task images, which are
+	// Determined images even if this agent never used them.
+	determinedRepoPrefix = "determinedai/"
+	bytesPerGB           = 1 << 30
+)
+
+// Cache prefetches the images that are hot in the agent's resource pool, reports the images
+// cached on the host to the master, and evicts the least recently used Determined images once the
+// images take up more than the configured disk space. Determined images are those used by tasks
+// or prefetched by this agent, or from the determinedai repositories; images are never evicted
+// while they are hot or in use by a container.
+type Cache struct {
+	// Configuration details. Set in initialization and never modified after.
+	opts options.ImageCacheOptions
+
+	// System dependencies. Also set in initialization and never modified after.
+	log      *logrus.Entry
+	cruntime container.ContainerRuntime
+	images   container.ImageCache // nil if the runtime's images can't be listed.
+	outbox   chan<- *aproto.MasterMessage
+
+	// Internal state. Access should be protected.
+	mu       sync.Mutex
+	hot      []string
+	lastUsed map[string]time.Time
+	reported []string
+	prefetch chan struct{}
+}
+
+// New returns a new image cache for the images of the container runtime.
+func New(
+	opts options.ImageCacheOptions,
+	cruntime container.ContainerRuntime,
+	outbox chan<- *aproto.MasterMessage,
+) *Cache {
+	images, _ := cruntime.(container.ImageCache)
+	return &Cache{
+		opts:     opts,
+		log:      logrus.WithField("component", "image-cache"),
+		cruntime: cruntime,
+		images:   images,
+		outbox:   outbox,
+		lastUsed: map[string]time.Time{},
+		prefetch: make(chan struct{}, 1),
+	}
+}
+
+// Prefetch replaces the hot images and starts pulling any of them missing from the host in the
+// background. Once they are pulled, the cached images are reported to the master, even if they
+// haven't changed since the last report.
+func (c *Cache) Prefetch(images []string) {
+	c.mu.Lock()
+	c.hot = nil
+	for _, image := range images {
+		c.hot = append(c.hot, normalize(image))
+	}
+	c.mu.Unlock()
+
+	select {
+	case c.prefetch <- struct{}{}:
+	default:
+	}
+}
+
+// Used records that a task is using the image.
+func (c *Cache) Used(image string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastUsed[normalize(image)] = time.Now()
+}
+
+// Run prefetches images and periodically refreshes the cache until the context is canceled.
+func (c *Cache) Run(ctx context.Context) {
+	t := time.NewTicker(refreshInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.prefetch:
+			c.pullHot(ctx)
+			c.refresh(ctx, true)
+		case <-t.C:
+			c.refresh(ctx, false)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Cache) pullHot(ctx context.Context) {
+	c.mu.Lock()
+	hot := slices.Clone(c.hot)
+	c.mu.Unlock()
+
+	for _, image := range hot {
+		c.log.Debugf("prefetching image %s", image)
+		err := c.cruntime.PullImage(
+			ctx, docker.PullImage{Name: image}, events.NilPublisher[docker.Event]{},
+		)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			c.log.WithError(err).Warnf("failed to prefetch image %s", image)
+		}
+	}
+}
+
+// refresh evicts images if the cache is over its size limit and reports the cached images to the
+// master if they changed or force is set.
+func (c *Cache) refresh(ctx context.Context, force bool) {
+	if c.images == nil {
+		return
+	}
+
+	images, size, err := c.images.ListImages(ctx)
+	if err != nil {
+		c.log.WithError(err).Warn("failed to list images")
+		return
+	}
+
+	if c.opts.MaxSizeGB > 0 {
+		c.mu.Lock()
+		evict := evictionCandidates(images, size, int64(c.opts.MaxSizeGB)*bytesPerGB,
+			c.lastUsed, c.hot)
+		c.mu.Unlock()
+
+		removed := map[string]bool{}
+		for _, image := range evict {
+			c.log.Infof("evicting image %s (%s)", image.ID, strings.Join(image.RepoTags, ", "))
+			if err := c.images.RemoveImage(ctx, image.ID); err != nil {
+				// The image may have been removed or started being used since it was listed.
+				c.log.WithError(err).Warnf("failed to evict image %s", image.ID)
+				continue
+			}
+			removed[image.ID] = true
+		}
+		var kept []*types.ImageSummary
+		for _, image := range images {
+			if !removed[image.ID] {
+				kept = append(kept, image)
+			}
+		}
+		images = kept
+	}
+
+	cached := cachedImages(images)
+	c.mu.Lock()
+	changed := !slices.Equal(cached, c.reported)
+	c.reported = cached
+	c.mu.Unlock()
+	if !changed && !force {
+		return
+	}
+
+	select {
+	case c.outbox <- &aproto.MasterMessage{ImagesCached: &aproto.ImagesCached{Images: cached}}:
+	case <-ctx.Done():
+	}
+}
+
+// evictionCandidates returns the Determined images to remove, least recently used first, to bring
+// the total size of the images under maxSize. Images without a recorded use are ordered by when
+// they were created.
+func evictionCandidates(
+	images []*types.ImageSummary,
+	size, maxSize int64,
+	lastUsed map[string]time.Time,
+	hot []string,
+) []*types.ImageSummary {
+	if size <= maxSize {
+		return nil
+	}
+
+	used := map[string]time.Time{}
+	var candidates []*types.ImageSummary
+	for _, image := range images {
+		// Images are removed by force, so an image is only removed if it is known that no
+		// container, running or stopped, uses it; Docker reports -1 if it didn't count them.
+		if image.Containers != 0 {
+			continue
+		}
+
+		isDetermined, isHot := false, false
+		used[image.ID] = time.Unix(image.Created, 0)
+		for _, ref := range imageRefs(image) {
+			if t, ok := lastUsed[ref]; ok {
+				isDetermined = true
+				if t.After(used[image.ID]) {
+					used[image.ID] = t
+				}
+			}
+			isHot = isHot || slices.Contains(hot, ref)
+			isDetermined = isDetermined || strings.HasPrefix(ref, determinedRepoPrefix)
+		}
+		if isDetermined && !isHot {
+			candidates = append(candidates, image)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return used[candidates[i].ID].Before(used[candidates[j].ID])
+	})
+
+	for i, image := range candidates {
+		if size <= maxSize {
+			return candidates[:i]
+		}
+		size -= image.Size
+		if image.SharedSize > 0 {
+			size += image.SharedSize
+		}
+	}
+	return candidates
+}
+
+// cachedImages returns the sorted tags of the images.
+func cachedImages(images []*types.ImageSummary) []string {
+	cached := []string{}
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			if tag != "<none>:<none>" {
+				cached = append(cached, tag)
+			}
+		}
+	}
+	sort.Strings(cached)
+	return cached
+}
+
+func imageRefs(image *types.ImageSummary) []string {
+	var refs []string
+	for _, ref := range append(slices.Clone(image.RepoTags), image.RepoDigests...) {
+		refs = append(refs, normalize(ref))
+	}
+	return refs
+}
+
+// normalize returns the familiar form of the image reference, with the default tag if it has none,
+// so that references to the same image compare equal.
+func normalize(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	return reference.FamiliarString(reference.TagNameOnly(named))
+}
@@ -0,0 +1,48 @@
+package imagecache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvictionCandidates(t *testing.T) {
+	now := time.Now()
+	images := []*types.ImageSummary{
+		{ID: "old-unused", RepoTags: []string{"determinedai/environments:old"}, Created: 1, Size: 10},
+		{ID: "recently-used", RepoTags: []string{"my/image:latest"}, Created: 2, Size: 10},
+		{ID: "used-long-ago", RepoTags: []string{"my/other:v1"}, Created: 3, Size: 10},
+		{ID: "running", RepoTags: []string{"determinedai/environments:running"}, Size: 10, Containers: 1},
+		{ID: "uncounted", RepoTags: []string{"determinedai/environments:x"}, Size: 10, Containers: -1},
+		{ID: "hot", RepoTags: []string{"determinedai/environments:hot"}, Size: 10},
+		{ID: "not-determined", RepoTags: []string{"postgres:14"}, Size: 10},
+	}
+	lastUsed := map[string]time.Time{
+		"my/image:latest": now,
+		"my/other:v1":     now.Add(-time.Hour),
+	}
+	hot := []string{"determinedai/environments:hot"}
+
+	ids := func(images []*types.ImageSummary) []string {
+		var ids []string
+		for _, image := range images {
+			ids = append(ids, image.ID)
+		}
+		return ids
+	}
+
+	require.Empty(t, evictionCandidates(images, 70, 70, lastUsed, hot))
+	require.Equal(t, []string{"old-unused"}, ids(evictionCandidates(images, 70, 60, lastUsed, hot)))
+	require.Equal(t, []string{"old-unused", "used-long-ago"},
+		ids(evictionCandidates(images, 70, 55, lastUsed, hot)))
+	require.Equal(t, []string{"old-unused", "used-long-ago", "recently-used"},
+		ids(evictionCandidates(images, 70, 0, lastUsed, hot)))
+}
+
+func TestNormalize(t *testing.T) {
+	require.Equal(t, "ubuntu:latest", normalize("docker.io/library/ubuntu"))
+	require.Equal(t, "determinedai/environments:tag", normalize("determinedai/environments:tag"))
+	require.Equal(t, "registry.example.com/image:latest", normalize("registry.example.com/image"))
+}
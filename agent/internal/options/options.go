@@ -10,6 +10,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// redacted replaces secrets in printed options.
+const redacted = "********"
+
 // Options stores all the configurable options for the Determined agent.
 type Options struct {
 	ConfigFile string `json:"config_file"`
@@ -23,12 +26,15 @@ type Options struct {
 	ContainerMasterHost string `json:"container_master_host"`
 	ContainerMasterPort int    `json:"container_master_port"`
 
-	Label        string `json:"label"`
-	ResourcePool string `json:"resource_pool"`
+	Label        string            `json:"label"`
+	Labels       map[string]string `json:"labels"`
+	ResourcePool string            `json:"resource_pool"`
 
 	APIEnabled bool   `json:"api_enabled"`
 	BindIP     string `json:"bind_ip"`
 	BindPort   int    `json:"bind_port"`
+	// APIToken is the bearer token required by the API's status endpoints.
+	APIToken string `json:"api_token"`
 
 	VisibleGPUs string `json:"visible_gpus"`
 
@@ -53,8 +59,18 @@ type Options struct {
 	// master config.
 	AgentReconnectBackoff int `json:"agent_reconnect_backoff"`
 
+	DevicePlugin DevicePluginOptions `json:"device_plugin"`
+
 	Hooks HooksOptions `json:"hooks"`
 
+	DeviceHealth DeviceHealthOptions `json:"device_health"`
+
+	ImageCache ImageCacheOptions `json:"image_cache"`
+
+	MessageSpool MessageSpoolOptions `json:"message_spool"`
+
+	Telemetry TelemetryOptions `json:"telemetry"`
+
 	ContainerRuntime   string             `json:"container_runtime"`
 	ImageRoot          string             `json:"image_root"`
 	SingularityOptions SingularityOptions `json:"singularity_options"`
@@ -84,11 +100,19 @@ func (o Options) Deprecations() (errs []error) {
 func (o Options) Validate() []error {
 	return []error{
 		o.validateTLS(),
-		check.In(o.SlotType, []string{"gpu", "cuda", "rocm", "cpu", "auto", "none"}),
+		check.In(o.SlotType, []string{"gpu", "cuda", "rocm", "cpu", "auto", "none", "plugin"}),
 		check.NotEmpty(o.MasterHost, "master host must be provided"),
+		o.validateDevicePlugin(),
 	}
 }
 
+func (o Options) validateDevicePlugin() error {
+	if o.SlotType == "plugin" && len(o.DevicePlugin.Command) == 0 {
+		return errors.New("device plugin command must be provided for the plugin slot type")
+	}
+	return nil
+}
+
 func (o Options) validateTLS() error {
 	if !o.TLS || !o.APIEnabled {
 		return nil
@@ -102,9 +126,17 @@ func (o Options) validateTLS() error {
 	return nil
 }
 
+// Redacted returns a copy of the options with secrets redacted.
+func (o Options) Redacted() Options {
+	if o.APIToken != "" {
+		o.APIToken = redacted
+	}
+	return o
+}
+
 // Printable returns a printable string.
 func (o Options) Printable() ([]byte, error) {
-	optJSON, err := json.Marshal(o)
+	optJSON, err := json.Marshal(o.Redacted())
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to convert config to JSON")
 	}
@@ -163,9 +195,60 @@ type FluentOptions struct {
 	ContainerName string `json:"container_name"`
 }
 
+// DevicePluginOptions configures the external command that discovers the agent's devices when the
+// slot type is "plugin".
+type DevicePluginOptions struct {
+	// Command prints the devices, and how to expose them to containers, as JSON.
+	Command []string `json:"command"`
+}
+
 // HooksOptions contains external commands to be run when specific things happen.
 type HooksOptions struct {
-	OnConnectionLost []string `json:"on_connection_lost"`
+	OnConnectionLost  []string `json:"on_connection_lost"`
+	OnDevicesDetected []string `json:"on_devices_detected"`
+	PreContainerStart []string `json:"pre_container_start"`
+	PostContainerExit []string `json:"post_container_exit"`
+	PreShutdown       []string `json:"pre_shutdown"`
+	// Timeout is the time a hook may run before it is killed and fails, in seconds; 0 uses the
+	// default.
+	Timeout int `json:"timeout"`
+	// FailContainerOnPreStartError fails the container if its pre_container_start hook fails,
+	// rather than only logging the failure.
+	FailContainerOnPreStartError bool `json:"fail_container_on_pre_start_error"`
+}
+
+// DeviceHealthOptions configures the periodic health checks of the agent's devices, whose results
+// are reported to the master so it can disable unhealthy slots.
+type DeviceHealthOptions struct {
+	// CheckInterval is the time between health checks, in seconds; 0 disables them.
+	CheckInterval int `json:"check_interval"`
+	// Command is run for each device on every check and marks the device unhealthy by exiting
+	// with a non-zero status.
+	Command []string `json:"command"`
+}
+
+// ImageCacheOptions configures the management of the images cached on the agent's host.
+type ImageCacheOptions struct {
+	// MaxSizeGB is the disk space images may take up before the least recently used Determined
+	// images are evicted, in gigabytes; 0 disables eviction.
+	MaxSizeGB int `json:"max_size_gb"`
+}
+
+// MessageSpoolOptions configures the on-disk queue of the messages for the master, such as container
+// logs, that the agent produces while it is disconnected from the master.
+type MessageSpoolOptions struct {
+	// Path is the file the messages are spooled to; defaults to a file in the temporary directory.
+	Path string `json:"path"`
+	// MaxSizeMB is the space the spooled messages may take up before container logs are dropped, in
+	// megabytes; 0 disables spooling.
+	MaxSizeMB int `json:"max_size_mb"`
+}
+
+// TelemetryOptions configures the export of traces of container launches, which continue the
+// traces of their allocations on the master.
+type TelemetryOptions struct {
+	OtelEnabled              bool   `json:"otel_enabled"`
+	OtelExportedOtlpEndpoint string `json:"otel_endpoint"`
 }
 
 // ContainerRuntime configures which container runtime to use.
@@ -177,6 +260,7 @@ const (
 	SingularityContainerRuntime = "singularity"
 	DockerContainerRuntime      = "docker"
 	PodmanContainerRuntime      = "podman"
+	BareContainerRuntime        = "bare"
 )
 
 // SingularityOptions configures how we interact with Singularity.
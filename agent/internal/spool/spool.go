@@ -0,0 +1,207 @@
+package spool
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const logSourceAgent = "agent"
+
+// dropped describes a range of log messages of a container that were dropped.
+type dropped struct {
+	count       int
+	first, last time.Time
+}
+
+// Spool is a bounded, on-disk queue of the messages for the master that the agent produces while it
+// is disconnected from it, which are replayed in order once it reconnects. Once the spool is full,
+// container logs and stats are dropped, while state changes are always kept; each range of dropped
+// logs is replaced by a log that marks it in the container's log stream.
+type Spool struct {
+	// Configuration details. Set in initialization and never modified after.
+	path    string
+	maxSize int64
+
+	// System dependencies. Also set in initialization and never modified after.
+	log  *logrus.Entry
+	file *os.File
+
+	// Internal state. Access should be protected.
+	mu      sync.Mutex
+	size    int64
+	dropped map[cproto.ID]*dropped
+}
+
+// New returns a new, empty spool, which replaces any existing file at the path.
+func New(path string, maxSize int64) (*Spool, error) {
+	//nolint:gosec // The path is configured by the agent's operator.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening spool file: %w", err)
+	}
+	return &Spool{
+		path:    path,
+		maxSize: maxSize,
+		log:     logrus.WithField("component", "spool"),
+		file:    file,
+		dropped: map[cproto.ID]*dropped{},
+	}, nil
+}
+
+// Push adds the message to the end of the spool, or drops it if the spool is full and the message
+// is a container log or stats record.
+func (s *Spool) Push(msg *aproto.MasterMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bs, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding spooled message: %w", err)
+	}
+	bs = append(bs, '\n')
+
+	if s.size+int64(len(bs)) > s.maxSize {
+		switch {
+		case msg.ContainerLog != nil:
+			s.drop(msg.ContainerLog)
+			return nil
+		case msg.ContainerStatsRecord != nil:
+			s.log.Debug("dropping container stats record, spool is full")
+			return nil
+		}
+	}
+
+	if id, ok := containerID(msg); ok {
+		if err := s.markDropped(id); err != nil {
+			return err
+		}
+	}
+	return s.write(bs)
+}
+
+// Replay sends the spooled messages in order, followed by marks for any logs that were dropped
+// since they were last spooled, and empties the spool.
+func (s *Spool) Replay(ctx context.Context, send func(*aproto.MasterMessage) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range sortedIDs(s.dropped) {
+		if err := s.markDropped(id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking spool file: %w", err)
+	}
+	dec := json.NewDecoder(bufio.NewReader(s.file))
+	for {
+		var msg aproto.MasterMessage
+		switch err := dec.Decode(&msg); {
+		case err == io.EOF:
+			s.log.Debugf("replayed %d bytes of spooled messages", s.size)
+			s.size = 0
+			if err := s.file.Truncate(0); err != nil {
+				return fmt.Errorf("truncating spool file: %w", err)
+			}
+			return nil
+		case err != nil:
+			return fmt.Errorf("decoding spooled message: %w", err)
+		case ctx.Err() != nil:
+			return ctx.Err()
+		}
+
+		if err := send(&msg); err != nil {
+			return err
+		}
+	}
+}
+
+// Close closes and removes the spool file.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(s.path)
+}
+
+func (s *Spool) write(bs []byte) error {
+	n, err := s.file.Write(bs)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("writing spool file: %w", err)
+	}
+	return nil
+}
+
+func (s *Spool) drop(log *aproto.ContainerLog) {
+	d, ok := s.dropped[log.ContainerID]
+	if !ok {
+		s.log.Warnf("spool is full, dropping logs of container %s", log.ContainerID)
+		d = &dropped{first: log.Timestamp}
+		s.dropped[log.ContainerID] = d
+	}
+	d.count++
+	d.last = log.Timestamp
+}
+
+// markDropped writes the log marking the range of dropped logs of the container, if there is one,
+// so that it precedes the container's later messages. Marks are written even if the spool is full.
+func (s *Spool) markDropped(id cproto.ID) error {
+	d, ok := s.dropped[id]
+	if !ok {
+		return nil
+	}
+	delete(s.dropped, id)
+
+	level := model.LogLevelWarning
+	source := logSourceAgent
+	msg := fmt.Sprintf(
+		"%d log messages from %s to %s were dropped while the agent was disconnected from the master",
+		d.count, d.first.Format(time.RFC3339Nano), d.last.Format(time.RFC3339Nano),
+	)
+	bs, err := json.Marshal(&aproto.MasterMessage{ContainerLog: &aproto.ContainerLog{
+		ContainerID: id,
+		Timestamp:   d.last,
+		Level:       &level,
+		AuxMessage:  &msg,
+		Source:      &source,
+	}})
+	if err != nil {
+		return fmt.Errorf("encoding dropped logs mark: %w", err)
+	}
+	return s.write(append(bs, '\n'))
+}
+
+func containerID(msg *aproto.MasterMessage) (cproto.ID, bool) {
+	switch {
+	case msg.ContainerLog != nil:
+		return msg.ContainerLog.ContainerID, true
+	case msg.ContainerStateChanged != nil:
+		return msg.ContainerStateChanged.Container.ID, true
+	default:
+		return "", false
+	}
+}
+
+func sortedIDs(dropped map[cproto.ID]*dropped) []cproto.ID {
+	ids := maps.Keys(dropped)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,85 @@
+package spool
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+func logMessage(id cproto.ID, msg string, ts time.Time) *aproto.MasterMessage {
+	return &aproto.MasterMessage{ContainerLog: &aproto.ContainerLog{
+		ContainerID: id,
+		Timestamp:   ts,
+		RunMessage:  &aproto.RunMessage{Value: msg},
+	}}
+}
+
+func stateMessage(id cproto.ID, state cproto.State) *aproto.MasterMessage {
+	return &aproto.MasterMessage{ContainerStateChanged: &aproto.ContainerStateChanged{
+		Container: cproto.Container{ID: id, State: state},
+	}}
+}
+
+func replay(t *testing.T, s *Spool) []*aproto.MasterMessage {
+	var msgs []*aproto.MasterMessage
+	require.NoError(t, s.Replay(context.Background(), func(msg *aproto.MasterMessage) error {
+		msgs = append(msgs, msg)
+		return nil
+	}))
+	return msgs
+}
+
+func TestSpoolReplaysInOrder(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "spool"), 1<<20)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+
+	ts := time.Now().UTC()
+	require.NoError(t, s.Push(logMessage("a", "one", ts)))
+	require.NoError(t, s.Push(logMessage("a", "two", ts)))
+	require.NoError(t, s.Push(stateMessage("a", cproto.Terminated)))
+
+	msgs := replay(t, s)
+	require.Len(t, msgs, 3)
+	require.Equal(t, "one", msgs[0].ContainerLog.RunMessage.Value)
+	require.True(t, ts.Equal(msgs[0].ContainerLog.Timestamp))
+	require.Equal(t, "two", msgs[1].ContainerLog.RunMessage.Value)
+	require.Equal(t, cproto.Terminated, msgs[2].ContainerStateChanged.Container.State)
+
+	require.Empty(t, replay(t, s))
+	require.NoError(t, s.Push(logMessage("a", "three", ts)))
+	msgs = replay(t, s)
+	require.Len(t, msgs, 1)
+	require.Equal(t, "three", msgs[0].ContainerLog.RunMessage.Value)
+}
+
+func TestSpoolMarksDroppedLogs(t *testing.T) {
+	ts := time.Now().UTC()
+	s, err := New(filepath.Join(t.TempDir(), "spool"), 1)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, s.Close()) }()
+
+	require.NoError(t, s.Push(logMessage("a", "one", ts)))
+	require.NoError(t, s.Push(logMessage("b", "one", ts)))
+	require.NoError(t, s.Push(logMessage("a", "two", ts.Add(time.Second))))
+	require.NoError(t, s.Push(&aproto.MasterMessage{
+		ContainerStatsRecord: &aproto.ContainerStatsRecord{},
+	}))
+	require.NoError(t, s.Push(stateMessage("a", cproto.Terminated)))
+
+	msgs := replay(t, s)
+	require.Len(t, msgs, 3)
+	require.Equal(t, cproto.ID("a"), msgs[0].ContainerLog.ContainerID)
+	require.Contains(t, *msgs[0].ContainerLog.AuxMessage, "2 log messages from")
+	require.Equal(t, cproto.Terminated, msgs[1].ContainerStateChanged.Container.State)
+	require.Equal(t, cproto.ID("b"), msgs[2].ContainerLog.ContainerID)
+	require.Contains(t, *msgs[2].ContainerLog.AuxMessage, "1 log messages from")
+
+	require.Empty(t, replay(t, s))
+}
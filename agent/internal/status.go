@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/determined-ai/determined/agent/internal/containers"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// masterStatus describes the agent's connection to the master.
+type masterStatus struct {
+	Connected bool `json:"connected"`
+	// ReconnectAttempts is the number of failed attempts to reconnect since the connection was lost.
+	ReconnectAttempts int `json:"reconnect_attempts"`
+	// Reconnects is the number of times the agent reconnected since it started.
+	Reconnects       int        `json:"reconnects"`
+	LastConnected    *time.Time `json:"last_connected"`
+	LastDisconnected *time.Time `json:"last_disconnected"`
+	LastError        string     `json:"last_error,omitempty"`
+}
+
+// agentStatus is the state of the agent served by its local API. The agent updates it as it
+// connects to the master and sets up its devices and container manager.
+type agentStatus struct {
+	mu      sync.RWMutex
+	master  masterStatus
+	devices []device.Device
+	labels  map[string]string
+	manager *containers.Manager
+}
+
+func (s *agentStatus) connected(reconnect bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.master.Connected = true
+	s.master.ReconnectAttempts = 0
+	s.master.LastConnected = &now
+	if reconnect {
+		s.master.Reconnects++
+	}
+}
+
+func (s *agentStatus) disconnected(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.master.Connected = false
+	s.master.LastDisconnected = &now
+	if err != nil {
+		s.master.LastError = err.Error()
+	}
+}
+
+func (s *agentStatus) reconnectFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.master.ReconnectAttempts++
+	s.master.LastError = err.Error()
+}
+
+func (s *agentStatus) setup(
+	devices []device.Device, labels map[string]string, manager *containers.Manager,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.devices = devices
+	s.labels = labels
+	s.manager = manager
+}
+
+func (s *agentStatus) masterStatus() masterStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.master
+}
+
+func (s *agentStatus) deviceStatus() ([]device.Device, map[string]string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.devices), maps.Clone(s.labels)
+}
+
+// containerManager returns the container manager, or nil if the agent hasn't set it up yet.
+func (s *agentStatus) containerManager() *containers.Manager {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.manager
+}
@@ -0,0 +1,800 @@
+package bare
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/agent/internal/container"
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/cruntimes"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/syncx/waitgroupx"
+)
+
+const (
+	hostNetworking = "host"
+	archivesName   = "archives"
+	stateFileName  = "container.json"
+	stdoutFileName = "stdout.log"
+	stderrFileName = "stderr.log"
+	exitFileName   = "exit_code"
+	// The offset of the output shipped from an output file is saved next to it, with this suffix.
+	offsetFileSuffix = ".offset"
+	// taskRootEnvVar tells the task where its root directory is.
+	taskRootEnvVar = "DET_TASK_ROOT"
+
+	// The task's command is wrapped in a shell that records its exit status, so that a task which
+	// exits while the agent is down, or after it was reattached, still reports how it exited.
+	wrapperShell  = "/bin/sh"
+	wrapperScript = `"$@"; status=$?; echo "$status" > "$0"; exit "$status"`
+
+	pollInterval       = time.Second
+	tailInterval       = 100 * time.Millisecond
+	offsetSaveInterval = time.Second
+)
+
+// hostEnvironment are the variables of the agent's environment that tasks inherit.
+var hostEnvironment = []string{"PATH", "HOME", "USER", "LANG"}
+
+// BareContainer captures the state of a task process. Its exported fields are saved to the task
+// directory once the process has started, so it can be reattached after the agent restarts.
+type BareContainer struct {
+	PID       int               `json:"pid"`
+	StartTime string            `json:"start_time"`
+	Labels    map[string]string `json:"labels"`
+
+	Req     cproto.RunSpec `json:"-"`
+	Dir     string         `json:"-"`
+	Started bool           `json:"-"`
+	// Proc is only set for processes started by this agent process, which can wait on them.
+	Proc *os.Process `json:"-"`
+}
+
+// BareClient implements ContainerRuntime by running tasks as host processes, without any
+// container engine.
+type BareClient struct {
+	log        *logrus.Entry
+	mu         sync.Mutex
+	wg         waitgroupx.Group
+	containers map[cproto.ID]*BareContainer
+	agentTmp   string
+	debug      bool
+}
+
+// New returns a new bare client, which launches and tracks task processes. It picks up the task
+// processes that a previous run of the agent left running, so that they can be reattached.
+func New(opts options.Options) (*BareClient, error) {
+	agentTmp, err := cruntimes.BaseTempDirName(opts.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compose agentTmp directory path: %w", err)
+	}
+
+	if err := os.MkdirAll(agentTmp, 0o700); err != nil {
+		return nil, fmt.Errorf("preparing agent tmp: %w", err)
+	}
+
+	s := &BareClient{
+		log:        logrus.WithField("component", "bare"),
+		wg:         waitgroupx.WithContext(context.Background()),
+		containers: make(map[cproto.ID]*BareContainer),
+		agentTmp:   agentTmp,
+		debug:      opts.Debug,
+	}
+	if err := s.loadContainers(); err != nil {
+		return nil, fmt.Errorf("loading task processes from previous runs: %w", err)
+	}
+	return s, nil
+}
+
+// Close the client. Unlike other runtimes, running task processes are left running so that the
+// next run of the agent can reattach them.
+func (s *BareClient) Close() error {
+	s.wg.Close()
+	return nil
+}
+
+// PullImage implements container.ContainerRuntime.
+func (s *BareClient) PullImage(
+	ctx context.Context,
+	req docker.PullImage,
+	p events.Publisher[docker.Event],
+) (err error) {
+	// Tasks run on the host, so there is no image to pull.
+	return nil
+}
+
+// CreateContainer implements container.ContainerRuntime.
+func (s *BareClient) CreateContainer(
+	ctx context.Context,
+	id cproto.ID,
+	req cproto.RunSpec,
+	p events.Publisher[docker.Event],
+) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.containers[id] = &BareContainer{
+		Labels: req.ContainerConfig.Labels,
+		Req:    req,
+		Dir:    filepath.Join(s.agentTmp, id.String()),
+	}
+	return id.String(), nil
+}
+
+// RunContainer implements container.ContainerRuntime.
+// nolint: golint // Both contexts can't both be first.
+func (s *BareClient) RunContainer(
+	ctx context.Context,
+	waitCtx context.Context,
+	id string,
+	p events.Publisher[docker.Event],
+) (*docker.Container, error) {
+	s.mu.Lock()
+	cont, ok := s.containers[cproto.ID(id)]
+	s.mu.Unlock()
+	if !ok {
+		return nil, container.ErrMissing
+	}
+	req := cont.Req
+
+	credential, err := taskCredential(req.ContainerConfig.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.HostConfig.NetworkMode != hostNetworking {
+		if err = p.Publish(ctx, docker.NewLogEvent(
+			model.LogLevelDebug,
+			fmt.Sprintf("container requested %s networking, but tasks run on the host; "+
+				"overriding to host networking", req.HostConfig.NetworkMode),
+		)); err != nil {
+			return nil, err
+		}
+	}
+	// Tasks see the host's paths, so a mount can only be honored if it is at its host path.
+	for _, m := range req.HostConfig.Mounts {
+		if m.Source != m.Target {
+			return nil, fmt.Errorf(
+				"bind mount %s:%s is not supported since tasks run on the host; "+
+					"mount it at its host path instead", m.Source, m.Target,
+			)
+		}
+	}
+
+	if err = os.MkdirAll(cont.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("making task dir: %w", err)
+	}
+	archivesPath := filepath.Join(cont.Dir, archivesName)
+	mountPoints, err := cruntimes.ArchiveMountPoints(ctx, req, p, archivesPath, s.log)
+	if err != nil {
+		return nil, fmt.Errorf("determining mount points: %w", err)
+	}
+	if credential != nil {
+		if err = chownTaskDir(cont.Dir, req, credential); err != nil {
+			return nil, err
+		}
+	}
+	root := taskRoot{dir: archivesPath, mountPoints: mountPoints}
+	stdout, err := os.OpenFile( // #nosec G304 // We made this filepath.
+		filepath.Join(cont.Dir, stdoutFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating stdout file: %w", err)
+	}
+	defer stdout.Close()
+	stderr, err := os.OpenFile( // #nosec G304 // We made this filepath.
+		filepath.Join(cont.Dir, stderrFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating stderr file: %w", err)
+	}
+	defer stderr.Close()
+
+	args := []string{"-c", wrapperScript, filepath.Join(cont.Dir, exitFileName)}
+	for _, arg := range req.ContainerConfig.Cmd {
+		args = append(args, root.path(arg))
+	}
+	if err = cruntimes.PprintCommand(ctx, wrapperShell, args, p, s.log); err != nil {
+		return nil, err
+	}
+
+	// The process is deliberately not bound to a context, so it outlives the agent.
+	// #nosec G204 // We launch arbitrary user code as a service.
+	cmd := exec.Command(wrapperShell, args...)
+	cmd.Dir = root.path(req.ContainerConfig.WorkingDir)
+	cmd.Env = taskEnvironment(req, root, credential, s.debug)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Run the task in its own process group, so signals reach all of its processes.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Credential: credential}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting task process: %w", err)
+	}
+
+	s.mu.Lock()
+	cont.PID = cmd.Process.Pid
+	cont.Proc = cmd.Process
+	cont.Started = true
+	cont.StartTime, err = processStartTime(cont.PID)
+	if err == nil {
+		err = saveContainer(cont)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		s.log.WithError(err).Warnf("task process %d of %s can't be reattached", cont.PID, id)
+	}
+	s.log.Infof("started container %s with pid %d", id, cont.PID)
+
+	exited := make(chan struct{})
+	logs := s.shipAllTaskLogs(cont, exited, p)
+	return &docker.Container{
+		ContainerInfo:   containerInfo(id, cont),
+		ContainerWaiter: s.waitOnContainer(cproto.ID(id), cont, exited, logs, p),
+	}, nil
+}
+
+// ReattachContainer implements container.ContainerRuntime. The output of the task process is
+// shipped from where the previous run of the agent stopped shipping it.
+func (s *BareClient) ReattachContainer(
+	ctx context.Context,
+	reattachID cproto.ID,
+	p events.Publisher[docker.Event],
+) (*docker.Container, *aproto.ExitCode, error) {
+	s.mu.Lock()
+	cont, ok := s.containers[reattachID]
+	s.mu.Unlock()
+	if !ok || !cont.Started {
+		return nil, nil, nil
+	}
+
+	if !processAlive(cont) {
+		exitCode, err := readExitCode(cont)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, &exitCode, nil
+	}
+
+	exited := make(chan struct{})
+	logs := s.shipAllTaskLogs(cont, exited, p)
+	return &docker.Container{
+		ContainerInfo:   containerInfo(reattachID.String(), cont),
+		ContainerWaiter: s.waitOnContainer(reattachID, cont, exited, logs, p),
+	}, nil, nil
+}
+
+// RemoveContainer implements container.ContainerRuntime.
+func (s *BareClient) RemoveContainer(ctx context.Context, id string, force bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cont, ok := s.containers[cproto.ID(id)]
+	if !ok {
+		return container.ErrMissing
+	}
+
+	if cont.Started {
+		return killProcessGroup(cont, syscall.SIGKILL)
+	}
+	delete(s.containers, cproto.ID(id))
+	return os.RemoveAll(cont.Dir)
+}
+
+// SignalContainer implements container.ContainerRuntime.
+func (s *BareClient) SignalContainer(
+	ctx context.Context,
+	id string,
+	sig syscall.Signal,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cont, ok := s.containers[cproto.ID(id)]
+	if !ok {
+		return container.ErrMissing
+	}
+
+	if cont.Started {
+		return killProcessGroup(cont, sig)
+	}
+	return fmt.Errorf("cannot signal container %s with %s that is not started", id, sig)
+}
+
+// ListRunningContainers implements container.ContainerRuntime.
+func (s *BareClient) ListRunningContainers(
+	ctx context.Context,
+	fs filters.Args,
+) (map[cproto.ID]types.Container, error) {
+	resp := make(map[cproto.ID]types.Container)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, cont := range s.containers {
+		state := "created"
+		if cont.Started {
+			state = "running"
+		}
+		resp[id] = types.Container{
+			ID:     string(id),
+			Labels: cont.Labels,
+			State:  state,
+		}
+	}
+	return resp, nil
+}
+
+// loadContainers restores the task processes saved in the agent tmp dir that are still running,
+// and cleans up after the ones that are not.
+func (s *BareClient) loadContainers() error {
+	entries, err := os.ReadDir(s.agentTmp)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		cont := &BareContainer{Dir: filepath.Join(s.agentTmp, e.Name()), Started: true}
+		b, err := os.ReadFile(filepath.Join(cont.Dir, stateFileName))
+		if err == nil {
+			err = json.Unmarshal(b, cont)
+		}
+		switch {
+		case err != nil:
+			s.log.WithError(err).Warnf("cleaning up unreadable task dir %s", cont.Dir)
+		case !processAlive(cont):
+			s.log.Infof("cleaning up task dir %s of exited process %d", cont.Dir, cont.PID)
+		default:
+			s.log.Infof("found task process %d of container %s", cont.PID, e.Name())
+			s.containers[cproto.ID(e.Name())] = cont
+			continue
+		}
+		if err := os.RemoveAll(cont.Dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskRoot is the directory that a task's archives are written to. Tasks share the host's
+// filesystem, so rather than the archives being mounted at their paths, paths under their mount
+// points, such as /run/determined, are rewritten to point into the task's root.
+type taskRoot struct {
+	dir         string
+	mountPoints []string
+}
+
+// path returns the task's copy of p if p is under one of the mount points, and p otherwise.
+func (r taskRoot) path(p string) string {
+	for _, m := range r.mountPoints {
+		if p == m || strings.HasPrefix(p, m+"/") {
+			return filepath.Join(r.dir, p)
+		}
+	}
+	return p
+}
+
+// env rewrites the paths in the value of an environment variable, which may be a list of paths.
+func (r taskRoot) env(kv string) string {
+	k, v, ok := strings.Cut(kv, "=")
+	if !ok {
+		return kv
+	}
+	paths := filepath.SplitList(v)
+	for i, p := range paths {
+		paths[i] = r.path(p)
+	}
+	return k + "=" + strings.Join(paths, string(filepath.ListSeparator))
+}
+
+func (s *BareClient) waitOnContainer(
+	id cproto.ID,
+	cont *BareContainer,
+	exited chan struct{},
+	logs *sync.WaitGroup,
+	p events.Publisher[docker.Event],
+) docker.ContainerWaiter {
+	wchan := make(chan dcontainer.ContainerWaitOKBody, 1)
+	errchan := make(chan error)
+
+	// Process.Wait can't be canceled, so it is kept out of s.wg to not block Close.
+	waited := make(chan dcontainer.ContainerWaitOKBody, 1)
+	if cont.Proc != nil {
+		go func() {
+			var body dcontainer.ContainerWaitOKBody
+			switch state, err := cont.Proc.Wait(); {
+			case err != nil:
+				body.Error = &dcontainer.ContainerWaitOKBodyError{Message: err.Error()}
+			default:
+				body.StatusCode = int64(exitCode(state))
+			}
+			waited <- body
+		}()
+	}
+
+	s.wg.Go(func(ctx context.Context) {
+		defer close(wchan)
+		defer close(errchan)
+
+		var body dcontainer.ContainerWaitOKBody
+		if cont.Proc != nil {
+			select {
+			case body = <-waited:
+			case <-ctx.Done():
+				s.log.Trace("detached from task process")
+				return
+			}
+		} else {
+			t := time.NewTicker(pollInterval)
+			defer t.Stop()
+			for processAlive(cont) {
+				select {
+				case <-t.C:
+				case <-ctx.Done():
+					s.log.Trace("detached from task process")
+					return
+				}
+			}
+			if code, err := readExitCode(cont); err != nil {
+				body.Error = &dcontainer.ContainerWaitOKBodyError{Message: err.Error()}
+			} else {
+				body.StatusCode = int64(code)
+			}
+		}
+		if body.Error != nil {
+			s.log.Tracef("proc %d for container %s exited: %s", cont.PID, id, body.Error.Message)
+		} else {
+			s.log.Tracef("proc %d for container %s exited with %d", cont.PID, id, body.StatusCode)
+		}
+
+		// Ship all the output of the task before reporting its exit.
+		close(exited)
+		logs.Wait()
+
+		select {
+		case wchan <- body:
+		case <-ctx.Done():
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.log.Tracef("forgetting completed container: %s", id)
+		delete(s.containers, id)
+
+		// Defer file cleanup until restart if debug logging is enabled.
+		if s.log.Logger.Level <= logrus.DebugLevel {
+			if err := p.Publish(ctx, docker.NewLogEvent(
+				model.LogLevelDebug,
+				fmt.Sprintf("leaving task dir %s for inspection", cont.Dir),
+			)); err != nil {
+				return
+			}
+		} else if err := os.RemoveAll(cont.Dir); err != nil {
+			if err = p.Publish(ctx, docker.NewLogEvent(
+				model.LogLevelWarning,
+				fmt.Sprintf("failed to cleanup task dir (archives, output, etc): %s", err),
+			)); err != nil {
+				logrus.WithError(err).Error("publishing cleanup failure warning")
+				return
+			}
+		}
+	})
+	return docker.ContainerWaiter{Waiter: wchan, Errs: errchan}
+}
+
+// shipAllTaskLogs ships the task's stdout and stderr, and returns a WaitGroup that is done once
+// all of it is shipped.
+func (s *BareClient) shipAllTaskLogs(
+	cont *BareContainer, exited <-chan struct{}, p events.Publisher[docker.Event],
+) *sync.WaitGroup {
+	var logs sync.WaitGroup
+	logs.Add(2)
+	for path, stdtype := range map[string]stdcopy.StdType{
+		filepath.Join(cont.Dir, stdoutFileName): stdcopy.Stdout,
+		filepath.Join(cont.Dir, stderrFileName): stdcopy.Stderr,
+	} {
+		path, stdtype := path, stdtype
+		s.wg.Go(func(ctx context.Context) {
+			defer logs.Done()
+			s.shipTaskLogs(ctx, path, stdtype, exited, p)
+		})
+	}
+	return &logs
+}
+
+// shipTaskLogs ships the output the task writes to the file, until the task has exited and all
+// of its output is shipped. It starts from the offset saved next to the file, and saves the offset
+// of the shipped output as it goes, so that output is shipped at least once across restarts of
+// the agent.
+func (s *BareClient) shipTaskLogs(
+	ctx context.Context,
+	path string,
+	stdtype stdcopy.StdType,
+	exited <-chan struct{},
+	p events.Publisher[docker.Event],
+) {
+	f, err := os.Open(path) // #nosec G304 // We made this filepath.
+	if err != nil {
+		s.log.WithError(err).Warnf("failed to open task output %s", path)
+		return
+	}
+	r := &tailReader{ctx: ctx, f: f, exited: exited}
+	defer r.Close()
+
+	offsetPath := path + offsetFileSuffix
+	var offset int64
+	if b, err := os.ReadFile(offsetPath); err == nil { // #nosec G304 // We made this filepath.
+		if offset, err = strconv.ParseInt(string(b), 10, 64); err != nil {
+			s.log.WithError(err).Warnf("shipping %s from the start", path)
+		}
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		s.log.WithError(err).Warnf("failed to seek task output %s", path)
+		return
+	}
+	saveOffset := func() {
+		err := os.WriteFile(offsetPath, []byte(strconv.FormatInt(offset, 10)), 0o600)
+		if err != nil {
+			s.log.WithError(err).Warnf("failed to save offset of task output %s", path)
+		}
+	}
+	defer saveOffset()
+
+	// Lines are shipped one at a time, so that the offset always falls at the start of a line.
+	br := bufio.NewReader(r)
+	saved := time.Now()
+	for {
+		line, err := br.ReadBytes('\n')
+		if ctx.Err() != nil {
+			return
+		}
+		if len(line) > 0 {
+			cruntimes.ShipContainerCommandLogs(
+				ctx, io.NopCloser(bytes.NewReader(line)), stdtype, p,
+			)
+			if ctx.Err() != nil {
+				return
+			}
+			offset += int64(len(line))
+			if time.Since(saved) > offsetSaveInterval {
+				saveOffset()
+				saved = time.Now()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// tailReader reads a file that is being written to, as `tail -f` would, until it is done being
+// written to.
+type tailReader struct {
+	ctx    context.Context
+	f      *os.File
+	exited <-chan struct{}
+}
+
+func (t *tailReader) Read(b []byte) (int, error) {
+	for {
+		n, err := t.f.Read(b)
+		if n > 0 || !errors.Is(err, io.EOF) {
+			return n, err
+		}
+		select {
+		case <-t.exited:
+			return t.f.Read(b)
+		case <-t.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(tailInterval):
+		}
+	}
+}
+
+func (t *tailReader) Close() error {
+	return t.f.Close()
+}
+
+// taskCredential returns the credential to run the task as its user:group, which is nil if that
+// is the agent's own user. Only an agent running as root can run tasks as other users.
+func taskCredential(uidgid string) (*syscall.Credential, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("checking user: %w", err)
+	}
+	if uidgid == fmt.Sprintf("%s:%s", u.Uid, u.Gid) {
+		return nil, nil
+	}
+	if u.Uid != "0" {
+		return nil, fmt.Errorf(
+			"agent running as %s:%s cannot launch as user %s", u.Uid, u.Gid, uidgid,
+		)
+	}
+
+	parts := strings.SplitN(uidgid, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("cannot launch as user %s: expected uid:gid", uidgid)
+	}
+	uid, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("cannot launch as user %s: parsing uid: %w", uidgid, err)
+	}
+	gid, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("cannot launch as user %s: parsing gid: %w", uidgid, err)
+	}
+	return &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
+
+// chownTaskDir gives the archives their owners, and the task's user access to the task dir,
+// since archives are written as the agent's user.
+func chownTaskDir(dir string, req cproto.RunSpec, credential *syscall.Credential) error {
+	if err := os.Chown(dir, int(credential.Uid), int(credential.Gid)); err != nil {
+		return fmt.Errorf("chowning task dir: %w", err)
+	}
+	archivesPath := filepath.Join(dir, archivesName)
+	if err := os.Chown(archivesPath, int(credential.Uid), int(credential.Gid)); err != nil {
+		return fmt.Errorf("chowning task archives: %w", err)
+	}
+	for _, a := range req.Archives {
+		for _, i := range a.Archive {
+			path := filepath.Join(archivesPath, a.Path, i.Path)
+			if err := os.Lchown(path, i.UserID, i.GroupID); err != nil {
+				return fmt.Errorf("chowning archive file %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// taskEnvironment returns the environment of the task, which is the task's own environment on
+// top of the few variables in hostEnvironment. The rest of the agent's environment, such as its
+// credentials, is kept from the task.
+func taskEnvironment(
+	req cproto.RunSpec, root taskRoot, credential *syscall.Credential, debug bool,
+) []string {
+	var env []string
+	for _, k := range hostEnvironment {
+		if v, ok := os.LookupEnv(k); ok {
+			env = append(env, k+"="+v)
+		}
+	}
+	if credential != nil {
+		if u, err := user.LookupId(strconv.FormatUint(uint64(credential.Uid), 10)); err == nil {
+			env = append(env, "HOME="+u.HomeDir, "USER="+u.Username)
+		}
+	}
+	for _, kv := range req.ContainerConfig.Env {
+		env = append(env, root.env(kv))
+	}
+	env = append(env, "DET_SHIPPER_EMIT_STDOUT_LOGS=False", taskRootEnvVar+"="+root.dir)
+
+	var cudaVisibleDevices []string
+	for _, d := range req.HostConfig.DeviceRequests {
+		if d.Driver == "nvidia" {
+			cudaVisibleDevices = append(cudaVisibleDevices, d.DeviceIDs...)
+		}
+	}
+	if len(cudaVisibleDevices) > 0 {
+		env = append(env, fmt.Sprintf("CUDA_VISIBLE_DEVICES=%s",
+			strings.Join(cudaVisibleDevices, ",")))
+	}
+
+	detDebug := 0
+	if debug {
+		detDebug = 1
+	}
+	return append(env, fmt.Sprintf("DET_DEBUG=%d", detDebug))
+}
+
+func containerInfo(id string, cont *BareContainer) types.ContainerJSON {
+	at := time.Now().String()
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:      id,
+			Created: at,
+			Path:    wrapperShell,
+			Args:    cont.Req.ContainerConfig.Cmd,
+			State: &types.ContainerState{
+				Status:    "running",
+				Running:   true,
+				Pid:       cont.PID,
+				StartedAt: at,
+			},
+			HostConfig: &dcontainer.HostConfig{
+				NetworkMode: hostNetworking,
+			},
+		},
+		Config: &dcontainer.Config{
+			ExposedPorts: cont.Req.ContainerConfig.ExposedPorts,
+		},
+	}
+}
+
+func saveContainer(cont *BareContainer) error {
+	b, err := json.Marshal(cont)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(cont.Dir, stateFileName), b, 0o600)
+}
+
+// processStartTime returns the start time of the process, in clock ticks since boot, which tells
+// it apart from a later process that reuses its PID.
+func processStartTime(pid int) (string, error) {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", err
+	}
+	// The command name in the second field may contain spaces, but is within parentheses.
+	stat := string(b)
+	fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+	if len(fields) < 20 {
+		return "", fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	return fields[19], nil
+}
+
+func processAlive(cont *BareContainer) bool {
+	if cont.PID <= 0 {
+		return false
+	}
+	startTime, err := processStartTime(cont.PID)
+	return err == nil && startTime == cont.StartTime
+}
+
+func killProcessGroup(cont *BareContainer, sig syscall.Signal) error {
+	if !processAlive(cont) {
+		return nil
+	}
+	if err := syscall.Kill(-cont.PID, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("signaling task process group %d with %s: %w", cont.PID, sig, err)
+	}
+	return nil
+}
+
+func readExitCode(cont *BareContainer) (aproto.ExitCode, error) {
+	b, err := os.ReadFile(filepath.Join(cont.Dir, exitFileName))
+	if err != nil {
+		return 0, fmt.Errorf("task process %d exited without recording its exit status", cont.PID)
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, fmt.Errorf("parsing exit status of task process %d: %w", cont.PID, err)
+	}
+	return aproto.ExitCode(code), nil
+}
+
+// exitCode returns the exit code of the process the way a shell would report it, which for
+// processes killed by a signal is 128 plus the signal number.
+func exitCode(state *os.ProcessState) int {
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return state.ExitCode()
+}
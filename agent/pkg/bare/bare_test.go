@@ -0,0 +1,282 @@
+package bare
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+func newTestClient(t *testing.T, agentID string) *BareClient {
+	cl, err := New(options.Options{AgentID: agentID})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, cl.Close())
+	})
+	return cl
+}
+
+func runTask(
+	ctx context.Context, t *testing.T, cl *BareClient, cmd string, pub events.Publisher[docker.Event],
+) (cproto.ID, *docker.Container) {
+	return runTaskSpec(ctx, t, cl, cproto.RunSpec{
+		ContainerConfig: container.Config{Cmd: strslice.StrSlice{"/bin/sh", "-c", cmd}},
+	}, pub)
+}
+
+func runTaskSpec(
+	ctx context.Context,
+	t *testing.T,
+	cl *BareClient,
+	spec cproto.RunSpec,
+	pub events.Publisher[docker.Event],
+) (cproto.ID, *docker.Container) {
+	u, err := user.Current()
+	require.NoError(t, err)
+
+	spec.ContainerConfig.User = fmt.Sprintf("%s:%s", u.Uid, u.Gid)
+	spec.HostConfig.NetworkMode = hostNetworking
+	cID := cproto.NewID()
+	id, err := cl.CreateContainer(ctx, cID, spec, pub)
+	require.NoError(t, err)
+
+	dc, err := cl.RunContainer(ctx, ctx, id, pub)
+	require.NoError(t, err)
+	return cID, dc
+}
+
+func TestBareRunContainer(t *testing.T) {
+	ctx := context.Background()
+	agentID := fmt.Sprintf("bare-test-%d", time.Now().UnixNano())
+	cl := newTestClient(t, agentID)
+	defer os.RemoveAll(cl.agentTmp)
+
+	evs := make(chan docker.Event, 1024)
+	_, dc := runTask(ctx, t, cl, "echo hello; echo world >&2; exit 3", events.ChannelPublisher(evs))
+
+	res := <-dc.ContainerWaiter.Waiter
+	require.Nil(t, res.Error)
+	require.Equal(t, int64(3), res.StatusCode)
+
+	logs := drainLogs(evs)
+	require.Contains(t, logs, "hello")
+	require.Contains(t, logs, "world")
+}
+
+func TestBareRejectsMountsAtOtherPaths(t *testing.T) {
+	ctx := context.Background()
+	agentID := fmt.Sprintf("bare-test-%d", time.Now().UnixNano())
+	cl := newTestClient(t, agentID)
+	defer os.RemoveAll(cl.agentTmp)
+
+	u, err := user.Current()
+	require.NoError(t, err)
+	pub := events.ChannelPublisher(make(chan docker.Event, 1024))
+	spec := cproto.RunSpec{
+		ContainerConfig: container.Config{
+			Cmd:  strslice.StrSlice{"/bin/true"},
+			User: fmt.Sprintf("%s:%s", u.Uid, u.Gid),
+		},
+		HostConfig: container.HostConfig{
+			NetworkMode: hostNetworking,
+			Mounts: []mount.Mount{{
+				Type: mount.TypeBind, Source: "/tmp", Target: "/determined_shared_fs",
+			}},
+		},
+	}
+	id, err := cl.CreateContainer(ctx, cproto.NewID(), spec, pub)
+	require.NoError(t, err)
+	_, err = cl.RunContainer(ctx, ctx, id, pub)
+	require.ErrorContains(t, err, "/tmp:/determined_shared_fs")
+
+	spec.HostConfig.Mounts[0].Target = "/tmp"
+	_, dc := runTaskSpec(ctx, t, cl, spec, pub)
+	res := <-dc.ContainerWaiter.Waiter
+	require.Nil(t, res.Error)
+	require.Equal(t, int64(0), res.StatusCode)
+}
+
+func TestBareTasksDontInheritAgentEnvironment(t *testing.T) {
+	t.Setenv("DET_AGENT_TEST_TOKEN", "secret")
+	ctx := context.Background()
+	agentID := fmt.Sprintf("bare-test-%d", time.Now().UnixNano())
+	cl := newTestClient(t, agentID)
+	defer os.RemoveAll(cl.agentTmp)
+
+	evs := make(chan docker.Event, 1024)
+	_, dc := runTaskSpec(ctx, t, cl, cproto.RunSpec{
+		ContainerConfig: container.Config{
+			Cmd: strslice.StrSlice{"/bin/sh", "-c", `echo "token=$DET_AGENT_TEST_TOKEN"; ` +
+				`echo "task=$TASK_VAR"; test -n "$PATH" && echo has-path`},
+			Env: []string{"TASK_VAR=set"},
+		},
+	}, events.ChannelPublisher(evs))
+
+	res := <-dc.ContainerWaiter.Waiter
+	require.Nil(t, res.Error)
+	require.Equal(t, int64(0), res.StatusCode)
+
+	logs := drainLogs(evs)
+	require.Contains(t, logs, "token=")
+	require.Contains(t, logs, "task=set")
+	require.Contains(t, logs, "has-path")
+}
+
+func TestBareTasksHaveTheirOwnRoot(t *testing.T) {
+	ctx := context.Background()
+	agentID := fmt.Sprintf("bare-test-%d", time.Now().UnixNano())
+	cl := newTestClient(t, agentID)
+	defer os.RemoveAll(cl.agentTmp)
+
+	const runDir = "/run/determined-bare-test"
+	var dcs []*docker.Container
+	var evs []chan docker.Event
+	for _, name := range []string{"first", "second"} {
+		ev := make(chan docker.Event, 1024)
+		_, dc := runTaskSpec(ctx, t, cl, cproto.RunSpec{
+			ContainerConfig: container.Config{
+				Cmd:        strslice.StrSlice{"/bin/sh", runDir + "/task.sh"},
+				Env:        []string{"TASK_FILE=" + runDir + "/name"},
+				WorkingDir: runDir,
+			},
+			Archives: []cproto.RunArchive{{
+				Path: runDir,
+				Archive: archive.Archive{
+					archive.RootItem("task.sh", []byte(`cat "$TASK_FILE"; echo; ls name`),
+						0o755, tar.TypeReg),
+					archive.RootItem("name", []byte(name), 0o644, tar.TypeReg),
+				},
+			}},
+		}, events.ChannelPublisher(ev))
+		dcs = append(dcs, dc)
+		evs = append(evs, ev)
+	}
+
+	for i, name := range []string{"first", "second"} {
+		res := <-dcs[i].ContainerWaiter.Waiter
+		require.Nil(t, res.Error)
+		require.Equal(t, int64(0), res.StatusCode)
+		logs := drainLogs(evs[i])
+		require.Contains(t, logs, name)
+		require.Contains(t, logs, "name")
+	}
+
+	_, err := os.Lstat(runDir)
+	require.ErrorIs(t, err, os.ErrNotExist, "tasks must not change the host's filesystem")
+}
+
+func TestBareRunsTaskEntrypoints(t *testing.T) {
+	ctx := context.Background()
+	agentID := fmt.Sprintf("bare-test-%d", time.Now().UnixNano())
+	cl := newTestClient(t, agentID)
+	defer os.RemoveAll(cl.agentTmp)
+
+	// The task's scripts are the master's, with a stand-in for python that passes the task's
+	// output through where the scripts enrich it.
+	items := archive.Archive{archive.RootItem(
+		"python", []byte("#!/bin/sh\ncase \"$1\" in *.py) exec cat ;; esac\n"), 0o755, tar.TypeReg,
+	)}
+	for _, name := range []string{
+		"command-entrypoint.sh",
+		"task-signal-handling.sh",
+		"task-logging-setup.sh",
+		"task-logging-teardown.sh",
+	} {
+		b, err := os.ReadFile(filepath.Join("../../../master/static/srv", name))
+		require.NoError(t, err)
+		items = append(items, archive.RootItem(name, b, 0o755, tar.TypeReg))
+	}
+
+	const runDir = "/run/determined"
+	evs := make(chan docker.Event, 1024)
+	_, dc := runTaskSpec(ctx, t, cl, cproto.RunSpec{
+		ContainerConfig: container.Config{
+			Cmd: strslice.StrSlice{
+				runDir + "/command-entrypoint.sh",
+				`echo "hello from $DET_TASK_ROOT"; ls "$DET_TASK_ROOT/run/determined/train/logs"`,
+			},
+			Env: []string{
+				"DET_PYTHON_EXECUTABLE=" + runDir + "/python",
+				"DET_SKIP_PIP_INSTALL=1",
+				"DET_LOG_WAIT_TIME=5",
+			},
+		},
+		Archives: []cproto.RunArchive{{Path: runDir, Archive: items}},
+	}, events.ChannelPublisher(evs))
+
+	res := <-dc.ContainerWaiter.Waiter
+	require.Nil(t, res.Error)
+	require.Equal(t, int64(0), res.StatusCode)
+
+	logs := drainLogs(evs)
+	require.Contains(t, logs, "hello from "+filepath.Join(cl.agentTmp, dc.ContainerInfo.ID, archivesName))
+	require.Contains(t, logs, "stdout.log")
+	require.Contains(t, logs, "wait.fifo")
+
+	_, err := os.Lstat(runDir + "/train/logs/wait.fifo")
+	require.ErrorIs(t, err, os.ErrNotExist, "tasks must not change the host's filesystem")
+}
+
+func TestBareReattachContainer(t *testing.T) {
+	ctx := context.Background()
+	agentID := fmt.Sprintf("bare-test-%d", time.Now().UnixNano())
+	cl := newTestClient(t, agentID)
+	defer os.RemoveAll(cl.agentTmp)
+
+	evs := make(chan docker.Event, 1024)
+	cID, _ := runTask(ctx, t, cl, "echo before; sleep 2; echo after; exit 5",
+		events.ChannelPublisher(evs))
+	var logs []string
+	require.Eventually(t, func() bool {
+		logs = append(logs, drainLogs(evs)...)
+		return slices.Contains(logs, "before")
+	}, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, cl.Close())
+
+	t.Log("reattaching the task from a new client")
+	reattached := newTestClient(t, agentID)
+	running, err := reattached.ListRunningContainers(ctx, docker.LabelFilter("", ""))
+	require.NoError(t, err)
+	require.Contains(t, running, cID)
+	require.Equal(t, "running", running[cID].State)
+
+	dc, exitCode, err := reattached.ReattachContainer(ctx, cID, events.ChannelPublisher(evs))
+	require.NoError(t, err)
+	require.Nil(t, exitCode)
+	require.NotNil(t, dc)
+
+	res := <-dc.ContainerWaiter.Waiter
+	require.Nil(t, res.Error)
+	require.Equal(t, int64(5), res.StatusCode)
+
+	t.Log("the reattached task's output is shipped from where the previous client stopped")
+	logs = drainLogs(evs)
+	require.Contains(t, logs, "after")
+	require.NotContains(t, logs, "before")
+}
+
+func drainLogs(evs chan docker.Event) []string {
+	var logs []string
+	for len(evs) > 0 {
+		if ev := <-evs; ev.Log != nil {
+			logs = append(logs, ev.Log.Message)
+		}
+	}
+	return logs
+}
@@ -13,13 +13,17 @@ import (
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/agent/pkg/docker"
 	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -242,3 +246,33 @@ func ArchiveMountPoints(ctx context.Context,
 	}
 	return mountPoints, nil
 }
+
+// sigkillExitCode is the exit code of a process killed by SIGKILL, as the kernel's OOM killer does.
+const sigkillExitCode = 128 + aproto.ExitCode(syscall.SIGKILL)
+
+// ResourcesArgs returns the arguments that apply the container's CPU and memory requests and
+// limits, for runtimes whose `run` takes the same flags as `docker run`.
+func ResourcesArgs(r dcontainer.Resources, args []string) []string {
+	if r.CPUShares > 0 {
+		args = append(args, "--cpu-shares", strconv.FormatInt(r.CPUShares, 10))
+	}
+	if r.NanoCPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(r.NanoCPUs)/1e9, 'f', -1, 64))
+	}
+	if r.Memory > 0 {
+		args = append(args, "--memory", strconv.FormatInt(r.Memory, 10))
+	}
+	if r.MemoryReservation > 0 {
+		args = append(args, "--memory-reservation", strconv.FormatInt(r.MemoryReservation, 10))
+	}
+	return args
+}
+
+// OOMKilledHeuristic returns a guess of whether the container was killed because it ran out of
+// memory, for runtimes that don't report it: it was, if it had a memory limit and was killed by
+// SIGKILL.
+func OOMKilledHeuristic(r dcontainer.Resources) func(context.Context, aproto.ExitCode) bool {
+	return func(_ context.Context, exitCode aproto.ExitCode) bool {
+		return r.Memory > 0 && exitCode == sigkillExitCode
+	}
+}
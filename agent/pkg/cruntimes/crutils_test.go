@@ -0,0 +1,58 @@
+package cruntimes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+func Test_ResourcesArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources container.Resources
+		want      []string
+	}{
+		{
+			name: "No requests or limits",
+			want: []string{"run"},
+		},
+		{
+			name: "CPU and memory limits",
+			resources: container.Resources{
+				CPUShares:         512,
+				NanoCPUs:          1_500_000_000,
+				Memory:            8 << 30,
+				MemoryReservation: 4 << 30,
+			},
+			want: []string{
+				"run", "--cpu-shares", "512", "--cpus", "1.5", "--memory", "8589934592",
+				"--memory-reservation", "4294967296",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResourcesArgs(tt.resources, []string{"run"}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResourcesArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_OOMKilledHeuristic(t *testing.T) {
+	ctx := context.Background()
+	limited := OOMKilledHeuristic(container.Resources{Memory: 1 << 30})
+	if !limited(ctx, aproto.ExitCode(137)) {
+		t.Error("expected SIGKILL of a memory-limited container to be reported as out of memory")
+	}
+	if limited(ctx, aproto.ExitCode(1)) {
+		t.Error("expected a failure other than SIGKILL not to be reported as out of memory")
+	}
+	if OOMKilledHeuristic(container.Resources{})(ctx, aproto.ExitCode(137)) {
+		t.Error("expected SIGKILL of an unlimited container not to be reported as out of memory")
+	}
+}
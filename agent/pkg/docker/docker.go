@@ -9,6 +9,7 @@ import (
 	"io"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
@@ -53,15 +54,21 @@ const (
 
 	// ImagePullStatsKind describes the IMAGEPULL event.
 	ImagePullStatsKind = "IMAGEPULL"
+
+	// oomEventGracePeriod is how long to wait for the out of memory event of a container that
+	// exited and was removed.
+	oomEventGracePeriod = time.Second
 )
 
 type (
 	// ContainerWaiter contains channels to wait on the termination of a running container.
 	// Results on the Waiter channel indicate changes in container state, while results on the
-	// Errs channel indicate failures to watch for updates.
+	// Errs channel indicate failures to watch for updates. OOMKilled, if set, reports whether a
+	// container that exited with the given code was killed because it ran out of memory.
 	ContainerWaiter struct {
-		Waiter <-chan dcontainer.ContainerWaitOKBody
-		Errs   <-chan error
+		Waiter    <-chan dcontainer.ContainerWaitOKBody
+		Errs      <-chan error
+		OOMKilled func(context.Context, aproto.ExitCode) bool
 	}
 	// Container contains details about a running container and waiters to await its termination.
 	Container struct {
@@ -117,6 +124,7 @@ func (d *Client) Inner() *client.Client {
 func (d *Client) ReattachContainer(
 	ctx context.Context,
 	id cproto.ID,
+	p events.Publisher[Event],
 ) (*Container, *aproto.ExitCode, error) {
 	filter := LabelFilter(ContainerIDLabel, id.String())
 	containers, err := d.cl.ContainerList(ctx, types.ContainerListOptions{Filters: filter})
@@ -145,8 +153,9 @@ func (d *Client) ReattachContainer(
 		return &Container{ //nolint: staticcheck // We mean to terminate this loop.
 			ContainerInfo: containerInfo,
 			ContainerWaiter: ContainerWaiter{
-				Waiter: waiter,
-				Errs:   errs,
+				Waiter:    waiter,
+				Errs:      errs,
+				OOMKilled: d.watchOOM(ctx, cont.ID),
 			},
 		}, nil, nil
 	}
@@ -297,6 +306,7 @@ func (d *Client) RunContainer(
 ) (*Container, error) {
 	// Wait before start to not miss immediate exits.
 	waiter, errs := d.cl.ContainerWait(waitCtx, id, dcontainer.WaitConditionNextExit)
+	oomKilled := d.watchOOM(waitCtx, id)
 
 	if err := d.cl.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
 		return nil, fmt.Errorf("starting container: %w", err)
@@ -315,12 +325,52 @@ func (d *Client) RunContainer(
 	return &Container{
 		ContainerInfo: containerInfo,
 		ContainerWaiter: ContainerWaiter{
-			Waiter: waiter,
-			Errs:   errs,
+			Waiter:    waiter,
+			Errs:      errs,
+			OOMKilled: oomKilled,
 		},
 	}, nil
 }
 
+// watchOOM watches for the container, by docker container ID, running out of memory for the
+// lifetime of the context, and returns a func that reports whether it was killed because it did.
+// The container's state is authoritative, but it is gone once auto-removed, so the events are
+// the fallback.
+func (d *Client) watchOOM(ctx context.Context, id string) func(context.Context, aproto.ExitCode) bool {
+	msgs, errs := d.cl.Events(ctx, types.EventsOptions{Filters: filters.NewArgs(
+		filters.Arg("type", "container"),
+		filters.Arg("container", id),
+		filters.Arg("event", "oom"),
+	)})
+	oom := make(chan struct{})
+	go func() {
+		select {
+		case <-msgs:
+			close(oom)
+		case err := <-errs:
+			if ctx.Err() == nil {
+				d.log.WithError(err).Warnf("watching container %s for out of memory events", id)
+			}
+		case <-ctx.Done():
+		}
+	}()
+
+	return func(ctx context.Context, _ aproto.ExitCode) bool {
+		if info, err := d.cl.ContainerInspect(ctx, id); err == nil {
+			return info.State.OOMKilled
+		}
+		// The event precedes the container's exit, but may not have been received yet.
+		select {
+		case <-oom:
+			return true
+		case <-time.After(oomEventGracePeriod):
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
 // SignalContainer signals the container, by docker container ID, with the requested signal,
 // returning an error if the Docker daemon is unable to process our request.
 func (d *Client) SignalContainer(ctx context.Context, id string, sig syscall.Signal) error {
@@ -355,6 +405,23 @@ func (d *Client) ListRunningContainers(ctx context.Context, fs filters.Args) (
 	return result, nil
 }
 
+// ListImages lists the Docker images on the host, along with the total size of their layers.
+func (d *Client) ListImages(ctx context.Context) ([]*types.ImageSummary, int64, error) {
+	du, err := d.cl.DiskUsage(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return du.Images, du.LayersSize, nil
+}
+
+// RemoveImage removes a Docker image by ID, along with all of its tags and its untagged parents.
+// Removing an image with several tags by ID has to be forced; Docker still refuses to remove an
+// image that a running container uses, but callers must check for stopped containers using it.
+func (d *Client) RemoveImage(ctx context.Context, id string) error {
+	_, err := d.cl.ImageRemove(ctx, id, types.ImageRemoveOptions{Force: true, PruneChildren: true})
+	return err
+}
+
 // LabelFilter is a convenience that takes a key and value and returns a docker label filter.
 func LabelFilter(key, val string) filters.Args {
 	return filters.NewArgs(filters.Arg("label", key+"="+val))
@@ -265,7 +265,9 @@ func TestRunContainerWithService(t *testing.T) {
 	require.True(t, found, "did not find our container")
 
 	t.Log("ensure it can be reattached")
-	reattached, terminated, err := cl.ReattachContainer(ctx, cproto.ID(containerID))
+	reattached, terminated, err := cl.ReattachContainer(
+		ctx, cproto.ID(containerID), events.NilPublisher[docker.Event]{},
+	)
 	require.NoError(t, err)
 	require.Nil(t, terminated)
 
@@ -254,6 +254,7 @@ func (s *PodmanClient) RunContainer(
 		args = append(args, "--shm-size", fmt.Sprintf("%d", shmsize))
 	}
 
+	args = cruntimes.ResourcesArgs(req.HostConfig.Resources, args)
 	args = capabilitiesToPodmanArgs(req, args)
 
 	image := cruntimes.CanonicalizeImage(req.ContainerConfig.Image)
@@ -345,6 +346,7 @@ func hostMountsToPodmanArgs(m mount.Mount, args []string) []string {
 func (s *PodmanClient) ReattachContainer(
 	ctx context.Context,
 	reattachID cproto.ID,
+	p events.Publisher[docker.Event],
 ) (*docker.Container, *aproto.ExitCode, error) {
 	return nil, nil, container.ErrMissing
 }
@@ -458,7 +460,11 @@ func (s *PodmanClient) waitOnContainer(
 			}
 		}
 	})
-	return docker.ContainerWaiter{Waiter: wchan, Errs: errchan}
+	return docker.ContainerWaiter{
+		Waiter:    wchan,
+		Errs:      errchan,
+		OOMKilled: cruntimes.OOMKilledHeuristic(cont.Req.HostConfig.Resources),
+	}
 }
 
 func (s *PodmanClient) shipPodmanCmdLogs(
@@ -329,6 +329,7 @@ func (s *SingularityClient) RunContainer(
 		}
 	}
 
+	args = cruntimes.ResourcesArgs(req.HostConfig.Resources, args)
 	args = capabilitiesToSingularityArgs(req, args)
 	image := s.computeImageReference(req.ContainerConfig.Image)
 	args = append(args, image)
@@ -480,6 +481,7 @@ func capabilitiesToSingularityArgs(req cproto.RunSpec, args []string) []string {
 func (s *SingularityClient) ReattachContainer(
 	ctx context.Context,
 	reattachID cproto.ID,
+	p events.Publisher[docker.Event],
 ) (*docker.Container, *aproto.ExitCode, error) {
 	return nil, nil, container.ErrMissing
 }
@@ -593,7 +595,11 @@ func (s *SingularityClient) waitOnContainer(
 			}
 		}
 	})
-	return docker.ContainerWaiter{Waiter: wchan, Errs: errchan}
+	return docker.ContainerWaiter{
+		Waiter:    wchan,
+		Errs:      errchan,
+		OOMKilled: cruntimes.OOMKilledHeuristic(cont.Req.HostConfig.Resources),
+	}
 }
 
 func (s *SingularityClient) shipSingularityCmdLogs(
@@ -2,12 +2,12 @@ import json
 import os
 from typing import Any, Dict, Iterable, List, Optional, Union
 
-from determined import gpu
+from determined import constants, gpu
 
-DEFAULT_RENDEZVOUS_INFO_PATH = "/run/determined/info/rendezvous.json"
-DEFAULT_TRIAL_INFO_PATH = "/run/determined/info/trial.json"
-DEFAULT_RESOURCES_INFO_PATH = "/run/determined/info/resources.json"
-DEFAULT_CLUSTER_INFO_PATH = "/run/determined/info/cluster.json"
+DEFAULT_RENDEZVOUS_INFO_PATH = f"{constants.RUN_DIR}/info/rendezvous.json"
+DEFAULT_TRIAL_INFO_PATH = f"{constants.RUN_DIR}/info/trial.json"
+DEFAULT_RESOURCES_INFO_PATH = f"{constants.RUN_DIR}/info/resources.json"
+DEFAULT_CLUSTER_INFO_PATH = f"{constants.RUN_DIR}/info/cluster.json"
 
 
 def getenv_int(key: str) -> Optional[int]:
@@ -4,7 +4,7 @@ import operator
 import os
 import sys
 import typing
-from typing import Any, Callable, Dict, List
+from typing import Any, Callable, Dict, List, Optional
 
 import determined.cli.render
 from determined import cli
@@ -37,6 +37,7 @@ def list_agents(args: argparse.Namespace) -> None:
                 ),
                 ("enabled", a.enabled),
                 ("draining", a.draining),
+                ("ready_for_maintenance", a.readyForMaintenance),
                 ("addresses", ", ".join(a.addresses) if a.addresses is not None else ""),
             ]
         )
@@ -56,6 +57,7 @@ def list_agents(args: argparse.Namespace) -> None:
         "Resource Pool",
         "Enabled",
         "Draining",
+        "Ready for Maintenance",
         "Addresses",
     ]
     values = [a.values() for a in agents]
@@ -125,6 +127,7 @@ def list_slots(args: argparse.Namespace) -> None:
                 ("task_name", get_task_name(c_names, slot)),
                 ("type", device_type_string((slot.device or bindings.v1Device()).type)),
                 ("device", (slot.device or bindings.v1Device()).brand),
+                ("unhealthy_reason", slot.unhealthyReason or ""),
             ]
         )
         for agent in sorted(resp.agents or [], key=operator.attrgetter("id"))
@@ -141,6 +144,7 @@ def list_slots(args: argparse.Namespace) -> None:
         "Task Name",
         "Type",
         "Device",
+        "Unhealthy Reason",
     ]
 
     if args.json:
@@ -170,16 +174,20 @@ def patch_agent(enabled: bool) -> Callable[[argparse.Namespace], None]:
             resp = bindings.get_GetAgents(cli.setup_session(args))
             agent_ids = sorted(local_id(a.id) for a in resp.agents or [])
 
-        drain_mode = None if enabled else args.drain
+        migrate = not enabled and args.migrate
+        drain_mode = None if enabled else args.drain or migrate
 
         for agent_id in agent_ids:
             path = f"api/v1/agents/{agent_id}/{action}"
 
-            payload = None
+            payload: Optional[Dict[str, Any]] = None
             if not enabled and drain_mode:
                 payload = {
                     "drain": drain_mode,
                 }
+                if migrate:
+                    payload["migrate"] = True
+                    payload["migrationTimeoutSeconds"] = args.migration_timeout
 
             api.post(args.master, path, payload)
             status = "Disabled" if not enabled else "Enabled"
@@ -260,6 +268,12 @@ args_description = [
             Arg("--drain", action="store_true",
                 help="enter drain mode, allowing the tasks currently running on "
                      "the disabled agents to finish. will also print these tasks, if any"),
+            Arg("--migrate", action="store_true",
+                help="enter drain mode and ask the tasks currently running on the disabled "
+                     "agents to checkpoint and move to other agents, killing those that are "
+                     "still running after the migration timeout"),
+            Arg("--migration-timeout", type=int, default=600,
+                help="seconds to wait for migrating tasks before they are killed"),
             Group(
                 Arg("--csv", action="store_true", help="print as CSV"),
                 Arg("--json", action="store_true", help="print as JSON"),
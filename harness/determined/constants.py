@@ -56,8 +56,12 @@ HOROVOD_AUTOTUNE_LOG_FILEPATH = "/tmp/autotune_log.csv"
 # large number of machines.
 HOROVOD_GLOO_TIMEOUT_SECONDS = 240
 
+# The directory of the files the master places in a task's container. Tasks that the agent runs
+# directly on its host, rather than in a container, find them under the task root the agent sets.
+RUN_DIR = os.environ.get("DET_TASK_ROOT", "") + "/run/determined"
+
 # The well-known locations of the executing container's STDOUT and STDERR.
-CONTAINER_STDOUT = "/run/determined/train/logs/stdout.log"
-CONTAINER_STDERR = "/run/determined/train/logs/stderr.log"
+CONTAINER_STDOUT = f"{RUN_DIR}/train/logs/stdout.log"
+CONTAINER_STDERR = f"{RUN_DIR}/train/logs/stderr.log"
 
-MANAGED_TRAINING_MODEL_COPY = "/run/determined/train/model"
+MANAGED_TRAINING_MODEL_COPY = f"{RUN_DIR}/train/model"
@@ -1,7 +1,7 @@
 import enum
 import logging
 import pathlib
-from typing import Any, Callable, Dict, List, Optional, Set
+from typing import Any, Callable, Dict, List, Optional, Sequence, Set, Tuple
 
 import determined as det
 from determined import core, tensorboard
@@ -10,6 +10,9 @@ from determined.common.api import bindings, errors
 
 logger = logging.getLogger("determined.core")
 
+# A histogram metric, as a pair of bucket edges and the count of values in each bucket.
+Histogram = Tuple[Sequence[float], Sequence[float]]
+
 
 class EarlyExitReason(enum.Enum):
     INVALID_HP = "EXITED_REASON_INVALID_HP"
@@ -74,6 +77,7 @@ class TrainContext:
         steps_completed: int,
         metrics: Dict[str, Any],
         batch_metrics: Optional[List[Dict[str, Any]]] = None,
+        histograms: Optional[Dict[str, Histogram]] = None,
     ) -> None:
         """
         Report trial metrics to the master.
@@ -88,7 +92,15 @@ class TrainContext:
             serializable_metrics = self._get_serializable_metrics(metrics)
             reportable_metrics = {k: metrics[k] for k in serializable_metrics}
 
-        v1metrics = bindings.v1Metrics(avgMetrics=reportable_metrics, batchMetrics=batch_metrics)
+        v1histograms = None
+        if histograms:
+            v1histograms = {
+                name: bindings.v1Histogram(bucketEdges=list(edges), counts=list(counts))
+                for name, (edges, counts) in histograms.items()
+            }
+        v1metrics = bindings.v1Metrics(
+            avgMetrics=reportable_metrics, batchMetrics=batch_metrics, histograms=v1histograms
+        )
         v1TrialMetrics = bindings.v1TrialMetrics(
             metrics=v1metrics,
             stepsCompleted=steps_completed,
@@ -141,6 +153,7 @@ class TrainContext:
         group: str,
         steps_completed: int,
         metrics: Dict[str, Any],
+        histograms: Optional[Dict[str, Histogram]] = None,
     ) -> None:
         """
         Report metrics data to the master.
@@ -154,8 +167,12 @@ class TrainContext:
             metrics (Dict[str, Any]): metrics data dictionary. Must be JSON-serializable.
                 When reporting metrics with the same ``group`` and ``steps_completed`` values,
                 the dictionary keys must not overlap.
+            histograms (Dict[str, Tuple[Sequence[float], Sequence[float]]], optional):
+                distributions, such as of weights or gradients, by name. Each is a pair of
+                strictly increasing bucket edges and the count of values in each bucket, as
+                returned by ``numpy.histogram``. Names must not overlap with ``metrics``.
         """
-        self._report_trial_metrics(group, steps_completed, metrics)
+        self._report_trial_metrics(group, steps_completed, metrics, histograms=histograms)
 
     def get_tensorboard_path(self) -> pathlib.Path:
         """
@@ -265,6 +282,7 @@ class DummyTrainContext(TrainContext):
         steps_completed: int,
         metrics: Dict[str, Any],
         batch_metrics: Optional[List[Dict[str, Any]]] = None,
+        histograms: Optional[Dict[str, Histogram]] = None,
     ) -> None:
         """
         Report trial metrics to the master.
@@ -292,8 +310,14 @@ class DummyTrainContext(TrainContext):
     def report_validation_metrics(self, steps_completed: int, metrics: Dict[str, Any]) -> None:
         self._report_trial_metrics(util._LEGACY_VALIDATION, steps_completed, metrics)
 
-    def report_metrics(self, group: str, steps_completed: int, metrics: Dict[str, Any]) -> None:
-        self._report_trial_metrics(group, steps_completed, metrics)
+    def report_metrics(
+        self,
+        group: str,
+        steps_completed: int,
+        metrics: Dict[str, Any],
+        histograms: Optional[Dict[str, Histogram]] = None,
+    ) -> None:
+        self._report_trial_metrics(group, steps_completed, metrics, histograms=histograms)
 
     def upload_tensorboard_files(
         self,
@@ -126,7 +126,7 @@ def create_sshd_cmd() -> List[str]:
         "-p",
         str(constants.DTRAIN_SSH_PORT),
         "-f",
-        "/run/determined/ssh/sshd_config",
+        f"{constants.RUN_DIR}/ssh/sshd_config",
         "-D",
     ]
 
@@ -16,7 +16,7 @@ import determined as det
 from determined import horovod, util
 from determined.common import api
 from determined.common.api import certs
-from determined.constants import DTRAIN_SSH_PORT
+from determined.constants import DTRAIN_SSH_PORT, RUN_DIR
 
 
 def create_sshd_worker_cmd(
@@ -45,7 +45,7 @@ def create_sshd_worker_cmd(
         "-p",
         str(DTRAIN_SSH_PORT),
         "-f",
-        "/run/determined/ssh/sshd_config",
+        f"{RUN_DIR}/ssh/sshd_config",
         "-D",
     ]
     if debug:
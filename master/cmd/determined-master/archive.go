@@ -0,0 +1,119 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+func newExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export ARCHIVE",
+		Short: "export users, workspaces, projects, experiments, models and templates to an archive",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runExport(args[0]); err != nil {
+				log.Error(fmt.Sprintf("%+v", err))
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func newImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import ARCHIVE",
+		Short: "import an archive written by export, migrating the db first",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runImport(args[0]); err != nil {
+				log.Error(fmt.Sprintf("%+v", err))
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runExport(path string) (err error) {
+	database, err := connectArchiveDB(false)
+	if err != nil {
+		return err
+	}
+	defer closeDB(database)
+
+	f, err := os.Create(path) // #nosec G304
+	if err != nil {
+		return errors.Wrap(err, "creating archive")
+	}
+	defer func() {
+		if errc := f.Close(); err == nil {
+			err = errc
+		}
+	}()
+
+	w := gzip.NewWriter(f)
+	if err := db.Export(context.TODO(), w); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "writing archive")
+	}
+	log.Infof("exported to %s", path)
+	return nil
+}
+
+func runImport(path string) error {
+	database, err := connectArchiveDB(true)
+	if err != nil {
+		return err
+	}
+	defer closeDB(database)
+
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return errors.Wrap(err, "opening archive")
+	}
+	defer func() {
+		if errc := f.Close(); errc != nil {
+			log.Errorf("error closing archive: %s", errc)
+		}
+	}()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return errors.Wrap(err, "reading archive")
+	}
+	if err := db.Import(context.TODO(), r); err != nil {
+		return err
+	}
+	log.Infof("imported %s", path)
+	return nil
+}
+
+// connectArchiveDB connects to the database configured for the master. Imports run the
+// migrations first, so that the schema matches the archive.
+func connectArchiveDB(migrate bool) (*db.PgDB, error) {
+	if err := initializeConfig(); err != nil {
+		return nil, err
+	}
+
+	masterConfig := config.GetMasterConfig()
+	if migrate {
+		return db.Setup(&masterConfig.DB)
+	}
+	return db.Connect(&masterConfig.DB)
+}
+
+func closeDB(database *db.PgDB) {
+	if err := database.Close(); err != nil {
+		log.Errorf("error closing pg connection: %s", err)
+	}
+}
@@ -14,41 +14,116 @@ import (
 )
 
 func newMigrateCmd() *cobra.Command {
-	return &cobra.Command{
+	var dryRun bool
+	cmd := &cobra.Command{
 		Use:   "migrate",
 		Short: "migrate the db",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := runMigrate(cmd, args); err != nil {
+			if err := runMigrate(args, dryRun); err != nil {
 				log.Error(fmt.Sprintf("%+v", err))
 				os.Exit(1)
 			}
 		},
 	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"list pending migrations and the table locks they take without applying them")
+	cmd.AddCommand(newMigrateDownCmd())
+	return cmd
 }
 
-func runMigrate(cmd *cobra.Command, args []string) error {
+func newMigrateDownCmd() *cobra.Command {
+	var to int64
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "revert migrations newer than --to in a single transaction",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runMigrateDown(cmd.Flags().Changed("to"), to, force); err != nil {
+				log.Error(fmt.Sprintf("%+v", err))
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Int64Var(&to, "to", 0,
+		"migration version to revert to (defaults to reverting only the latest migration)")
+	cmd.Flags().BoolVar(&force, "force", false,
+		"revert even if a running master expects a newer migration version")
+	return cmd
+}
+
+func runMigrate(args []string, dryRun bool) error {
 	logStore := logger.NewLogBuffer(logStoreSize)
 	log.AddHook(logStore)
 
-	err := initializeConfig()
+	database, err := connectMigrateDB()
 	if err != nil {
 		return err
 	}
+	defer closeDB(database)
 
-	config := config.GetMasterConfig()
-	database, err := db.Connect(&config.DB)
+	migrations := config.GetMasterConfig().DB.Migrations
+	if dryRun {
+		return printPendingMigrations(database, migrations)
+	}
+
+	if err = database.Migrate(migrations, args); err != nil {
+		return errors.Wrap(err, "running migrations")
+	}
+
+	return nil
+}
+
+func printPendingMigrations(database *db.PgDB, migrations string) error {
+	current, pending, err := database.PendingMigrations(migrations)
 	if err != nil {
-		return err
+		return errors.Wrap(err, "listing pending migrations")
 	}
-	defer func() {
-		if errd := database.Close(); errd != nil {
-			log.Errorf("error closing pg connection: %s", errd)
+
+	fmt.Printf("current version: %d\n", current)
+	if len(pending) == 0 {
+		fmt.Println("no pending migrations")
+		return nil
+	}
+	fmt.Printf("%d pending migrations:\n", len(pending))
+	for _, m := range pending {
+		fmt.Printf("  %d %s\n", m.Version, m.Name)
+		for _, l := range m.Locks {
+			fmt.Printf("      %s lock on %s: %s\n", l.Lock, l.Table, l.Statement)
 		}
-	}()
+	}
+	return nil
+}
 
-	if err = database.Migrate(config.DB.Migrations, args); err != nil {
-		return errors.Wrap(err, "running migrations")
+func runMigrateDown(hasTarget bool, target int64, force bool) error {
+	database, err := connectMigrateDB()
+	if err != nil {
+		return err
 	}
+	defer closeDB(database)
 
+	migrations := config.GetMasterConfig().DB.Migrations
+	if !hasTarget {
+		if target, err = database.PreviousMigrationVersion(migrations); err != nil {
+			return err
+		}
+	}
+
+	oldVersion, newVersion, err := database.MigrateDown(migrations, target, force)
+	if err != nil {
+		return errors.Wrap(err, "reverting migrations")
+	}
+	if oldVersion == newVersion {
+		log.Infof("no migrations to revert; version: %d", newVersion)
+	} else {
+		log.Infof("migrated down from %d to %d", oldVersion, newVersion)
+	}
 	return nil
 }
+
+func connectMigrateDB() (*db.PgDB, error) {
+	if err := initializeConfig(); err != nil {
+		return nil, err
+	}
+	return db.Connect(&config.GetMasterConfig().DB)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,8 @@ func newRootCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newMigrateCmd())
 	cmd.AddCommand(newPopulateCmd())
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newImportCmd())
 	return cmd
 }
 
@@ -62,6 +65,7 @@ func runRoot() error {
 	}
 
 	m := internal.New(logStore, config)
+	m.SetConfigLoader(reloadConfig)
 	return m.Run(context.TODO())
 }
 
@@ -98,6 +102,37 @@ func initializeConfig() error {
 	return nil
 }
 
+// reloadConfig re-reads the config file and returns the validated configuration it produces
+// together with the environment variables and command line flags the master started with.
+func reloadConfig() (*config.Config, error) {
+	current := config.GetMasterConfig()
+	bs, err := readConfigFile(current.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop the values merged in from the previous read of the config file, so that settings
+	// removed from the file fall back to their defaults.
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(nil)); err != nil {
+		return nil, errors.Wrap(err, "resetting configuration")
+	}
+
+	conf, err := mergeConfigIntoViper(bs)
+	if err != nil {
+		return nil, err
+	}
+	if err := check.Validate(conf); err != nil {
+		return nil, err
+	}
+
+	// An unset signing key is randomly generated on each read; keep the one already in use.
+	if v.GetString(configKey{"webhooks", "signing-key"}.AccessPath()) == "" {
+		conf.Webhooks.SigningKey = current.Webhooks.SigningKey
+	}
+	return conf, nil
+}
+
 func mergeConfigIntoViper(bs []byte) (*config.Config, error) {
 	// Write a configMap from the config file, and create a copy (cpMap) to
 	// deepcopy values needed to override viper's merge auto-lowercasing.
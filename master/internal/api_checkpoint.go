@@ -334,7 +334,7 @@ func (a *apiServer) CheckpointsRemoveFiles(
 			registeredCheckpointUUIDs)
 	}
 
-	taskSpec := *a.m.taskSpec
+	taskSpec := *a.m.taskSpec.Load()
 
 	var expIDs []int
 	for _, g := range groupCUUIDsByEIDs {
@@ -65,6 +65,7 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 	aUser *model.User) (
 	*tasks.GenericCommandSpec, []pkgCommand.LaunchWarning, error,
 ) {
+	conf := a.m.config.Load()
 	var err error
 	cmdSpec := tasks.GenericCommandSpec{}
 
@@ -117,8 +118,8 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 	if err != nil {
 		return nil, launchWarnings, fmt.Errorf("checking resource availability: %v", err.Error())
 	}
-	if a.m.config.ResourceManager.AgentRM != nil &&
-		a.m.config.LaunchError &&
+	if conf.ResourceManager.AgentRM != nil &&
+		conf.LaunchError &&
 		len(launchWarnings) > 0 {
 		return nil, nil, errors.New("slots requested exceeds cluster capacity")
 	}
@@ -126,12 +127,12 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 	taskContainerDefaults, err := a.m.rm.TaskContainerDefaults(
 		a.m.system,
 		poolName,
-		a.m.config.TaskContainerDefaults,
+		conf.TaskContainerDefaults,
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("getting TaskContainerDefaults: %v", err)
 	}
-	taskSpec := *a.m.taskSpec
+	taskSpec := *a.m.taskSpec.Load()
 	taskSpec.TaskContainerDefaults = taskContainerDefaults
 	taskSpec.AgentUserGroup = agentUserGroup
 	taskSpec.Owner = userModel
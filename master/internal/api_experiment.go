@@ -18,6 +18,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
@@ -445,7 +446,7 @@ func (a *apiServer) DeleteExperiments(
 func (a *apiServer) deleteExperiments(exps []*model.Experiment, userModel *model.User) ([]int,
 	error,
 ) {
-	taskSpec := *a.m.taskSpec
+	taskSpec := *a.m.taskSpec.Load()
 
 	sema := make(chan struct{}, maxConcurrentDeletes)
 	wg := sync.WaitGroup{}
@@ -1291,7 +1292,7 @@ func (a *apiServer) PatchExperiment(
 				return nil, errors.Errorf("cannot find user %v who owns experiment", modelExp.OwnerID)
 			}
 
-			taskSpec := *a.m.taskSpec
+			taskSpec := *a.m.taskSpec.Load()
 			user := &model.User{
 				ID:       ownerFullUser.ID,
 				Username: ownerFullUser.Username,
@@ -1479,10 +1480,15 @@ func (a *apiServer) CreateExperiment(
 		}
 	}
 
+	_, span := tracer.Start(ctx, "experiment.create")
 	e, launchWarnings, err := newExperiment(a.m, dbExp, activeConfig, taskSpec, a.m.system)
 	if err != nil {
+		span.RecordError(err)
+		span.End()
 		return nil, status.Errorf(codes.Internal, "failed to create experiment: %s", err)
 	}
+	span.SetAttributes(attribute.Int("determined.experiment_id", e.ID))
+	span.End()
 	a.m.system.ActorOf(exputil.ExperimentsAddr.Child(e.ID), e)
 
 	if req.Activate {
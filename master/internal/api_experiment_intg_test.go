@@ -192,9 +192,9 @@ func TestDeleteExperimentWithoutCheckpoints(t *testing.T) {
 // nolint: exhaustivestruct
 func TestCreateExperimentCheckpointStorage(t *testing.T) {
 	api, _, ctx := setupAPITest(t, nil)
-	api.m.config.CheckpointStorage = expconf.CheckpointStorageConfig{}
+	api.m.config.Load().CheckpointStorage = expconf.CheckpointStorageConfig{}
 	defer func() {
-		api.m.config.CheckpointStorage = expconf.CheckpointStorageConfig{}
+		api.m.config.Load().CheckpointStorage = expconf.CheckpointStorageConfig{}
 	}()
 
 	conf := `
@@ -250,7 +250,7 @@ resources:
 	require.Equal(t, expected, resp.Config.AsMap()["checkpoint_storage"])
 
 	// Checkpoint specified in master config.
-	api.m.config.CheckpointStorage = expconf.CheckpointStorageConfig{
+	api.m.config.Load().CheckpointStorage = expconf.CheckpointStorageConfig{
 		RawS3Config: &expconf.S3Config{
 			RawBucket:    ptrs.Ptr("masterbucket"),
 			RawSecretKey: ptrs.Ptr("mastersecret"),
@@ -2,12 +2,12 @@ package internal
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
 	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -29,26 +29,27 @@ var masterLogsBatchMissWaitTime = time.Second
 func (a *apiServer) GetMaster(
 	_ context.Context, _ *apiv1.GetMasterRequest,
 ) (*apiv1.GetMasterResponse, error) {
+	conf := a.m.config.Load()
 	product := apiv1.GetMasterResponse_PRODUCT_UNSPECIFIED
-	if a.m.config.InternalConfig.ExternalSessions.Enabled() {
+	if conf.InternalConfig.ExternalSessions.Enabled() {
 		product = apiv1.GetMasterResponse_PRODUCT_COMMUNITY
 	}
 	masterResp := &apiv1.GetMasterResponse{
 		Version:               version.Version,
 		MasterId:              a.m.MasterID,
 		ClusterId:             a.m.ClusterID,
-		ClusterName:           a.m.config.ClusterName,
-		TelemetryEnabled:      a.m.config.Telemetry.Enabled && a.m.config.Telemetry.SegmentWebUIKey != "",
-		ExternalLoginUri:      a.m.config.InternalConfig.ExternalSessions.LoginURI,
-		ExternalLogoutUri:     a.m.config.InternalConfig.ExternalSessions.LogoutURI,
+		ClusterName:           conf.ClusterName,
+		TelemetryEnabled:      conf.Telemetry.Enabled && conf.Telemetry.SegmentWebUIKey != "",
+		ExternalLoginUri:      conf.InternalConfig.ExternalSessions.LoginURI,
+		ExternalLogoutUri:     conf.InternalConfig.ExternalSessions.LogoutURI,
 		Branding:              "determined",
 		RbacEnabled:           config.GetAuthZConfig().IsRBACUIEnabled(),
 		StrictJobQueueControl: config.GetAuthZConfig().StrictJobQueueControl,
 		Product:               product,
-		UserManagementEnabled: !a.m.config.InternalConfig.ExternalSessions.Enabled(),
-		FeatureSwitches:       a.m.config.FeatureSwitches,
+		UserManagementEnabled: !conf.InternalConfig.ExternalSessions.Enabled(),
+		FeatureSwitches:       conf.FeatureSwitches,
 	}
-	sso.AddProviderInfoToMasterResponse(a.m.config, masterResp)
+	sso.AddProviderInfoToMasterResponse(conf, masterResp)
 
 	return masterResp, nil
 }
@@ -56,10 +57,11 @@ func (a *apiServer) GetMaster(
 func (a *apiServer) GetTelemetry(
 	_ context.Context, _ *apiv1.GetTelemetryRequest,
 ) (*apiv1.GetTelemetryResponse, error) {
+	conf := a.m.config.Load()
 	resp := apiv1.GetTelemetryResponse{}
-	if a.m.config.Telemetry.Enabled && a.m.config.Telemetry.SegmentWebUIKey != "" {
+	if conf.Telemetry.Enabled && conf.Telemetry.SegmentWebUIKey != "" {
 		resp.Enabled = true
-		resp.SegmentKey = a.m.config.Telemetry.SegmentWebUIKey
+		resp.SegmentKey = conf.Telemetry.SegmentWebUIKey
 	}
 	return &resp, nil
 }
@@ -79,7 +81,7 @@ func (a *apiServer) GetMasterConfig(
 		return nil, permErr
 	}
 
-	config, err := a.m.config.Printable()
+	config, err := a.m.config.Load().Printable()
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing master config")
 	}
@@ -107,30 +109,32 @@ func (a *apiServer) PatchMasterConfig(
 
 	paths := req.FieldMask.GetPaths()
 
+	a.m.configLock.Lock()
+	defer a.m.configLock.Unlock()
+	next := *a.m.config.Load()
 	for _, path := range paths {
 		switch path {
 		case "log":
 			if !isValidLogLevel(req.Config.Log.Level) {
-				panic(fmt.Sprintf("invalid log level: %s", req.Config.Log.Level))
+				return nil, status.Errorf(codes.InvalidArgument, "invalid log level: %s", req.Config.Log.Level)
 			}
-			a.m.config.Log.Level = req.Config.Log.Level
-			a.m.config.Log.Color = req.Config.Log.Color
-			logger.SetLogrus(a.m.config.Log)
+			next.Log.Level = req.Config.Log.Level
+			next.Log.Color = req.Config.Log.Color
 		case "log.level":
 			if !isValidLogLevel(req.Config.Log.Level) {
-				panic(fmt.Sprintf("invalid log level: %s", req.Config.Log.Level))
+				return nil, status.Errorf(codes.InvalidArgument, "invalid log level: %s", req.Config.Log.Level)
 			}
-			a.m.config.Log.Level = req.Config.Log.Level
-			logger.SetLogrus(a.m.config.Log)
+			next.Log.Level = req.Config.Log.Level
 		case "log.color":
-			a.m.config.Log.Color = req.Config.Log.Color
-			logger.SetLogrus(a.m.config.Log)
+			next.Log.Color = req.Config.Log.Color
 		default:
-			panic(fmt.Sprintf("unsupported or invalid field: %s", path))
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported or invalid field: %s", path)
 		}
 	}
+	logger.SetLogrus(next.Log)
+	a.m.storeConfig(&next)
 
-	config, err := a.m.config.Printable()
+	config, err := next.Printable()
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing master config")
 	}
@@ -141,6 +145,44 @@ func (a *apiServer) PatchMasterConfig(
 	}, err
 }
 
+func (a *apiServer) ReloadMasterConfig(
+	ctx context.Context, req *apiv1.ReloadMasterConfigRequest,
+) (*apiv1.ReloadMasterConfigResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, u)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	result, err := a.m.reloadConfig()
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to reload master config: %s", err)
+	}
+	log.WithFields(log.Fields{
+		"user":             u.Username,
+		"applied":          result.Applied,
+		"restart_required": result.RestartRequired,
+	}).Info("reloaded master config")
+
+	config, err := a.m.config.Load().Printable()
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing master config")
+	}
+	configStruct := &structpb.Struct{}
+	err = protojson.Unmarshal(config, configStruct)
+	return &apiv1.ReloadMasterConfigResponse{
+		Config:                configStruct,
+		AppliedFields:         result.Applied,
+		RestartRequiredFields: result.RestartRequired,
+	}, err
+}
+
 func (a *apiServer) MasterLogs(
 	req *apiv1.MasterLogsRequest, resp apiv1.Determined_MasterLogsServer,
 ) error {
@@ -253,6 +295,21 @@ func (a *apiServer) ResourceAllocationAggregated(
 	return a.m.fetchAggregatedResourceAllocation(req)
 }
 
+func (a *apiServer) GetCostReport(
+	ctx context.Context,
+	req *apiv1.GetCostReportRequest,
+) (*apiv1.GetCostReportResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.m.canGetUsageDetails(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return a.m.fetchCostReport(ctx, req)
+}
+
 func isValidLogLevel(lvl string) bool {
 	switch strings.ToLower(lvl) {
 	case "fatal", "error", "warn", "info", "debug", "trace":
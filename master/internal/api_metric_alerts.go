@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/internal/webhooks"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// canEditMetricAlertRule checks that the current user can edit the project or experiment the
+// rule is attached to, and the webhook it sends events to.
+func (a *apiServer) canEditMetricAlertRule(
+	ctx context.Context, r *webhooks.MetricAlertRule,
+) error {
+	switch {
+	case r.ProjectID != nil:
+		if _, _, err := a.getProjectAndCheckCanDoActions(ctx, int32(*r.ProjectID),
+			project.AuthZProvider.Get().CanSetProjectNotes); err != nil {
+			return err
+		}
+	case r.ExperimentID != nil:
+		if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, *r.ExperimentID,
+			expauth.AuthZProvider.Get().CanEditExperiment); err != nil {
+			return err
+		}
+	}
+	if r.WebhookID != nil {
+		return webhooks.AuthorizeRequest(ctx)
+	}
+	return nil
+}
+
+func (a *apiServer) GetMetricAlertRules(
+	ctx context.Context, req *apiv1.GetMetricAlertRulesRequest,
+) (*apiv1.GetMetricAlertRulesResponse, error) {
+	var projectID, experimentID *int
+	switch {
+	case req.ProjectId != nil:
+		if _, _, err := a.getProjectAndCheckCanDoActions(ctx, *req.ProjectId); err != nil {
+			return nil, err
+		}
+		id := int(*req.ProjectId)
+		projectID = &id
+	case req.ExperimentId != nil:
+		if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(*req.ExperimentId),
+			expauth.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+			return nil, err
+		}
+		id := int(*req.ExperimentId)
+		experimentID = &id
+	default:
+		// Listing every rule in the cluster is limited to webhook admins.
+		if err := webhooks.AuthorizeRequest(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	rules, err := webhooks.GetMetricAlertRules(ctx, projectID, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetMetricAlertRulesResponse{Rules: rules.Proto()}, nil
+}
+
+func (a *apiServer) PostMetricAlertRule(
+	ctx context.Context, req *apiv1.PostMetricAlertRuleRequest,
+) (*apiv1.PostMetricAlertRuleResponse, error) {
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+	r, err := webhooks.MetricAlertRuleFromProto(req.Rule)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := r.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := a.canEditMetricAlertRule(ctx, r); err != nil {
+		return nil, err
+	}
+
+	if err := webhooks.AddMetricAlertRule(ctx, r); err != nil {
+		return nil, errors.Wrap(err, "error creating metric alert rule")
+	}
+	return &apiv1.PostMetricAlertRuleResponse{Rule: r.Proto()}, nil
+}
+
+func (a *apiServer) DeleteMetricAlertRule(
+	ctx context.Context, req *apiv1.DeleteMetricAlertRuleRequest,
+) (*apiv1.DeleteMetricAlertRuleResponse, error) {
+	r, err := webhooks.GetMetricAlertRule(ctx, webhooks.MetricAlertRuleID(req.Id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.NotFoundErrs("metric alert rule", fmt.Sprint(req.Id), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := a.canEditMetricAlertRule(ctx, r); err != nil {
+		return nil, err
+	}
+
+	if err := webhooks.DeleteMetricAlertRule(ctx, r.ID); err != nil {
+		return nil, errors.Wrapf(err, "error deleting metric alert rule %d", r.ID)
+	}
+	return &apiv1.DeleteMetricAlertRuleResponse{}, nil
+}
+
+func (a *apiServer) GetTrialMetricAlerts(
+	ctx context.Context, req *apiv1.GetTrialMetricAlertsRequest,
+) (*apiv1.GetTrialMetricAlertsResponse, error) {
+	if err := trials.CanGetTrialsExperimentAndCheckCanDoAction(ctx, int(req.TrialId),
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		return nil, err
+	}
+
+	events, err := webhooks.GetTrialMetricAlertEvents(ctx, int(req.TrialId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetTrialMetricAlertsResponse{Events: events.Proto()}, nil
+}
+
+// evaluateMetricAlerts checks the metric alert rules that apply to a trial after it reported
+// metrics, and pauses or kills the trial for the rules that fired with those actions. Failures
+// are logged rather than returned so that they don't fail the metrics report.
+func (a *apiServer) evaluateMetricAlerts(
+	ctx context.Context, trialID int, group model.MetricGroup,
+) {
+	fired, err := webhooks.EvaluateMetricAlerts(ctx, trialID, group)
+	if err != nil {
+		log.WithError(err).Errorf("evaluating metric alerts for trial %d", trialID)
+	}
+	if len(fired) == 0 {
+		return
+	}
+
+	eID, rID, err := a.m.db.TrialExperimentAndRequestID(trialID)
+	if err != nil {
+		log.WithError(err).Errorf("finding experiment for trial %d", trialID)
+		return
+	}
+	for _, f := range fired {
+		var state model.State
+		switch f.Rule.Action {
+		case webhooks.MetricAlertActionPause:
+			state = model.PausedState
+		case webhooks.MetricAlertActionKill:
+			state = model.StoppingKilledState
+		default:
+			continue
+		}
+
+		if err := a.ask(actor.Addr("experiments", eID), patchTrialState{
+			requestID: rID,
+			state: model.StateWithReason{
+				State:               state,
+				InformationalReason: "metric alert: " + f.Event.Message,
+			},
+		}, nil); err != nil {
+			log.WithError(err).Errorf(
+				"applying metric alert rule %d to trial %d", f.Rule.ID, trialID)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	modelauth "github.com/determined-ai/determined/master/internal/model"
 	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
 	"github.com/determined-ai/determined/proto/pkg/modelv1"
@@ -596,8 +597,14 @@ func (a *apiServer) GetModelVersions(
 				curUser.Username, parentModel.Name))
 	}
 
+	stages := make([]string, 0, len(req.Stages))
+	for _, s := range req.Stages {
+		stages = append(stages, s.String())
+	}
+
 	resp := &apiv1.GetModelVersionsResponse{Model: parentModel}
-	err = a.m.db.QueryProto("get_model_versions", &resp.ModelVersions, parentModel.Id)
+	err = a.m.db.QueryProto("get_model_versions", &resp.ModelVersions, parentModel.Id,
+		strings.Join(stages, ","))
 	if err != nil {
 		return nil, err
 	}
@@ -651,31 +658,62 @@ func (a *apiServer) PostModelVersion(
 		return nil, err
 	}
 
-	respModelVersion := &apiv1.PostModelVersionResponse{}
-	respModelVersion.ModelVersion = &modelv1.ModelVersion{}
-
 	mdata, err := protojson.Marshal(req.Metadata)
 	if err != nil {
 		return nil, errors.Wrap(err, "error marshaling ModelVersion.Metadata")
 	}
 
-	reqLabels := strings.Join(req.Labels, ",")
+	provenance := make([]*model.ModelVersionProvenance, 0, len(req.Provenance))
+	for _, p := range req.Provenance {
+		if p.DatasetUri == "" {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"provenance records must include a dataset URI")
+		}
+		metadata := map[string]interface{}{}
+		if p.Metadata != nil {
+			metadata = p.Metadata.AsMap()
+		}
+		provenance = append(provenance, &model.ModelVersionProvenance{
+			DatasetURI:  p.DatasetUri,
+			DatasetHash: p.DatasetHash,
+			Metadata:    metadata,
+		})
+	}
+
+	// The version and its provenance are recorded together, so a version is never registered
+	// without the datasets it was trained on.
+	var version int32
+	err = db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		id, v, err := db.AddModelVersionTx(ctx, tx, modelResp.Id, c.Uuid, req.Name, req.Comment,
+			mdata, req.Labels, req.Notes, model.UserID(user.User.Id))
+		if err != nil {
+			return errors.Wrapf(err, "error adding model version to model %q", req.ModelName)
+		}
+		version = v
 
-	err = a.m.db.QueryProto(
-		"insert_model_version",
-		respModelVersion.ModelVersion,
-		modelResp.Id,
-		c.Uuid,
-		req.Name,
-		req.Comment,
-		mdata,
-		reqLabels,
-		req.Notes,
-		user.User.Id,
-	)
+		if len(provenance) == 0 {
+			return nil
+		}
+		for _, p := range provenance {
+			p.ModelVersionID = id
+		}
+		if _, err := tx.NewInsert().Model(&provenance).
+			ExcludeColumn("id").
+			Exec(ctx); err != nil {
+			return errors.Wrapf(err, "error recording provenance for version %d of model %q",
+				version, req.ModelName)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return respModelVersion, errors.Wrapf(err, "error adding model version to model %q",
-		req.ModelName)
+	mv, err := a.ModelVersionFromID(req.ModelName, version)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PostModelVersionResponse{ModelVersion: mv}, nil
 }
 
 func (a *apiServer) PatchModelVersion(
@@ -815,6 +853,180 @@ func (a *apiServer) DeleteModelVersion(
 		errors.Wrapf(err, "error deleting model version %v", modelVersionName)
 }
 
+func (a *apiServer) TransitionModelVersion(
+	ctx context.Context, req *apiv1.TransitionModelVersionRequest,
+) (*apiv1.TransitionModelVersionResponse, error) {
+	if req.Stage == modelv1.ModelVersionStage_MODEL_VERSION_STAGE_UNSPECIFIED {
+		return nil, status.Errorf(codes.InvalidArgument, "a target stage must be specified")
+	}
+
+	currModelVersion, err := a.ModelVersionFromID(req.ModelName, req.ModelVersionNum)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currModel, err := a.ModelFromIdentifier(req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	if err := modelauth.AuthZProvider.Get().CanEditModel(ctx, *curUser, currModel,
+		currModel.WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	if currModel.Archived {
+		return nil, errors.Errorf("model %q is archived and cannot transition versions",
+			currModel.Name)
+	}
+
+	if currModelVersion.Stage == req.Stage {
+		return &apiv1.TransitionModelVersionResponse{ModelVersion: currModelVersion}, nil
+	}
+
+	modelVersionName := fmt.Sprintf("%v:%v", req.ModelName, req.ModelVersionNum)
+	err = db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Only one version of a model may be in production at a time, so promoting a version
+		// archives whichever version currently holds that stage.
+		if req.Stage == modelv1.ModelVersionStage_MODEL_VERSION_STAGE_PRODUCTION {
+			var superseded []int
+			if err := tx.NewUpdate().Table("model_versions").
+				Set("stage = ?", modelv1.ModelVersionStage_MODEL_VERSION_STAGE_ARCHIVED.String()).
+				Set("last_updated_time = current_timestamp").
+				Where("model_id = ?", currModel.Id).
+				Where("stage = ?", req.Stage.String()).
+				Where("id != ?", currModelVersion.Id).
+				Returning("id").
+				Scan(ctx, &superseded); err != nil {
+				return errors.Wrap(err, "error archiving current production model version")
+			}
+			for _, id := range superseded {
+				if err := insertModelVersionTransition(ctx, tx, &model.ModelVersionStageTransition{
+					ModelVersionID: id,
+					FromStage:      req.Stage.String(),
+					ToStage:        modelv1.ModelVersionStage_MODEL_VERSION_STAGE_ARCHIVED.String(),
+					UserID:         curUser.ID,
+					Reason:         fmt.Sprintf("superseded by version %d", currModelVersion.Version),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		var fromStage string
+		if err := tx.NewSelect().Table("model_versions").Column("stage").
+			Where("id = ?", currModelVersion.Id).For("UPDATE").
+			Scan(ctx, &fromStage); err != nil {
+			return errors.Wrapf(err, "error locking model version %v", modelVersionName)
+		}
+
+		if _, err := tx.NewUpdate().Table("model_versions").
+			Set("stage = ?", req.Stage.String()).
+			Set("last_updated_time = current_timestamp").
+			Where("id = ?", currModelVersion.Id).
+			Exec(ctx); err != nil {
+			return errors.Wrapf(err, "error updating stage of model version %v", modelVersionName)
+		}
+
+		return insertModelVersionTransition(ctx, tx, &model.ModelVersionStageTransition{
+			ModelVersionID: int(currModelVersion.Id),
+			FromStage:      fromStage,
+			ToStage:        req.Stage.String(),
+			UserID:         curUser.ID,
+			Reason:         req.Reason,
+		})
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), db.CodeUniqueViolation) {
+			return nil, status.Errorf(codes.Aborted,
+				"another version of model %q was concurrently moved to production", req.ModelName)
+		}
+		return nil, err
+	}
+
+	mv, err := a.ModelVersionFromID(req.ModelName, req.ModelVersionNum)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.TransitionModelVersionResponse{ModelVersion: mv}, nil
+}
+
+func insertModelVersionTransition(
+	ctx context.Context, tx bun.Tx, t *model.ModelVersionStageTransition,
+) error {
+	if _, err := tx.NewInsert().Model(t).
+		ExcludeColumn("id", "transition_time").
+		Exec(ctx); err != nil {
+		return errors.Wrapf(err, "error recording stage transition for model version %d",
+			t.ModelVersionID)
+	}
+	return nil
+}
+
+func (a *apiServer) GetModelVersionTransitions(
+	ctx context.Context, req *apiv1.GetModelVersionTransitionsRequest,
+) (*apiv1.GetModelVersionTransitionsResponse, error) {
+	mv, err := a.ModelVersionFromID(req.ModelName, req.ModelVersionNum)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currModel, err := a.ModelFromIdentifier(req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	if err = modelauth.AuthZProvider.Get().CanGetModel(ctx, *curUser, currModel,
+		currModel.WorkspaceId); err != nil {
+		return nil, authz.SubIfUnauthorized(err,
+			errors.Errorf("current user %q doesn't have permissions to get model %q",
+				curUser.Username, currModel.Name))
+	}
+
+	resp := &apiv1.GetModelVersionTransitionsResponse{}
+	err = a.m.db.QueryProto("get_model_version_transitions", &resp.Transitions, mv.Id)
+	return resp, errors.Wrapf(err, "error fetching stage transitions for model version %v:%v",
+		req.ModelName, req.ModelVersionNum)
+}
+
+func (a *apiServer) GetModelVersionLineage(
+	ctx context.Context, req *apiv1.GetModelVersionLineageRequest,
+) (*apiv1.GetModelVersionLineageResponse, error) {
+	mv, err := a.ModelVersionFromID(req.ModelName, req.ModelVersionNum)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currModel, err := a.ModelFromIdentifier(req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	if err = modelauth.AuthZProvider.Get().CanGetModel(ctx, *curUser, currModel,
+		currModel.WorkspaceId); err != nil {
+		return nil, authz.SubIfUnauthorized(err,
+			errors.Errorf("current user %q doesn't have permissions to get model %q",
+				curUser.Username, currModel.Name))
+	}
+
+	resp := &apiv1.GetModelVersionLineageResponse{
+		ModelVersion: mv,
+		Lineage:      &modelv1.ModelVersionLineage{},
+	}
+	err = a.m.db.QueryProto("get_model_version_lineage", resp.Lineage, mv.Id)
+	return resp, errors.Wrapf(err, "error fetching lineage for model version %v:%v",
+		req.ModelName, req.ModelVersionNum)
+}
+
 // Query for all trials that use a given model_version and return their metrics.
 func (a *apiServer) GetTrialMetricsByModelVersion(
 	ctx context.Context, req *apiv1.GetTrialMetricsByModelVersionRequest,
@@ -0,0 +1,164 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+func createTestModelVersion(
+	ctx context.Context, t *testing.T, api *apiServer, curUser model.User, mdl *modelv1.Model,
+) *modelv1.ModelVersion {
+	ckpt := createVersionTwoCheckpoint(ctx, t, api, curUser, map[string]int64{"a": 1})
+	_, version, err := db.AddModelVersionTx(
+		ctx, db.Bun(), mdl.Id, ckpt, "", "", []byte(`{}`), nil, "", curUser.ID)
+	require.NoError(t, err)
+	var mv modelv1.ModelVersion
+	require.NoError(t, api.m.db.QueryProto("get_model_version", &mv, mdl.Id, version))
+	return &mv
+}
+
+func TestTransitionModelVersion(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+
+	mdlResp, err := api.PostModel(ctx, &apiv1.PostModelRequest{Name: uuid.NewString()})
+	require.NoError(t, err)
+	mdl := mdlResp.Model
+
+	first := createTestModelVersion(ctx, t, api, curUser, mdl)
+	second := createTestModelVersion(ctx, t, api, curUser, mdl)
+	require.Equal(t, modelv1.ModelVersionStage_MODEL_VERSION_STAGE_NONE, first.Stage)
+
+	_, err = api.TransitionModelVersion(ctx, &apiv1.TransitionModelVersionRequest{
+		ModelName:       mdl.Name,
+		ModelVersionNum: first.Version,
+	})
+	require.Error(t, err)
+
+	resp, err := api.TransitionModelVersion(ctx, &apiv1.TransitionModelVersionRequest{
+		ModelName:       mdl.Name,
+		ModelVersionNum: first.Version,
+		Stage:           modelv1.ModelVersionStage_MODEL_VERSION_STAGE_PRODUCTION,
+		Reason:          "initial release",
+	})
+	require.NoError(t, err)
+	require.Equal(t, modelv1.ModelVersionStage_MODEL_VERSION_STAGE_PRODUCTION,
+		resp.ModelVersion.Stage)
+
+	// Promoting another version archives the current production version.
+	resp, err = api.TransitionModelVersion(ctx, &apiv1.TransitionModelVersionRequest{
+		ModelName:       mdl.Name,
+		ModelVersionNum: second.Version,
+		Stage:           modelv1.ModelVersionStage_MODEL_VERSION_STAGE_PRODUCTION,
+	})
+	require.NoError(t, err)
+	require.Equal(t, modelv1.ModelVersionStage_MODEL_VERSION_STAGE_PRODUCTION,
+		resp.ModelVersion.Stage)
+
+	mvResp, err := api.GetModelVersion(ctx, &apiv1.GetModelVersionRequest{
+		ModelName:       mdl.Name,
+		ModelVersionNum: first.Version,
+	})
+	require.NoError(t, err)
+	require.Equal(t, modelv1.ModelVersionStage_MODEL_VERSION_STAGE_ARCHIVED,
+		mvResp.ModelVersion.Stage)
+
+	transitions, err := api.GetModelVersionTransitions(ctx,
+		&apiv1.GetModelVersionTransitionsRequest{
+			ModelName:       mdl.Name,
+			ModelVersionNum: first.Version,
+		})
+	require.NoError(t, err)
+	require.Len(t, transitions.Transitions, 2)
+	require.Equal(t, modelv1.ModelVersionStage_MODEL_VERSION_STAGE_NONE,
+		transitions.Transitions[0].FromStage)
+	require.Equal(t, "initial release", transitions.Transitions[0].Reason)
+	require.Equal(t, curUser.Username, transitions.Transitions[0].Username)
+	require.Equal(t, modelv1.ModelVersionStage_MODEL_VERSION_STAGE_ARCHIVED,
+		transitions.Transitions[1].ToStage)
+
+	versions, err := api.GetModelVersions(ctx, &apiv1.GetModelVersionsRequest{
+		ModelName: mdl.Name,
+		Stages:    []modelv1.ModelVersionStage{modelv1.ModelVersionStage_MODEL_VERSION_STAGE_PRODUCTION},
+	})
+	require.NoError(t, err)
+	require.Len(t, versions.ModelVersions, 1)
+	require.Equal(t, second.Id, versions.ModelVersions[0].Id)
+}
+
+func TestModelVersionLineage(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+
+	parent := createVersionTwoCheckpoint(ctx, t, api, curUser, map[string]int64{"a": 1})
+	child := createVersionTwoCheckpoint(ctx, t, api, curUser, map[string]int64{"a": 1})
+	_, err := db.Bun().NewUpdate().Table("checkpoints_v2").
+		Set("state = ?", model.CompletedState).
+		Where("uuid = ?", child).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	// Make the trial that produced the child checkpoint a warm start of the parent.
+	var parentTrialID, childTrialID int32
+	require.NoError(t, db.Bun().NewSelect().Table("checkpoints_view").Column("trial_id").
+		Where("uuid = ?", parent).Scan(ctx, &parentTrialID))
+	require.NoError(t, db.Bun().NewSelect().Table("checkpoints_view").Column("trial_id").
+		Where("uuid = ?", child).Scan(ctx, &childTrialID))
+	_, err = db.Bun().NewUpdate().Table("trials").
+		Set("warm_start_checkpoint_id = (SELECT id FROM checkpoints_v2 WHERE uuid = ?)", parent).
+		Where("id = ?", childTrialID).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	mdlResp, err := api.PostModel(ctx, &apiv1.PostModelRequest{Name: uuid.NewString()})
+	require.NoError(t, err)
+
+	_, err = api.PostModelVersion(ctx, &apiv1.PostModelVersionRequest{
+		ModelName:      mdlResp.Model.Name,
+		CheckpointUuid: child,
+		Provenance:     []*modelv1.ModelVersionProvenance{{DatasetHash: "abc"}},
+	})
+	require.Error(t, err)
+
+	metadata, err := structpb.NewStruct(map[string]interface{}{"split": "train"})
+	require.NoError(t, err)
+	mvResp, err := api.PostModelVersion(ctx, &apiv1.PostModelVersionRequest{
+		ModelName:      mdlResp.Model.Name,
+		CheckpointUuid: child,
+		Provenance: []*modelv1.ModelVersionProvenance{{
+			DatasetUri:  "s3://bucket/dataset",
+			DatasetHash: "abc",
+			Metadata:    metadata,
+		}},
+	})
+	require.NoError(t, err)
+
+	resp, err := api.GetModelVersionLineage(ctx, &apiv1.GetModelVersionLineageRequest{
+		ModelName:       mdlResp.Model.Name,
+		ModelVersionNum: mvResp.ModelVersion.Version,
+	})
+	require.NoError(t, err)
+	require.Equal(t, childTrialID, resp.Lineage.GetTrialId())
+	require.NotNil(t, resp.Lineage.ExperimentConfig)
+	require.Equal(t, "tensortorch",
+		resp.Lineage.CheckpointMetadata.AsMap()["framework"])
+
+	require.Len(t, resp.Lineage.WarmStartChain, 1)
+	require.Equal(t, parent, resp.Lineage.WarmStartChain[0].CheckpointUuid)
+	require.Equal(t, parentTrialID, resp.Lineage.WarmStartChain[0].GetTrialId())
+
+	require.Len(t, resp.Lineage.Provenance, 1)
+	require.Equal(t, "s3://bucket/dataset", resp.Lineage.Provenance[0].DatasetUri)
+	require.Equal(t, "abc", resp.Lineage.Provenance[0].DatasetHash)
+	require.Equal(t, "train", resp.Lineage.Provenance[0].Metadata.AsMap()["split"])
+}
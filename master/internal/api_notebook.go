@@ -218,6 +218,7 @@ func (a *apiServer) isNTSCPermittedToLaunch(
 func (a *apiServer) LaunchNotebook(
 	ctx context.Context, req *apiv1.LaunchNotebookRequest,
 ) (*apiv1.LaunchNotebookResponse, error) {
+	conf := a.m.config.Load()
 	user, _, err := grpcutil.GetUser(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get the user: %s", err)
@@ -247,9 +248,9 @@ func (a *apiServer) LaunchNotebook(
 	spec.WatchRunnerIdleTimeout = true
 
 	// Postprocess the spec.
-	if spec.Config.IdleTimeout == nil && a.m.config.NotebookTimeout != nil {
+	if spec.Config.IdleTimeout == nil && conf.NotebookTimeout != nil {
 		spec.Config.IdleTimeout = ptrs.Ptr(model.Duration(
-			time.Second * time.Duration(*a.m.config.NotebookTimeout)))
+			time.Second * time.Duration(*conf.NotebookTimeout)))
 	}
 	if spec.Config.Description == "" {
 		petName := petname.Generate(expconf.TaskNameGeneratorWords, expconf.TaskNameGeneratorSep)
@@ -238,7 +238,8 @@ func (a *apiServer) getProjectColumnsByID(
 		}
 
 		columnType := parseMetricsType(stats.MetricType)
-		if len(summaryMetrics) > idx+1 && summaryMetrics[idx+1].Count > 1 {
+		ambiguous := len(summaryMetrics) > idx+1 && summaryMetrics[idx+1].Count > 1
+		if ambiguous {
 			columnType = projectv1.ColumnType_COLUMN_TYPE_UNSPECIFIED
 		}
 
@@ -253,7 +254,17 @@ func (a *apiServer) getProjectColumnsByID(
 			columnLocation = projectv1.LocationType_LOCATION_TYPE_VALIDATIONS
 		}
 		// don't surface aggregates that don't make sense for non-numbers
-		if columnType == projectv1.ColumnType_COLUMN_TYPE_NUMBER {
+		switch {
+		case stats.MetricType == db.MetricTypeHistogram && !ambiguous:
+			// Histograms are only comparable through the quantiles of their last value.
+			for _, q := range model.HistogramQuantiles {
+				columns = append(columns, &projectv1.ProjectColumn{
+					Column:   fmt.Sprintf("%s.%s.%s", columnPrefix, stats.MetricName, q.Key),
+					Location: columnLocation,
+					Type:     projectv1.ColumnType_COLUMN_TYPE_NUMBER,
+				})
+			}
+		case columnType == projectv1.ColumnType_COLUMN_TYPE_NUMBER:
 			aggregates := []string{"last", "max", "mean", "min"}
 			for _, aggregate := range aggregates {
 				columns = append(columns, &projectv1.ProjectColumn{
@@ -262,7 +273,7 @@ func (a *apiServer) getProjectColumnsByID(
 					Type:     columnType,
 				})
 			}
-		} else {
+		default:
 			columns = append(columns, &projectv1.ProjectColumn{
 				Column:   fmt.Sprintf("%s.%s.last", columnPrefix, stats.MetricName),
 				Location: columnLocation,
@@ -836,3 +847,74 @@ func (a *apiServer) GetProjectsByUserActivity(
 
 	return &apiv1.GetProjectsByUserActivityResponse{Projects: viewableProjects}, nil
 }
+
+func (a *apiServer) GetProjectMetricRetentionPolicy(
+	ctx context.Context, req *apiv1.GetProjectMetricRetentionPolicyRequest,
+) (*apiv1.GetProjectMetricRetentionPolicyResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId); err != nil {
+		return nil, err
+	}
+
+	p, err := db.GetProjectMetricRetentionPolicy(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		defaults := a.m.config.Load().MetricRetention
+		return &apiv1.GetProjectMetricRetentionPolicyResponse{
+			Policy: &projectv1.MetricRetentionPolicy{
+				RawRetentionDays: int32(defaults.RawRetentionDays),
+				BucketSize:       int32(defaults.BucketSize),
+			},
+		}, nil
+	}
+	return &apiv1.GetProjectMetricRetentionPolicyResponse{
+		Policy: &projectv1.MetricRetentionPolicy{
+			RawRetentionDays: int32(p.RawRetentionDays),
+			BucketSize:       int32(p.BucketSize),
+		},
+		Overridden: true,
+	}, nil
+}
+
+func (a *apiServer) PutProjectMetricRetentionPolicy(
+	ctx context.Context, req *apiv1.PutProjectMetricRetentionPolicyRequest,
+) (*apiv1.PutProjectMetricRetentionPolicyResponse, error) {
+	switch {
+	case req.Policy == nil:
+		return nil, status.Error(codes.InvalidArgument, "policy is required")
+	case req.Policy.RawRetentionDays < 0:
+		return nil, status.Error(codes.InvalidArgument, "raw_retention_days must be >= 0")
+	case req.Policy.BucketSize < 2:
+		return nil, status.Error(codes.InvalidArgument, "bucket_size must be >= 2")
+	}
+	// Compaction drops the metrics as reported, so changing the policy takes the same permission
+	// as deleting the project.
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanDeleteProject); err != nil {
+		return nil, err
+	}
+
+	if err := db.SetProjectMetricRetentionPolicy(ctx, &db.MetricRetentionPolicy{
+		ProjectID:        int(req.ProjectId),
+		RawRetentionDays: int(req.Policy.RawRetentionDays),
+		BucketSize:       int(req.Policy.BucketSize),
+	}); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutProjectMetricRetentionPolicyResponse{Policy: req.Policy}, nil
+}
+
+func (a *apiServer) DeleteProjectMetricRetentionPolicy(
+	ctx context.Context, req *apiv1.DeleteProjectMetricRetentionPolicyRequest,
+) (*apiv1.DeleteProjectMetricRetentionPolicyResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanDeleteProject); err != nil {
+		return nil, err
+	}
+
+	if err := db.DeleteProjectMetricRetentionPolicy(ctx, int(req.ProjectId)); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteProjectMetricRetentionPolicyResponse{}, nil
+}
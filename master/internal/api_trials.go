@@ -22,6 +22,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	expauth "github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/internal/trials"
@@ -671,6 +672,12 @@ func (a *apiServer) formatMetrics(
 			Values:  valueMap,
 			Epoch:   in.Epoch,
 		}
+		for name, h := range in.Histograms {
+			if out.Histograms == nil {
+				out.Histograms = map[string]*commonv1.Histogram{}
+			}
+			out.Histograms[name] = h.ToProto()
+		}
 		m.Data = append(m.Data, &out)
 	}
 	return nil
@@ -1313,6 +1320,9 @@ func (a *apiServer) ReportTrialMetrics(
 	if err := a.m.db.AddTrialMetrics(ctx, req.Metrics, metricGroup); err != nil {
 		return nil, err
 	}
+	prom.SetTrialMetrics(int(req.Metrics.TrialId), metricGroup,
+		req.Metrics.GetMetrics().GetAvgMetrics().AsMap())
+	a.evaluateMetricAlerts(ctx, int(req.Metrics.TrialId), metricGroup)
 	return &apiv1.ReportTrialMetricsResponse{}, nil
 }
 
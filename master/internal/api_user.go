@@ -238,7 +238,7 @@ func (a *apiServer) GetUserByUsername(
 func (a *apiServer) PostUser(
 	ctx context.Context, req *apiv1.PostUserRequest,
 ) (*apiv1.PostUserResponse, error) {
-	if a.m.config.InternalConfig.ExternalSessions.Enabled() {
+	if a.m.config.Load().InternalConfig.ExternalSessions.Enabled() {
 		return nil, errExternalSessions
 	}
 	if req.User == nil {
@@ -324,7 +324,7 @@ func (a *apiServer) PostUser(
 func (a *apiServer) SetUserPassword(
 	ctx context.Context, req *apiv1.SetUserPasswordRequest,
 ) (*apiv1.SetUserPasswordResponse, error) {
-	if a.m.config.InternalConfig.ExternalSessions.Enabled() {
+	if a.m.config.Load().InternalConfig.ExternalSessions.Enabled() {
 		return nil, errExternalSessions
 	}
 	curUser, _, err := grpcutil.GetUser(ctx)
@@ -361,7 +361,7 @@ func (a *apiServer) SetUserPassword(
 func (a *apiServer) PatchUser(
 	ctx context.Context, req *apiv1.PatchUserRequest,
 ) (*apiv1.PatchUserResponse, error) {
-	if a.m.config.InternalConfig.ExternalSessions.Enabled() {
+	if a.m.config.Load().InternalConfig.ExternalSessions.Enabled() {
 		return nil, errExternalSessions
 	}
 	if req.User == nil {
@@ -522,7 +522,7 @@ func (a *apiServer) GetUserSetting(
 func (a *apiServer) PostUserSetting(
 	ctx context.Context, req *apiv1.PostUserSettingRequest,
 ) (*apiv1.PostUserSettingResponse, error) {
-	if a.m.config.InternalConfig.ExternalSessions.Enabled() {
+	if a.m.config.Load().InternalConfig.ExternalSessions.Enabled() {
 		return nil, errExternalSessions
 	}
 	if req.Settings == nil {
@@ -75,18 +75,18 @@ func setupAPITest(t *testing.T, pgdb *db.PgDB) (*apiServer, model.User, context.
 			db:              pgdb,
 			taskLogBackend:  pgdb,
 			rm:              mockRM,
-			config: &config.Config{
-				InternalConfig: config.InternalConfig{
-					ExternalSessions: model.ExternalSessions{},
-				},
-				TaskContainerDefaults: model.TaskContainerDefaultsConfig{},
-				ResourceConfig: config.ResourceConfig{
-					ResourceManager: &config.ResourceManagerConfig{},
-				},
-			},
-			taskSpec: &tasks.TaskSpec{SSHRsaSize: 1024},
 		},
 	}
+	api.m.config.Store(&config.Config{
+		InternalConfig: config.InternalConfig{
+			ExternalSessions: model.ExternalSessions{},
+		},
+		TaskContainerDefaults: model.TaskContainerDefaultsConfig{},
+		ResourceConfig: config.ResourceConfig{
+			ResourceManager: &config.ResourceManagerConfig{},
+		},
+	})
+	api.m.taskSpec.Store(&tasks.TaskSpec{SSHRsaSize: 1024})
 	config.GetMasterConfig().Security.AuthZ = config.AuthZConfig{Type: "basic"}
 
 	userModel, err := user.ByUsername(context.TODO(), "admin")
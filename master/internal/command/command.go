@@ -253,6 +253,7 @@ func (c *command) Receive(ctx *actor.Context) error {
 			}
 		}
 
+		cpus, memory := c.Config.Resources.ToExpconf().HostResourceRequests()
 		var idleWatcherConfig *sproto.IdleTimeoutConfig
 		if c.Config.IdleTimeout != nil && (c.WatchProxyIdleTimeout || c.WatchRunnerIdleTimeout) {
 			idleWatcherConfig = &sproto.IdleTimeoutConfig{
@@ -276,7 +277,11 @@ func (c *command) Receive(ctx *actor.Context) error {
 			SlotsNeeded:  c.Config.Resources.Slots,
 			ResourcePool: c.Config.Resources.ResourcePool,
 			FittingRequirements: sproto.FittingRequirements{
-				SingleAgent: true,
+				SingleAgent:     true,
+				RequiredLabels:  c.Config.Resources.LabelSelectors.Required,
+				PreferredLabels: c.Config.Resources.LabelSelectors.Preferred,
+				CPUs:            cpus,
+				Memory:          memory,
 			},
 
 			ProxyPorts:  sproto.NewProxyPortConfig(c.GenericCommandSpec.ProxyPorts(), c.taskID),
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 
 	log "github.com/sirupsen/logrus"
 
@@ -30,7 +31,7 @@ var (
 
 var (
 	once         sync.Once
-	masterConfig *Config
+	masterConfig atomic.Pointer[Config]
 )
 
 // KubernetesDefaultPriority is the default K8 resource manager priority.
@@ -117,6 +118,12 @@ func DefaultConfig() *Config {
 		Cache: CacheConfig{
 			CacheDir: "/var/cache/determined",
 		},
+		Observability: ObservabilityConfig{
+			Exporter: PrometheusExporterConfig{
+				MaxTrialSeries: 10000,
+			},
+		},
+		MetricRetention: DefaultMetricRetentionConfig(),
 		FeatureSwitches: []string{},
 		ResourceConfig:  *DefaultResourceConfig(),
 	}
@@ -145,6 +152,8 @@ type Config struct {
 	Observability         ObservabilityConfig               `json:"observability"`
 	Cache                 CacheConfig                       `json:"cache"`
 	Webhooks              WebhooksConfig                    `json:"webhooks"`
+	Cost                  CostConfig                        `json:"cost"`
+	MetricRetention       MetricRetentionConfig             `json:"metric_retention"`
 	FeatureSwitches       []string                          `json:"feature_switches"`
 	ResourceConfig
 
@@ -152,17 +161,18 @@ type Config struct {
 	InternalConfig InternalConfig `json:"__internal"`
 }
 
-// GetMasterConfig returns reference to the master config singleton.
+// GetMasterConfig returns reference to the master config singleton. The config must not be
+// modified once the master is running, since it is replaced rather than modified on reload.
 func GetMasterConfig() *Config {
 	once.Do(func() {
-		masterConfig = DefaultConfig()
+		masterConfig.CompareAndSwap(nil, DefaultConfig())
 	})
-	return masterConfig
+	return masterConfig.Load()
 }
 
 // SetMasterConfig sets the master config singleton.
 func SetMasterConfig(aConfig *Config) {
-	if masterConfig != nil {
+	if masterConfig.Load() != nil {
 		panic("master config is already set")
 	}
 	if aConfig == nil {
@@ -172,6 +182,11 @@ func SetMasterConfig(aConfig *Config) {
 	*config = *aConfig
 }
 
+// ReplaceMasterConfig atomically replaces the master config singleton, e.g. on reload.
+func ReplaceMasterConfig(aConfig *Config) {
+	masterConfig.Store(aConfig)
+}
+
 // Printable returns a printable string.
 func (c Config) Printable() ([]byte, error) {
 	const hiddenValue = "********"
@@ -337,7 +352,29 @@ type InternalConfig struct {
 
 // ObservabilityConfig is the configuration for observability metrics.
 type ObservabilityConfig struct {
-	EnablePrometheus bool `json:"enable_prometheus"`
+	EnablePrometheus bool                     `json:"enable_prometheus"`
+	Exporter         PrometheusExporterConfig `json:"exporter"`
+}
+
+// PrometheusExporterConfig configures the exporter of trial metrics and resource pool stats.
+type PrometheusExporterConfig struct {
+	Enabled bool `json:"enabled"`
+	// MaxTrialSeries caps the number of trial metric series exported at once; series past the
+	// cap are dropped until trials holding series exit.
+	MaxTrialSeries int `json:"max_trial_series"`
+}
+
+// Validate implements the check.Validatable interface.
+func (o ObservabilityConfig) Validate() []error {
+	var errs []error
+	if o.Exporter.Enabled && !o.EnablePrometheus {
+		errs = append(errs, errors.New(
+			"observability.exporter.enabled requires observability.enable_prometheus"))
+	}
+	if o.Exporter.MaxTrialSeries < 0 {
+		errs = append(errs, errors.New("observability.exporter.max_trial_series must be >= 0"))
+	}
+	return errs
 }
 
 func readPriorityFromScheduler(conf *SchedulerConfig) *int {
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+)
+
+// CostConfig prices the slot-hours used by allocations, so that usage can be reported as cost.
+type CostConfig struct {
+	// Currency labels the prices in cost reports; it is not used for any conversion.
+	Currency string `json:"currency"`
+	// SlotHour is the price of one slot for one hour in pools without a more specific price.
+	SlotHour float64 `json:"slot_hour"`
+	// InstanceTypes maps provisioner instance types to the price of one instance for one hour.
+	// Dynamic pools launching one of these types price a slot-hour as the instance price
+	// divided by the slots per instance.
+	InstanceTypes map[string]float64 `json:"instance_types"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c CostConfig) Validate() []error {
+	var errs []error
+	if c.SlotHour < 0 {
+		errs = append(errs, errors.New("cost.slot_hour must be >= 0"))
+	}
+	for name, price := range c.InstanceTypes {
+		if price < 0 {
+			errs = append(errs, errors.Errorf("cost.instance_types.%s must be >= 0", name))
+		}
+	}
+	return errs
+}
+
+// SlotHourCost returns the price of one slot-hour in the named resource pool. A price set on the
+// pool wins over the price of its provisioner's instance type, which wins over the default.
+func (c *Config) SlotHourCost(poolName string) float64 {
+	for _, pool := range c.ResourcePools {
+		if pool.PoolName != poolName {
+			continue
+		}
+		if pool.SlotHourCost != nil {
+			return *pool.SlotHourCost
+		}
+		if pool.Provider == nil || pool.Provider.InstanceType() == nil {
+			break
+		}
+		price, ok := c.Cost.InstanceTypes[pool.Provider.InstanceType().Name()]
+		if slots := pool.Provider.SlotsPerInstance(); ok && slots > 0 {
+			return price / float64(slots)
+		}
+	}
+	return c.Cost.SlotHour
+}
+
+// SlotHourCosts returns the price of one slot-hour in each resource pool.
+func (c *Config) SlotHourCosts() map[string]float64 {
+	costs := make(map[string]float64, len(c.ResourcePools))
+	for _, pool := range c.ResourcePools {
+		costs[pool.PoolName] = c.SlotHourCost(pool.PoolName)
+	}
+	return costs
+}
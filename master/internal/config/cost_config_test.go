@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestSlotHourCost(t *testing.T) {
+	c := DefaultConfig()
+	c.Cost = CostConfig{
+		SlotHour:      1,
+		InstanceTypes: map[string]float64{"p3.8xlarge": 12},
+	}
+	c.ResourcePools = []ResourcePoolConfig{
+		{PoolName: "priced", SlotHourCost: ptrs.Ptr(2.5)},
+		{PoolName: "aws", Provider: &provconfig.Config{
+			AWS: &provconfig.AWSClusterConfig{InstanceType: "p3.8xlarge"},
+		}},
+		{PoolName: "unpriced-aws", Provider: &provconfig.Config{
+			AWS: &provconfig.AWSClusterConfig{InstanceType: "p3.2xlarge"},
+		}},
+		{PoolName: "static"},
+	}
+
+	assert.DeepEqual(t, c.SlotHourCosts(), map[string]float64{
+		"priced":       2.5,
+		"aws":          3, // A p3.8xlarge has 4 GPUs.
+		"unpriced-aws": 1,
+		"static":       1,
+	})
+	assert.Equal(t, c.SlotHourCost("removed"), 1.0)
+
+	c.Cost.InstanceTypes["p3.8xlarge"] = -1
+	assert.Equal(t, len(c.Cost.Validate()), 1)
+}
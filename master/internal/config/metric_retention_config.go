@@ -0,0 +1,46 @@
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// MetricRetentionConfig is the cluster-wide policy for compacting old training metrics. Projects
+// can override RawRetentionDays and BucketSize.
+type MetricRetentionConfig struct {
+	// RawRetentionDays is how long training metrics are kept as reported before being compacted;
+	// 0 disables compaction.
+	RawRetentionDays int `json:"raw_retention_days"`
+	// BucketSize is the number of consecutive training metric reports of a trial rolled into
+	// one compacted report holding their mean, min and max.
+	BucketSize int `json:"bucket_size"`
+	// CompactionInterval is how often the master looks for metrics to compact.
+	CompactionInterval model.Duration `json:"compaction_interval"`
+}
+
+// DefaultMetricRetentionConfig returns the default metric retention policy, which keeps all
+// metrics as reported.
+func DefaultMetricRetentionConfig() MetricRetentionConfig {
+	return MetricRetentionConfig{
+		BucketSize:         10,
+		CompactionInterval: model.Duration(time.Hour),
+	}
+}
+
+// Validate implements the check.Validatable interface.
+func (m MetricRetentionConfig) Validate() []error {
+	var errs []error
+	if m.RawRetentionDays < 0 {
+		errs = append(errs, errors.New("metric_retention.raw_retention_days must be >= 0"))
+	}
+	if m.BucketSize < 2 {
+		errs = append(errs, errors.New("metric_retention.bucket_size must be >= 2"))
+	}
+	if m.CompactionInterval <= 0 {
+		errs = append(errs, errors.New("metric_retention.compaction_interval must be > 0"))
+	}
+	return errs
+}
@@ -154,6 +154,31 @@ func (c *Config) InitMasterAddress() error {
 	return nil
 }
 
+// InstanceType returns the type of the instances the provisioner launches, or nil if the
+// provisioner does not launch cloud instances.
+func (c Config) InstanceType() model.InstanceType {
+	switch {
+	case c.AWS != nil:
+		return c.AWS.InstanceType
+	case c.GCP != nil:
+		return c.GCP.InstanceType
+	default:
+		return nil
+	}
+}
+
+// SlotsPerInstance returns the number of slots per launched instance.
+func (c Config) SlotsPerInstance() int {
+	switch {
+	case c.AWS != nil:
+		return c.AWS.SlotsPerInstance()
+	case c.GCP != nil:
+		return c.GCP.SlotsPerInstance()
+	default:
+		return 0
+	}
+}
+
 // Printable returns a printable object.
 func (c Config) Printable() Config {
 	const hiddenValue = "********"
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/logger"
+)
+
+// ReloadResult reports the outcome of reloading the master configuration.
+type ReloadResult struct {
+	// Applied lists the changed fields that took effect immediately.
+	Applied []string
+	// RestartRequired lists the changed fields that only take effect after a master restart.
+	RestartRequired []string
+}
+
+// Reload returns a copy of c with the safely mutable subset of next applied and reports which of
+// the remaining changed fields require a restart. c itself is left unchanged, since it may be
+// read concurrently. next is expected to be resolved and validated.
+//
+// The live subset is the log config, task container defaults, webhooks, cost rates, the metric
+// retention policy, the description, max_aux_containers_per_agent, task_container_defaults,
+// slot_hour_cost and scheduler settings of existing resource pools, and the agent resource
+// manager's default scheduler settings. Scheduler changes only apply live when the scheduler type
+// stays the same.
+func (c *Config) Reload(next *Config) (*Config, ReloadResult) {
+	updated := *c
+	var result ReloadResult
+	for _, field := range changedFields(c, next) {
+		switch field {
+		case "log":
+			updated.Log = next.Log
+			logger.SetLogrus(updated.Log)
+			result.Applied = append(result.Applied, field)
+		case "task_container_defaults":
+			updated.TaskContainerDefaults = next.TaskContainerDefaults
+			result.Applied = append(result.Applied, field)
+		case "webhooks":
+			updated.Webhooks = next.Webhooks
+			result.Applied = append(result.Applied, field)
+		case "cost":
+			updated.Cost = next.Cost
+			result.Applied = append(result.Applied, field)
+		case "metric_retention":
+			updated.MetricRetention = next.MetricRetention
+			result.Applied = append(result.Applied, field)
+		case "resource_manager":
+			if !reloadableResourceManager(c.ResourceManager, next.ResourceManager) {
+				result.RestartRequired = append(result.RestartRequired, field)
+				continue
+			}
+			updated.ResourceManager = next.ResourceManager
+			result.Applied = append(result.Applied, "resource_manager.scheduler")
+		case "resource_pools":
+			applied, ok := reloadablePools(c, next)
+			if !ok {
+				result.RestartRequired = append(result.RestartRequired, field)
+				continue
+			}
+			updated.ResourcePools = next.ResourcePools
+			result.Applied = append(result.Applied, applied...)
+		default:
+			result.RestartRequired = append(result.RestartRequired, field)
+		}
+	}
+	return &updated, result
+}
+
+// changedFields returns the JSON names of the top-level fields that differ between a and b.
+func changedFields(a, b *Config) []string {
+	var changed []string
+	var walk func(a, b reflect.Value)
+	walk = func(a, b reflect.Value) {
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.Anonymous {
+				walk(a.Field(i), b.Field(i))
+				continue
+			}
+			if reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+				continue
+			}
+			changed = append(changed, strings.Split(field.Tag.Get("json"), ",")[0])
+		}
+	}
+	walk(reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem())
+	return changed
+}
+
+// reloadableResourceManager returns true if the resource manager configs differ only in the
+// agent resource manager's default scheduler settings.
+func reloadableResourceManager(current, next *ResourceManagerConfig) bool {
+	if current.AgentRM == nil || next.AgentRM == nil ||
+		!sameSchedulerType(current.AgentRM.Scheduler, next.AgentRM.Scheduler) {
+		return false
+	}
+	a, b := *current.AgentRM, *next.AgentRM
+	a.Scheduler, b.Scheduler = nil, nil
+	return reflect.DeepEqual(
+		ResourceManagerConfig{AgentRM: &a, KubernetesRM: current.KubernetesRM},
+		ResourceManagerConfig{AgentRM: &b, KubernetesRM: next.KubernetesRM},
+	)
+}
+
+// reloadablePools returns the live-reloadable pool fields that changed, and false if any
+// other part of the pool list changed.
+func reloadablePools(current, next *Config) ([]string, bool) {
+	if len(current.ResourcePools) != len(next.ResourcePools) {
+		return nil, false
+	}
+
+	var applied []string
+	for i := range current.ResourcePools {
+		a, b := current.ResourcePools[i], next.ResourcePools[i]
+		if b.KubernetesNamespace == "" && current.ResourceManager.KubernetesRM != nil {
+			// The kubernetes resource manager fills in the namespace of running pools.
+			b.KubernetesNamespace = current.ResourceManager.KubernetesRM.Namespace
+			next.ResourcePools[i].KubernetesNamespace = b.KubernetesNamespace
+		}
+		if a.PoolName != b.PoolName || !sameSchedulerType(a.Scheduler, b.Scheduler) {
+			return nil, false
+		}
+
+		for _, field := range []struct {
+			name    string
+			changed bool
+		}{
+			{"description", a.Description != b.Description},
+			{"max_aux_containers_per_agent", a.MaxAuxContainersPerAgent != b.MaxAuxContainersPerAgent},
+			{"task_container_defaults", !reflect.DeepEqual(a.TaskContainerDefaults, b.TaskContainerDefaults)},
+			{"scheduler", !reflect.DeepEqual(a.Scheduler, b.Scheduler)},
+			{"slot_hour_cost", !reflect.DeepEqual(a.SlotHourCost, b.SlotHourCost)},
+		} {
+			if field.changed {
+				applied = append(applied, fmt.Sprintf("resource_pools.%s.%s", a.PoolName, field.name))
+			}
+		}
+
+		a.Description, b.Description = "", ""
+		a.MaxAuxContainersPerAgent, b.MaxAuxContainersPerAgent = 0, 0
+		a.TaskContainerDefaults, b.TaskContainerDefaults = nil, nil
+		a.Scheduler, b.Scheduler = nil, nil
+		a.SlotHourCost, b.SlotHourCost = nil, nil
+		if !reflect.DeepEqual(a, b) {
+			return nil, false
+		}
+	}
+	return applied, true
+}
+
+func sameSchedulerType(a, b *SchedulerConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.GetType() == b.GetType()
+}
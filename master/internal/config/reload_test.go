@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func testReloadConfig(t *testing.T) *Config {
+	c := DefaultConfig()
+	c.ResourceManager.AgentRM = &AgentResourceManagerConfig{}
+	c.ResourcePools = []ResourcePoolConfig{defaultRPConfig()}
+	c.ResourcePools[0].PoolName = "default"
+	assert.NilError(t, c.Resolve())
+	return c
+}
+
+func TestReloadAppliesLiveFields(t *testing.T) {
+	current, next := testReloadConfig(t), testReloadConfig(t)
+	next.Webhooks.SigningKey = current.Webhooks.SigningKey
+	next.Log.Level = "debug"
+	next.TaskContainerDefaults.ShmSizeBytes = 1 << 20
+	next.ResourcePools[0].Description = "reloaded"
+	next.ResourcePools[0].MaxAuxContainersPerAgent = 5
+	next.ResourcePools[0].SlotHourCost = ptrs.Ptr(2.0)
+	next.ResourceManager.AgentRM.Scheduler.FittingPolicy = worst
+	next.Cost.SlotHour = 1
+
+	updated, result := current.Reload(next)
+	assert.DeepEqual(t, result.Applied, []string{
+		"log",
+		"task_container_defaults",
+		"cost",
+		"resource_manager.scheduler",
+		"resource_pools.default.description",
+		"resource_pools.default.max_aux_containers_per_agent",
+		"resource_pools.default.slot_hour_cost",
+	})
+	assert.Equal(t, len(result.RestartRequired), 0)
+	assert.Equal(t, updated.Log.Level, "debug")
+	assert.Equal(t, updated.ResourcePools[0].Description, "reloaded")
+	assert.Equal(t, updated.ResourceManager.AgentRM.Scheduler.FittingPolicy, worst)
+	// The current config is left untouched for its concurrent readers.
+	assert.Assert(t, current.Log.Level != "debug")
+	assert.Equal(t, current.ResourcePools[0].Description, "")
+	assert.Assert(t, current.ResourceManager.AgentRM.Scheduler.FittingPolicy != worst)
+}
+
+func TestReloadReportsRestartRequired(t *testing.T) {
+	current, next := testReloadConfig(t), testReloadConfig(t)
+	next.Webhooks.SigningKey = current.Webhooks.SigningKey
+
+	next.Port = 9090
+	next.ResourcePools[0].AgentReattachEnabled = true
+	next.ResourceManager.AgentRM.Scheduler = &SchedulerConfig{
+		Priority:      &PrioritySchedulerConfig{DefaultPriority: ptrs.Ptr(10)},
+		FittingPolicy: best,
+	}
+
+	updated, result := current.Reload(next)
+	assert.Equal(t, len(result.Applied), 0)
+	assert.DeepEqual(t, result.RestartRequired, []string{
+		"port", "resource_manager", "resource_pools",
+	})
+	assert.Equal(t, updated.Port, 8080)
+	assert.Assert(t, !updated.ResourcePools[0].AgentReattachEnabled)
+
+	// Adding a pool also needs a restart.
+	next = testReloadConfig(t)
+	next.Webhooks.SigningKey = current.Webhooks.SigningKey
+	next.ResourcePools = append(next.ResourcePools, ResourcePoolConfig{PoolName: "extra"})
+	_, result = current.Reload(next)
+	assert.DeepEqual(t, result.RestartRequired, []string{"resource_pools"})
+}
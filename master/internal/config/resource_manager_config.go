@@ -117,6 +117,7 @@ type KubernetesResourceManagerConfig struct {
 	MasterServiceName        string                  `json:"master_service_name"`
 	LeaveKubernetesResources bool                    `json:"leave_kubernetes_resources"`
 	DefaultScheduler         string                  `json:"default_scheduler"`
+	GangScheduler            *GangSchedulerConfig    `json:"gang_scheduler"`
 	SlotType                 device.Type             `json:"slot_type"`
 	SlotResourceRequests     PodSlotResourceRequests `json:"slot_resource_requests"`
 	// deprecated, no longer in use.
@@ -180,13 +181,43 @@ func (k KubernetesResourceManagerConfig) Validate() []error {
 		checkCPUResource = check.GreaterThan(
 			k.SlotResourceRequests.CPU, float32(0), "slot_resource_requests.cpu must be > 0")
 	}
+	var checkGangScheduler error
+	if k.GangScheduler != nil {
+		switch k.GangScheduler.Type {
+		case KueueGangScheduler:
+			checkGangScheduler = check.NotEmpty(k.GangScheduler.Queue,
+				"gang_scheduler.queue must be set to a LocalQueue name when using kueue")
+		case VolcanoGangScheduler:
+			break
+		default:
+			checkGangScheduler = errors.Errorf("gang_scheduler.type must be either %s or %s",
+				KueueGangScheduler, VolcanoGangScheduler)
+		}
+	}
 	return []error{
 		check.GreaterThanOrEqualTo(k.MaxSlotsPerPod, 0, "max_slots_per_pod must be >= 0"),
 		checkSlotType,
 		checkCPUResource,
+		checkGangScheduler,
 	}
 }
 
+// GangSchedulerConfig configures an external gang-scheduling backend that admits all the pods
+// of an allocation together.
+type GangSchedulerConfig struct {
+	// Type is the backend, either kueue or volcano.
+	Type string `json:"type"`
+	// Queue is the Kueue LocalQueue or Volcano Queue that allocations are submitted to.
+	Queue string `json:"queue"`
+}
+
+const (
+	// KueueGangScheduler gang schedules allocations with Kueue Workloads.
+	KueueGangScheduler = "kueue"
+	// VolcanoGangScheduler gang schedules allocations with Volcano PodGroups.
+	VolcanoGangScheduler = "volcano"
+)
+
 // PodSlotResourceRequests contains the per-slot container requests.
 type PodSlotResourceRequests struct {
 	CPU float32 `json:"cpu"`
@@ -32,10 +32,18 @@ type ResourcePoolConfig struct {
 	// AgentReconnectWait define the time master will wait for agent
 	// before abandoning it.
 	AgentReconnectWait model.Duration `json:"agent_reconnect_wait"`
+	// PrefetchImages are pulled by the agents of the pool as soon as they connect, so tasks
+	// don't wait on pulling them.
+	PrefetchImages []string `json:"prefetch_images"`
 
 	// If empty, will behave as if the value is resource_manager.namespace,
 	// which in most cases will be the namespace the helm deployment is in.
 	KubernetesNamespace string `json:"kubernetes_namespace"`
+	// If nil, pods are launched into the cluster that resource_manager points at, which in
+	// most cases will be the cluster the master runs in.
+	KubernetesCluster *KubernetesClusterConfig `json:"kubernetes_cluster,omitempty"`
+	// If nil, slot-hours in the pool are priced by the master's cost config.
+	SlotHourCost *float64 `json:"slot_hour_cost,omitempty"`
 
 	// Deprecated: Use MaxAuxContainersPerAgent instead.
 	MaxCPUContainersPerAgent int `json:"max_cpu_containers_per_agent,omitempty"`
@@ -64,6 +72,36 @@ func (r ResourcePoolConfig) Validate() []error {
 		check.True(len(r.PoolName) != 0, "resource pool name cannot be empty"),
 		check.True(r.MaxAuxContainersPerAgent >= 0,
 			"resource pool max cpu containers per agent should be >= 0"),
+		check.True(r.SlotHourCost == nil || *r.SlotHourCost >= 0,
+			"resource pool slot hour cost should be >= 0"),
+	}
+}
+
+// KubernetesClusterConfig selects the Kubernetes cluster a resource pool launches pods into.
+// Resource pools with the same kubeconfig and context share a cluster.
+type KubernetesClusterConfig struct {
+	KubeconfigPath string `json:"kubeconfig_path"`
+	// If empty, the current context of the kubeconfig is used.
+	Context string `json:"context"`
+	// Pods in other clusters usually can't reach the master through its service's cluster IP,
+	// so the address they use to reach the master can be set per cluster.
+	MasterIP   string `json:"master_ip"`
+	MasterPort int32  `json:"master_port"`
+}
+
+// Name identifies the cluster.
+func (k KubernetesClusterConfig) Name() string {
+	if k.Context == "" {
+		return k.KubeconfigPath
+	}
+	return k.KubeconfigPath + ":" + k.Context
+}
+
+// Validate implements the check.Validatable interface.
+func (k KubernetesClusterConfig) Validate() []error {
+	return []error{
+		check.True(k.KubeconfigPath != "", "kubernetes_cluster.kubeconfig_path must be provided"),
+		check.True(k.MasterPort >= 0, "kubernetes_cluster.master_port must be >= 0"),
 	}
 }
 
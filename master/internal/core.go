@@ -13,12 +13,15 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
@@ -52,6 +55,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/internal/task/tasklogger"
 	"github.com/determined-ai/determined/master/internal/task/taskmodel"
@@ -90,8 +94,10 @@ type Master struct {
 	ClusterID string
 	MasterID  string
 
-	config   *config.Config
-	taskSpec *tasks.TaskSpec
+	// config and taskSpec are replaced rather than modified when the master config changes, so
+	// that they can be read without locking.
+	config   atomic.Pointer[config.Config]
+	taskSpec atomic.Pointer[tasks.TaskSpec]
 
 	logs   *logger.LogBuffer
 	system *actor.System
@@ -101,33 +107,120 @@ type Master struct {
 
 	trialLogBackend TrialLogBackend
 	taskLogBackend  TaskLogBackend
+
+	// configLoader re-reads the master config from its sources; it is nil if reloads are not
+	// supported. configLock serializes changes to the master config.
+	configLoader func() (*config.Config, error)
+	configLock   sync.Mutex
 }
 
 // New creates an instance of the Determined master.
 func New(logStore *logger.LogBuffer, config *config.Config) *Master {
 	logger.SetLogrus(config.Log)
-	return &Master{
+	m := &Master{
 		MasterID: uuid.New().String(),
 		logs:     logStore,
-		config:   config,
 	}
+	m.config.Store(config)
+	return m
+}
+
+// SetConfigLoader sets the function used to re-read the master config on reload.
+func (m *Master) SetConfigLoader(loader func() (*config.Config, error)) {
+	m.configLoader = loader
+}
+
+// reloadConfig re-reads the master config and applies the fields that can change without a
+// restart.
+func (m *Master) reloadConfig() (config.ReloadResult, error) {
+	if m.configLoader == nil {
+		return config.ReloadResult{}, errors.New("master config reloads are not supported")
+	}
+
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+
+	next, err := m.configLoader()
+	if err != nil {
+		return config.ReloadResult{}, errors.Wrap(err, "reading master config")
+	}
+
+	updated, result := m.config.Load().Reload(next)
+	m.storeConfig(updated)
+
+	for _, field := range result.Applied {
+		if strings.HasPrefix(field, "resource_") {
+			return result, m.updateResourcePoolConfigs()
+		}
+	}
+	return result, nil
+}
+
+// storeConfig publishes a changed master config, and the task spec derived from it, to their
+// readers. The caller must hold configLock.
+func (m *Master) storeConfig(c *config.Config) {
+	m.config.Store(c)
+	config.ReplaceMasterConfig(c)
+	if current := m.taskSpec.Load(); current != nil {
+		taskSpec := *current
+		taskSpec.TaskContainerDefaults = c.TaskContainerDefaults
+		m.taskSpec.Store(&taskSpec)
+	}
+}
+
+func (m *Master) updateResourcePoolConfigs() error {
+	conf := m.config.Load()
+	update := sproto.UpdateResourcePoolConfigs{
+		ResourcePools: append([]config.ResourcePoolConfig{}, conf.ResourcePools...),
+	}
+	if conf.ResourceManager.AgentRM != nil {
+		update.DefaultScheduler = conf.ResourceManager.AgentRM.Scheduler
+	}
+	return errors.Wrap(m.rm.UpdateResourcePoolConfigs(m.system, update),
+		"updating resource pool configs")
+}
+
+// reloadConfigOnSignal reloads the master config whenever the process receives SIGHUP.
+func (m *Master) reloadConfigOnSignal(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				result, err := m.reloadConfig()
+				if err != nil {
+					log.WithError(err).Error("failed to reload master config on SIGHUP")
+					continue
+				}
+				log.WithFields(log.Fields{
+					"applied":          result.Applied,
+					"restart_required": result.RestartRequired,
+				}).Info("reloaded master config on SIGHUP")
+			}
+		}
+	}()
 }
 
 // Info returns this master's information.
 func (m *Master) Info() aproto.MasterInfo {
+	conf := m.config.Load()
 	telemetryInfo := aproto.TelemetryInfo{}
-	if m.config.Telemetry.SegmentWebUIKey != "" {
-		telemetryInfo.SegmentKey = m.config.Telemetry.SegmentWebUIKey
+	if conf.Telemetry.SegmentWebUIKey != "" {
+		telemetryInfo.SegmentKey = conf.Telemetry.SegmentWebUIKey
 	}
 
-	if m.config.Telemetry.Enabled {
+	if conf.Telemetry.Enabled {
 		// Only advertise a Segment WebUI key if a key has been configured and
 		// telemetry is enabled.
 		telemetryInfo.Enabled = true
 
-		if m.config.Telemetry.OtelEnabled && m.config.Telemetry.OtelExportedOtlpEndpoint != "" {
+		if conf.Telemetry.OtelEnabled && conf.Telemetry.OtelExportedOtlpEndpoint != "" {
 			telemetryInfo.OtelEnabled = true
-			telemetryInfo.OtelExportedOtlpEndpoint = m.config.Telemetry.OtelExportedOtlpEndpoint
+			telemetryInfo.OtelExportedOtlpEndpoint = conf.Telemetry.OtelExportedOtlpEndpoint
 		}
 	}
 
@@ -136,9 +229,9 @@ func (m *Master) Info() aproto.MasterInfo {
 		MasterID:    m.MasterID,
 		Version:     version.Version,
 		Telemetry:   telemetryInfo,
-		ClusterName: m.config.ClusterName,
+		ClusterName: conf.ClusterName,
 	}
-	sso.AddProviderInfoToMasterInfo(m.config, &masterInfo)
+	sso.AddProviderInfoToMasterInfo(conf, &masterInfo)
 	return masterInfo
 }
 
@@ -609,6 +702,7 @@ func (m *Master) findListeningPort(listener net.Listener) (uint16, error) {
 }
 
 func (m *Master) startServers(ctx context.Context, cert *tls.Certificate) error {
+	conf := m.config.Load()
 	// Create the base socket listener by either fetching one passed to us from systemd or creating a
 	// TCP listener manually.
 	var baseListener net.Listener
@@ -623,9 +717,9 @@ func (m *Master) startServers(ctx context.Context, cert *tls.Certificate) error
 			return pErr
 		}
 		log.Infof("found port %d for systemd listener", port)
-		m.config.Port = int(port)
+		conf.Port = int(port)
 	default:
-		baseListener, err = net.Listen("tcp", fmt.Sprintf(":%d", m.config.Port))
+		baseListener, err = net.Listen("tcp", fmt.Sprintf(":%d", conf.Port))
 		if err != nil {
 			return err
 		}
@@ -637,7 +731,7 @@ func (m *Master) startServers(ctx context.Context, cert *tls.Certificate) error
 		var clientCAs *x509.CertPool
 		clientAuthMode := tls.NoClientCert
 
-		if agentRM := m.config.ResourceManager.AgentRM; agentRM != nil && agentRM.RequireAuthentication {
+		if agentRM := conf.ResourceManager.AgentRM; agentRM != nil && agentRM.RequireAuthentication {
 			// Most connections don't require client certificates, but we do want to make sure that any that
 			// are provided are valid, so individual handlers that care can just check for the presence of
 			// certificates.
@@ -665,10 +759,10 @@ func (m *Master) startServers(ctx context.Context, cert *tls.Certificate) error
 	// This must be before grpcutil.RegisterHTTPProxy is called since it may use stuff set up by the
 	// gRPC server (logger initialization, maybe more). Found by --race.
 	gRPCServer := grpcutil.NewGRPCServer(m.db, &apiServer{m: m},
-		m.config.Observability.EnablePrometheus,
-		&m.config.InternalConfig.ExternalSessions)
+		conf.Observability.EnablePrometheus,
+		&conf.InternalConfig.ExternalSessions)
 
-	err = grpcutil.RegisterHTTPProxy(ctx, m.echo, m.config.Port, cert)
+	err = grpcutil.RegisterHTTPProxy(ctx, m.echo, conf.Port, cert)
 	if err != nil {
 		return errors.Wrap(err, "failed to register gRPC gateway")
 	}
@@ -712,7 +806,7 @@ func (m *Master) startServers(ctx context.Context, cert *tls.Certificate) error
 	if systemdListener != nil {
 		log.Infof("accepting incoming connections on a socket inherited from systemd")
 	} else {
-		log.Infof("accepting incoming connections on port %d", m.config.Port)
+		log.Infof("accepting incoming connections on port %d", conf.Port)
 	}
 	select {
 	case err := <-errs:
@@ -803,12 +897,12 @@ func convertDBErrorsToNotFound(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-func updateClusterHeartbeat(ctx context.Context, db *db.PgDB) {
-	t := time.NewTicker(10 * time.Minute)
+func updateClusterHeartbeat(ctx context.Context, pgDB *db.PgDB) {
+	t := time.NewTicker(db.ClusterHeartbeatInterval)
 	defer t.Stop()
 	for {
 		currentTime := time.Now().UTC().Truncate(time.Millisecond)
-		err := db.UpdateClusterHeartBeat(currentTime)
+		err := pgDB.UpdateClusterHeartBeat(currentTime)
 		if err != nil {
 			log.Error(err.Error())
 		}
@@ -853,15 +947,16 @@ func (m *Master) postTaskLogs(c echo.Context) (interface{}, error) {
 
 // Run causes the Determined master to connect the database and begin listening for HTTP requests.
 func (m *Master) Run(ctx context.Context) error {
+	conf := m.config.Load()
 	log.Infof("Determined master %s (built with %s)", version.Version, runtime.Version())
 
 	var err error
 
-	if err = etc.SetRootPath(filepath.Join(m.config.Root, "static/srv")); err != nil {
+	if err = etc.SetRootPath(filepath.Join(conf.Root, "static/srv")); err != nil {
 		return errors.Wrap(err, "could not set static root")
 	}
 
-	m.db, err = db.Setup(&m.config.DB)
+	m.db, err = db.Setup(&conf.DB)
 	if err != nil {
 		return err
 	}
@@ -872,7 +967,20 @@ func (m *Master) Run(ctx context.Context) error {
 		return errors.Wrap(err, "could not fetch cluster id from database")
 	}
 
-	err = m.checkIfRMDefaultsAreUnbound(m.config.ResourceManager)
+	migrationVersion, err := db.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if err = m.db.RecordMasterMigrationVersion(&migrationVersion); err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.db.RecordMasterMigrationVersion(nil); err != nil {
+			log.WithError(err).Error("failed to clear master migration version")
+		}
+	}()
+
+	err = m.checkIfRMDefaultsAreUnbound(conf.ResourceManager)
 	if err != nil {
 		return fmt.Errorf("could not validate cluster default resource pools: %s", err.Error())
 	}
@@ -880,19 +988,19 @@ func (m *Master) Run(ctx context.Context) error {
 	// Must happen before recovery. If tasks can't recover their allocations, they need an end time.
 	cluster.InitTheLastBootClusterHeartbeat()
 
-	cert, err := m.config.Security.TLS.ReadCertificate()
+	cert, err := conf.Security.TLS.ReadCertificate()
 	if err != nil {
 		return errors.Wrap(err, "failed to read TLS certificate")
 	}
-	m.taskSpec = &tasks.TaskSpec{
+	m.taskSpec.Store(&tasks.TaskSpec{
 		ClusterID:             m.ClusterID,
-		HarnessPath:           filepath.Join(m.config.Root, "wheels"),
-		TaskContainerDefaults: m.config.TaskContainerDefaults,
+		HarnessPath:           filepath.Join(conf.Root, "wheels"),
+		TaskContainerDefaults: conf.TaskContainerDefaults,
 		MasterCert:            config.GetCertPEM(cert),
-		SSHRsaSize:            m.config.Security.SSH.RsaKeySize,
-		SegmentEnabled:        m.config.Telemetry.Enabled && m.config.Telemetry.SegmentMasterKey != "",
-		SegmentAPIKey:         m.config.Telemetry.SegmentMasterKey,
-	}
+		SSHRsaSize:            conf.Security.SSH.RsaKeySize,
+		SegmentEnabled:        conf.Telemetry.Enabled && conf.Telemetry.SegmentMasterKey != "",
+		SegmentAPIKey:         conf.Telemetry.SegmentMasterKey,
+	})
 
 	go m.cleanUpExperimentSnapshots()
 
@@ -924,11 +1032,11 @@ func (m *Master) Run(ctx context.Context) error {
 	}()
 
 	switch {
-	case m.config.Logging.DefaultLoggingConfig != nil:
+	case conf.Logging.DefaultLoggingConfig != nil:
 		m.trialLogBackend = m.db
 		m.taskLogBackend = m.db
-	case m.config.Logging.ElasticLoggingConfig != nil:
-		es, eErr := elastic.Setup(*m.config.Logging.ElasticLoggingConfig)
+	case conf.Logging.ElasticLoggingConfig != nil:
+		es, eErr := elastic.Setup(*conf.Logging.ElasticLoggingConfig)
 		if eErr != nil {
 			return eErr
 		}
@@ -939,7 +1047,7 @@ func (m *Master) Run(ctx context.Context) error {
 	}
 	tasklogger.SetDefaultLogger(tasklogger.New(m.taskLogBackend))
 
-	user.InitService(m.db, m.system, &m.config.InternalConfig.ExternalSessions)
+	user.InitService(m.db, m.system, &conf.InternalConfig.ExternalSessions)
 	userService := user.GetService()
 
 	proxy.InitProxy(processProxyAuthentication)
@@ -972,7 +1080,7 @@ func (m *Master) Run(ctx context.Context) error {
 	}))
 	setupEchoRedirects(m)
 
-	if m.config.EnableCors {
+	if conf.EnableCors {
 		m.echo.Use(api.CORSWithTargetedOrigin)
 	}
 
@@ -995,12 +1103,12 @@ func (m *Master) Run(ctx context.Context) error {
 
 	m.echo.Use(convertDBErrorsToNotFound)
 
-	if m.config.InternalConfig.AuditLoggingEnabled {
+	if conf.InternalConfig.AuditLoggingEnabled {
 		m.echo.Use(auditLogMiddleware())
 	}
 
-	if m.config.Telemetry.OtelEnabled {
-		opentelemetry.ConfigureOtel(m.config.Telemetry.OtelExportedOtlpEndpoint, "determined-master")
+	if conf.Telemetry.OtelEnabled {
+		opentelemetry.ConfigureOtel(conf.Telemetry.OtelExportedOtlpEndpoint, "determined-master")
 		m.echo.Use(otelecho.Middleware("determined-master"))
 	}
 
@@ -1024,16 +1132,21 @@ func (m *Master) Run(ctx context.Context) error {
 		m.system,
 		m.db,
 		m.echo,
-		&m.config.ResourceConfig,
-		&m.config.TaskContainerDefaults,
+		&conf.ResourceConfig,
+		&conf.TaskContainerDefaults,
 		&aproto.MasterSetAgentOptions{
 			MasterInfo:     m.Info(),
-			LoggingOptions: m.config.Logging,
+			LoggingOptions: conf.Logging,
 		},
 		cert,
 	)
 	jobservice.SetDefaultService(job.NewManager(m.rm, m.system))
 
+	// The exporter is enabled before experiments are restored so restored trials are exported.
+	if conf.Observability.Exporter.Enabled {
+		prom.EnableExporter(conf.Observability.Exporter, m.resourcePoolStats)
+	}
+
 	tasksGroup := m.echo.Group("/tasks")
 	tasksGroup.GET("", api.Route(m.getTasks))
 
@@ -1071,9 +1184,10 @@ func (m *Master) Run(ctx context.Context) error {
 	// set to the last cluster heartbeat when the cluster was running.
 	go updateClusterHeartbeat(ctx, m.db)
 	go trials.MarkLostTrialsWorker(ctx)
+	go m.compactMetrics(ctx)
 
 	// Docs and WebUI.
-	webuiRoot := filepath.Join(m.config.Root, "webui")
+	webuiRoot := filepath.Join(conf.Root, "webui")
 	reactRoot := filepath.Join(webuiRoot, "react")
 	reactRootAbs, err := filepath.Abs(reactRoot)
 	if err != nil {
@@ -1131,7 +1245,7 @@ func (m *Master) Run(ctx context.Context) error {
 	})
 
 	m.echo.File("/api/v1/api.swagger.json",
-		filepath.Join(m.config.Root, "swagger/determined/api/v1/api.swagger.json"))
+		filepath.Join(conf.Root, "swagger/determined/api/v1/api.swagger.json"))
 
 	m.echo.GET("/info", api.Route(m.getInfo))
 
@@ -1139,6 +1253,10 @@ func (m *Master) Run(ctx context.Context) error {
 	experimentsGroup.GET("/:experiment_id/model_def", m.getExperimentModelDefinition)
 	experimentsGroup.GET("/:experiment_id/file/download", m.getExperimentModelFile)
 	experimentsGroup.GET("/:experiment_id/preview_gc", api.Route(m.getExperimentCheckpointsToGC))
+	experimentsGroup.GET("/:experiment_id/metrics-export", m.getExperimentMetricsExport)
+
+	projectsGroup := m.echo.Group("/projects")
+	projectsGroup.GET("/:project_id/metrics-export", m.getProjectMetricsExport)
 
 	checkpointsGroup := m.echo.Group("/checkpoints")
 	checkpointsGroup.GET("/:checkpoint_uuid", m.getCheckpoint)
@@ -1150,6 +1268,7 @@ func (m *Master) Run(ctx context.Context) error {
 	resourcesGroup.GET("/allocation/raw", m.getRawResourceAllocation)
 	resourcesGroup.GET("/allocation/allocations-csv", m.getResourceAllocations)
 	resourcesGroup.GET("/allocation/aggregated", m.getAggregatedResourceAllocation)
+	resourcesGroup.GET("/cost-csv", m.getCostReport)
 
 	m.echo.POST("/task-logs", api.Route(m.postTaskLogs))
 
@@ -1168,7 +1287,7 @@ func (m *Master) Run(ctx context.Context) error {
 	)
 	m.echo.Any("/debug/pprof/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
 
-	if m.config.Observability.EnablePrometheus {
+	if conf.Observability.EnablePrometheus {
 		p := prometheus.NewPrometheus("echo", nil)
 		// Group and obscure URLs returning 400 or 500 errors outside of /api/v1 and /det
 		// This is to prevent a cardinality explosion that could be caused by mass non-200 requests
@@ -1187,6 +1306,10 @@ func (m *Master) Run(ctx context.Context) error {
 			echo.WrapHandler(promhttp.HandlerFor(prom.DetStateMetrics, promhttp.HandlerOpts{})))
 		m.echo.Any("/prom/det-http-sd-config",
 			api.Route(m.getPrometheusTargets))
+		if conf.Observability.Exporter.Enabled {
+			m.echo.Any("/prom/det-exporter-metrics",
+				echo.WrapHandler(promhttp.HandlerFor(prom.ExporterMetrics, promhttp.HandlerOpts{})))
+		}
 	}
 
 	handler := proxy.DefaultProxy.NewProxyHandler("service")
@@ -1208,15 +1331,17 @@ func (m *Master) Run(ctx context.Context) error {
 		m.db,
 		m.rm,
 		m.ClusterID,
-		m.config.Telemetry,
+		conf.Telemetry,
 	)
 
-	if err := sso.RegisterAPIHandlers(m.config, m.db, m.echo); err != nil {
+	if err := sso.RegisterAPIHandlers(conf, m.db, m.echo); err != nil {
 		return err
 	}
 
 	webhooks.Init()
 	defer webhooks.Deinit()
 
+	m.reloadConfigOnSignal(ctx)
+
 	return m.startServers(ctx, cert)
 }
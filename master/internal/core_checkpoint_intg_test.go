@@ -289,12 +289,14 @@ func RegisterCheckpointAsModelVersion(t *testing.T, pgDB *db.PgDB, ckptID uuid.U
 		Labels:     []string{"some label"},
 		Notes:      "some notes",
 	}
-	var mv modelv1.ModelVersion
-	err = pgDB.QueryProto(
-		"insert_model_version", &mv, pmdl.Id, ckptID, expected.Name, expected.Comment,
-		emptyMetadata, strings.Join(expected.Labels, ","), expected.Notes, user.ID,
+	_, version, err := db.AddModelVersionTx(
+		context.TODO(), db.Bun(), pmdl.Id, ckptID.String(), expected.Name, expected.Comment,
+		emptyMetadata, expected.Labels, expected.Notes, user.ID,
 	)
 	require.NoError(t, err)
+	var mv modelv1.ModelVersion
+	err = pgDB.QueryProto("get_model_version", &mv, pmdl.Id, version)
+	require.NoError(t, err)
 	return &mv
 }
 
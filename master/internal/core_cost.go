@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+var costReportGroupBys = map[masterv1.CostReportGroupBy]db.CostGroupBy{
+	masterv1.CostReportGroupBy_COST_REPORT_GROUP_BY_WORKSPACE:     db.CostGroupByWorkspace,
+	masterv1.CostReportGroupBy_COST_REPORT_GROUP_BY_PROJECT:       db.CostGroupByProject,
+	masterv1.CostReportGroupBy_COST_REPORT_GROUP_BY_USER:          db.CostGroupByUser,
+	masterv1.CostReportGroupBy_COST_REPORT_GROUP_BY_LABEL:         db.CostGroupByLabel,
+	masterv1.CostReportGroupBy_COST_REPORT_GROUP_BY_RESOURCE_POOL: db.CostGroupByResourcePool,
+}
+
+func (m *Master) fetchCostReport(
+	ctx context.Context, req *apiv1.GetCostReportRequest,
+) (*apiv1.GetCostReportResponse, error) {
+	conf := m.config.Load()
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid start date %s", err.Error())
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid end date %s", err.Error())
+	}
+	if start.After(end) {
+		return nil, status.Error(codes.InvalidArgument, "start date cannot be after end date")
+	}
+
+	opts := db.CostReportOptions{
+		Start:               start.UTC(),
+		End:                 end.UTC().AddDate(0, 0, 1),
+		SlotHourCosts:       conf.SlotHourCosts(),
+		DefaultSlotHourCost: conf.Cost.SlotHour,
+	}
+	for _, g := range req.GroupBy {
+		groupBy, ok := costReportGroupBys[g]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid group by %s", g)
+		}
+		opts.GroupBy = append(opts.GroupBy, groupBy)
+	}
+
+	rows, err := db.CostReport(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.GetCostReportResponse{Currency: conf.Cost.Currency}
+	for _, row := range rows {
+		resp.Entries = append(resp.Entries, &masterv1.CostReportEntry{
+			Workspace:    row.Workspace,
+			Project:      row.Project,
+			Username:     row.Username,
+			Label:        row.Label,
+			ResourcePool: row.ResourcePool,
+			SlotHours:    row.SlotHours,
+			Cost:         row.Cost,
+		})
+	}
+	return resp, nil
+}
+
+//	@Summary	Get the cost of resource allocations during the given date range (CSV).
+//	@Tags		Cluster
+//	@ID			get-cost-report-csv
+//	@Produce	text/csv
+//	@Param		start_date	query	string	true	"First day of the report (YYYY-MM-DD format)"
+//	@Param		end_date	query	string	true	"Last day of the report (YYYY-MM-DD format)"
+//
+// nolint:lll
+//
+//	@Param		group_by	query	string	false	"Comma-separated dimensions to group by (workspace, project, user, label, resource_pool)"
+//	@Success	200			{}		string	"workspace,project,username,label,resource_pool,slot_hours,cost,currency"
+//	@Router		/resources/cost-csv [get]
+func (m *Master) getCostReport(c echo.Context) error {
+	args := struct {
+		Start   string `query:"start_date"`
+		End     string `query:"end_date"`
+		GroupBy string `query:"group_by"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+
+	req := &apiv1.GetCostReportRequest{StartDate: args.Start, EndDate: args.End}
+	for _, g := range strings.Split(args.GroupBy, ",") {
+		if g = strings.TrimSpace(g); g == "" {
+			continue
+		}
+		groupBy, ok := masterv1.CostReportGroupBy_value["COST_REPORT_GROUP_BY_"+strings.ToUpper(g)]
+		if !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid group_by %q", g))
+		}
+		req.GroupBy = append(req.GroupBy, masterv1.CostReportGroupBy(groupBy))
+	}
+
+	resp, err := m.fetchCostReport(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Content-Type", "text/csv")
+	csvWriter := csv.NewWriter(c.Response())
+	header := []string{
+		"workspace", "project", "username", "label", "resource_pool", "slot_hours", "cost", "currency",
+	}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	for _, entry := range resp.Entries {
+		fields := []string{
+			entry.Workspace, entry.Project, entry.Username, entry.Label, entry.ResourcePool,
+			fmt.Sprintf("%f", entry.SlotHours), fmt.Sprintf("%f", entry.Cost), resp.Currency,
+		}
+		if err := csvWriter.Write(fields); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -299,6 +299,7 @@ func (m *Master) unmarshalTemplateConfig(ctx context.Context, templateName strin
 func (m *Master) parseCreateExperiment(req *apiv1.CreateExperimentRequest, owner *model.User) (
 	*model.Experiment, expconf.ExperimentConfig, *projectv1.Project, *tasks.TaskSpec, error,
 ) {
+	conf := m.config.Load()
 	ctx := context.TODO()
 	// Read the config as the user provided it.
 	config, err := expconf.ParseAnyExperimentConfigYAML([]byte(req.Config))
@@ -339,12 +340,12 @@ func (m *Master) parseCreateExperiment(req *apiv1.CreateExperimentRequest, owner
 	taskContainerDefaults, err := m.rm.TaskContainerDefaults(
 		m.system,
 		poolName,
-		m.config.TaskContainerDefaults,
+		conf.TaskContainerDefaults,
 	)
 	if err != nil {
 		return nil, config, nil, nil, errors.Wrapf(err, "error getting TaskContainerDefaults")
 	}
-	taskSpec := *m.taskSpec
+	taskSpec := *m.taskSpec.Load()
 	taskSpec.TaskContainerDefaults = taskContainerDefaults
 	taskSpec.TaskContainerDefaults.MergeIntoExpConfig(&config)
 	if defaulted.RawEntrypoint == nil && (req.Unmanaged == nil || !*req.Unmanaged) {
@@ -364,7 +365,7 @@ func (m *Master) parseCreateExperiment(req *apiv1.CreateExperimentRequest, owner
 
 	// Merge in the master's checkpoint storage into the config.
 	config.RawCheckpointStorage = schemas.Merge(
-		config.RawCheckpointStorage, &m.config.CheckpointStorage,
+		config.RawCheckpointStorage, &conf.CheckpointStorage,
 	)
 
 	// Lastly, apply any json-schema-defined defaults.
@@ -431,6 +432,7 @@ func (m *Master) parseCreateExperiment(req *apiv1.CreateExperimentRequest, owner
 
 	taskSpec.Project = config.Project()
 	taskSpec.Workspace = config.Workspace()
+	taskSpec.WorkspaceID = workspaceID
 	for label := range config.Labels() {
 		taskSpec.Labels = append(taskSpec.Labels, label)
 	}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// compactMetrics periodically compacts the training metrics older than the retention of the
+// metric_retention policy, or of the policy overriding it for their project. The policy and
+// interval are read on every pass so that reloading the master config applies them.
+func (m *Master) compactMetrics(ctx context.Context) {
+	for {
+		conf := m.config.Load().MetricRetention
+		m.compactMetricsOnce(ctx, db.MetricRetentionPolicy{
+			RawRetentionDays: conf.RawRetentionDays,
+			BucketSize:       conf.BucketSize,
+		})
+
+		select {
+		case <-time.After(time.Duration(conf.CompactionInterval)):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Master) compactMetricsOnce(ctx context.Context, defaults db.MetricRetentionPolicy) {
+	trials, err := db.TrialsWithMetricsToCompact(ctx, defaults)
+	if err != nil {
+		log.WithError(err).Error("failed to find metrics to compact")
+		return
+	}
+
+	var compacted int
+	for _, t := range trials {
+		if ctx.Err() != nil {
+			return
+		}
+		n, err := db.CompactTrialMetrics(ctx, t)
+		if err != nil {
+			log.WithError(err).Errorf("failed to compact metrics of trial %d", t.TrialID)
+			continue
+		}
+		compacted += n
+	}
+	if compacted > 0 {
+		log.Infof("compacted %d training metric reports of %d trials", compacted, len(trials))
+	}
+}
@@ -0,0 +1,407 @@
+package internal
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/marshal"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+const (
+	// MIMETextCSV is CSV's MIME type.
+	MIMETextCSV = "text/csv"
+	// MIMEApplicationParquet is Parquet's MIME type.
+	MIMEApplicationParquet = "application/vnd.apache.parquet"
+)
+
+// metricsExportFormats are the MIME types of the formats metrics can be exported in.
+var metricsExportFormats = map[string]string{
+	"csv":     MIMETextCSV,
+	"parquet": MIMEApplicationParquet,
+}
+
+// metricsExportHParamPrefix prefixes the columns of flattened hyperparameters in a metrics export.
+const metricsExportHParamPrefix = "hparams."
+
+type metricsExportArgs struct {
+	Group        *string `query:"group"`
+	Format       *string `query:"format"`
+	Metrics      *string `query:"metrics"`
+	StartBatches *int    `query:"start_batches"`
+	EndBatches   *int    `query:"end_batches"`
+}
+
+//	@Summary	Export the metrics of the trials of an experiment in a CSV or Parquet file.
+//	@Tags		Experiments
+//	@ID			get-experiment-metrics-export
+//	@Produce	text/csv,application/vnd.apache.parquet
+//	@Param		experiment_id	path	int		true	"Experiment ID"
+//	@Param		group			query	string	false	"Metric group (defaults to training)"
+//	@Param		format			query	string	false	"csv (default) or parquet"
+//	@Param		metrics			query	string	false	"Comma-separated metric names (defaults to all)"
+//	@Param		start_batches	query	int		false	"First batch to export"
+//	@Param		end_batches		query	int		false	"Last batch to export"
+//	@Success	200				{}		string	"trial_id,experiment_id,hparams.*,total_batches,end_time,metrics"
+//	@Router		/experiments/{experiment_id}/metrics-export [get]
+func (m *Master) getExperimentMetricsExport(c echo.Context) error {
+	args := struct {
+		ExperimentID int `path:"experiment_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	if _, _, err := echoGetExperimentAndCheckCanDoActions(
+		c.Request().Context(), c, m, args.ExperimentID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts,
+	); err != nil {
+		return err
+	}
+
+	return m.exportMetrics(c, fmt.Sprintf("exp%d", args.ExperimentID), []int{args.ExperimentID})
+}
+
+//	@Summary	Export the metrics of the trials of every experiment of a project in a CSV or Parquet file.
+//	@Tags		Projects
+//	@ID			get-project-metrics-export
+//	@Produce	text/csv,application/vnd.apache.parquet
+//	@Param		project_id		path	int		true	"Project ID"
+//	@Param		group			query	string	false	"Metric group (defaults to training)"
+//	@Param		format			query	string	false	"csv (default) or parquet"
+//	@Param		metrics			query	string	false	"Comma-separated metric names (defaults to all)"
+//	@Param		start_batches	query	int		false	"First batch to export"
+//	@Param		end_batches		query	int		false	"Last batch to export"
+//	@Success	200				{}		string	"trial_id,experiment_id,hparams.*,total_batches,end_time,metrics"
+//	@Router		/projects/{project_id}/metrics-export [get]
+func (m *Master) getProjectMetricsExport(c echo.Context) error {
+	args := struct {
+		ProjectID int `path:"project_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	curUser := c.(*detContext.DetContext).MustGetUser()
+
+	notFoundErr := api.NotFoundErrs("project", fmt.Sprint(args.ProjectID), false)
+	p := &projectv1.Project{}
+	if err := m.db.QueryProto("get_project", p, args.ProjectID); errors.Is(err, db.ErrNotFound) {
+		return notFoundErr
+	} else if err != nil {
+		return errors.Wrapf(err, "error fetching project (%d) from database", args.ProjectID)
+	}
+	if err := project.AuthZProvider.Get().CanGetProject(ctx, curUser, p); err != nil {
+		return authz.SubIfUnauthorized(err, notFoundErr)
+	}
+
+	var experimentIDs []int
+	if err := db.Bun().NewSelect().Table("experiments").
+		Column("id").
+		Where("project_id = ?", args.ProjectID).
+		Order("id").
+		Scan(ctx, &experimentIDs); err != nil {
+		return errors.Wrapf(err, "getting experiments of project %d", args.ProjectID)
+	}
+	for _, id := range experimentIDs {
+		if _, _, err := echoGetExperimentAndCheckCanDoActions(ctx, c, m, id,
+			expauth.AuthZProvider.Get().CanGetExperimentArtifacts,
+		); err != nil {
+			return err
+		}
+	}
+
+	return m.exportMetrics(c, fmt.Sprintf("project%d", args.ProjectID), experimentIDs)
+}
+
+// exportMetrics writes the metrics of the trials of the experiments selected by the query
+// parameters to the response, as a file named after name.
+func (m *Master) exportMetrics(c echo.Context, name string, experimentIDs []int) error {
+	var args metricsExportArgs
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+
+	opts := db.MetricsExportOptions{
+		ExperimentIDs: experimentIDs,
+		Group:         model.TrainingMetricGroup,
+	}
+	if args.Group != nil {
+		opts.Group = model.MetricGroup(*args.Group)
+	}
+	if err := opts.Group.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid group: %s", err))
+	}
+	format := "csv"
+	if args.Format != nil {
+		format = strings.ToLower(*args.Format)
+	}
+	mimeType, ok := metricsExportFormats[format]
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("invalid format %q, expected csv or parquet", format))
+	}
+	if args.Metrics != nil {
+		for _, name := range strings.Split(*args.Metrics, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				opts.Metrics = append(opts.Metrics, name)
+			}
+		}
+	}
+	if args.StartBatches != nil {
+		opts.StartBatches = *args.StartBatches
+	}
+	if args.EndBatches != nil {
+		opts.EndBatches = *args.EndBatches
+		if opts.EndBatches < opts.StartBatches {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				"end_batches cannot be less than start_batches")
+		}
+	}
+
+	ctx := c.Request().Context()
+	trials, err := db.MetricsExportTrials(ctx, experimentIDs)
+	if err != nil {
+		return err
+	}
+	metricNames := opts.Metrics
+	if len(metricNames) == 0 {
+		if metricNames, err = db.MetricsExportNames(ctx, experimentIDs, opts.Group); err != nil {
+			return err
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, mimeType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(
+		`attachment; filename="%s_%s_metrics.%s"`, name, opts.Group, format))
+	return writeMetricsExport(ctx, c.Response(), format, trials, metricNames, opts)
+}
+
+// writeMetricsExport writes one row per metric report of the trials to w in the format.
+func writeMetricsExport(
+	ctx context.Context, w io.Writer, format string,
+	trials []db.MetricsExportTrial, metricNames []string, opts db.MetricsExportOptions,
+) error {
+	hparams := make(map[int]map[string]any, len(trials))
+	experimentIDs := make(map[int]int, len(trials))
+	hparamSet := map[string]bool{}
+	for _, t := range trials {
+		flat := map[string]any{}
+		flattenMetricsExportHParams(t.HParams, metricsExportHParamPrefix, flat)
+		for name := range flat {
+			hparamSet[name] = true
+		}
+		hparams[t.ID] = flat
+		experimentIDs[t.ID] = t.ExperimentID
+	}
+	hparamNames := make([]string, 0, len(hparamSet))
+	for name := range hparamSet {
+		hparamNames = append(hparamNames, name)
+	}
+	sort.Strings(hparamNames)
+
+	var writer metricsExportWriter
+	switch format {
+	case "parquet":
+		writer = newParquetMetricsExportWriter(w, hparamNames, metricNames)
+	default:
+		writer = newCSVMetricsExportWriter(w, hparamNames, metricNames)
+	}
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+	if err := db.ExportMetrics(ctx, opts, func(row *db.MetricsExportRow) error {
+		return writer.write(experimentIDs[row.TrialID], hparams[row.TrialID], row)
+	}); err != nil {
+		return err
+	}
+	return writer.close()
+}
+
+// flattenMetricsExportHParams flattens nested hyperparameters into target, joining their names
+// with dots.
+func flattenMetricsExportHParams(hparams map[string]any, prefix string, target map[string]any) {
+	for name, v := range hparams {
+		if nested, ok := v.(map[string]any); ok {
+			flattenMetricsExportHParams(nested, prefix+name+".", target)
+			continue
+		}
+		target[prefix+name] = v
+	}
+}
+
+// metricsExportWriter writes the rows of a metrics export in a file format.
+type metricsExportWriter interface {
+	writeHeader() error
+	write(experimentID int, hparams map[string]any, row *db.MetricsExportRow) error
+	close() error
+}
+
+type csvMetricsExportWriter struct {
+	w           *csv.Writer
+	hparamNames []string
+	metricNames []string
+}
+
+func newCSVMetricsExportWriter(
+	w io.Writer, hparamNames, metricNames []string,
+) *csvMetricsExportWriter {
+	return &csvMetricsExportWriter{
+		w:           csv.NewWriter(w),
+		hparamNames: hparamNames,
+		metricNames: metricNames,
+	}
+}
+
+func (w *csvMetricsExportWriter) writeHeader() error {
+	header := []string{"trial_id", "experiment_id"}
+	header = append(header, w.hparamNames...)
+	header = append(header, "total_batches", "end_time")
+	header = append(header, w.metricNames...)
+	return w.w.Write(header)
+}
+
+func (w *csvMetricsExportWriter) write(
+	experimentID int, hparams map[string]any, row *db.MetricsExportRow,
+) error {
+	fields := []string{strconv.Itoa(row.TrialID), strconv.Itoa(experimentID)}
+	for _, name := range w.hparamNames {
+		fields = append(fields, formatMetricsExportValue(hparams[name]))
+	}
+	fields = append(fields,
+		strconv.Itoa(row.TotalBatches), row.EndTime.UTC().Format(time.RFC3339Nano))
+	for _, name := range w.metricNames {
+		fields = append(fields, formatMetricsExportValue(row.Metrics[name]))
+	}
+	return w.w.Write(fields)
+}
+
+func (w *csvMetricsExportWriter) close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// formatMetricsExportValue formats a hyperparameter or metric value as a string. Values other
+// than numbers, strings and booleans, such as histograms, are formatted as JSON.
+func formatMetricsExportValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(bytes)
+	}
+}
+
+// parquetMetricsExportWriter writes a Parquet file with required trial_id, experiment_id,
+// total_batches and end_time columns, an optional string column per hyperparameter and an
+// optional double column per metric. Non-numeric metric values, such as histograms, are null.
+type parquetMetricsExportWriter struct {
+	w           io.Writer
+	pw          *writer.ParquetWriter
+	hparamNames []string
+	metricNames []string
+}
+
+func newParquetMetricsExportWriter(
+	w io.Writer, hparamNames, metricNames []string,
+) *parquetMetricsExportWriter {
+	return &parquetMetricsExportWriter{w: w, hparamNames: hparamNames, metricNames: metricNames}
+}
+
+func parquetMetricsExportColumn(
+	name string, typ parquet.Type, repetition parquet.FieldRepetitionType,
+	converted *parquet.ConvertedType,
+) *parquet.SchemaElement {
+	return &parquet.SchemaElement{
+		Name:           name,
+		Type:           parquet.TypePtr(typ),
+		RepetitionType: parquet.FieldRepetitionTypePtr(repetition),
+		ConvertedType:  converted,
+	}
+}
+
+func (w *parquetMetricsExportWriter) writeHeader() error {
+	required, optional := parquet.FieldRepetitionType_REQUIRED, parquet.FieldRepetitionType_OPTIONAL
+	schema := []*parquet.SchemaElement{
+		{Name: "metrics", RepetitionType: parquet.FieldRepetitionTypePtr(required)},
+		parquetMetricsExportColumn("trial_id", parquet.Type_INT64, required, nil),
+		parquetMetricsExportColumn("experiment_id", parquet.Type_INT64, required, nil),
+	}
+	for _, name := range w.hparamNames {
+		schema = append(schema, parquetMetricsExportColumn(name, parquet.Type_BYTE_ARRAY, optional,
+			parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8)))
+	}
+	schema = append(schema,
+		parquetMetricsExportColumn("total_batches", parquet.Type_INT64, required, nil),
+		parquetMetricsExportColumn("end_time", parquet.Type_INT64, required,
+			parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MILLIS)),
+	)
+	for _, name := range w.metricNames {
+		schema = append(schema, parquetMetricsExportColumn(name, parquet.Type_DOUBLE, optional, nil))
+	}
+	schema[0].NumChildren = ptrs.Ptr(int32(len(schema) - 1))
+
+	pw, err := writer.NewParquetWriterFromWriter(w.w, schema, 1)
+	if err != nil {
+		return errors.Wrap(err, "creating parquet writer")
+	}
+	pw.MarshalFunc = marshal.MarshalCSV
+	w.pw = pw
+	return nil
+}
+
+func (w *parquetMetricsExportWriter) write(
+	experimentID int, hparams map[string]any, row *db.MetricsExportRow,
+) error {
+	values := []any{int64(row.TrialID), int64(experimentID)}
+	for _, name := range w.hparamNames {
+		if v, ok := hparams[name]; ok && v != nil {
+			values = append(values, formatMetricsExportValue(v))
+		} else {
+			values = append(values, nil)
+		}
+	}
+	values = append(values, int64(row.TotalBatches), row.EndTime.UnixMilli())
+	for _, name := range w.metricNames {
+		if f, ok := row.Metrics[name].(float64); ok {
+			values = append(values, f)
+		} else {
+			values = append(values, nil)
+		}
+	}
+	return w.pw.Write(values)
+}
+
+func (w *parquetMetricsExportWriter) close() error {
+	return w.pw.WriteStop()
+}
@@ -0,0 +1,103 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func newMetricsExportEchoContext(
+	user model.User, path string,
+) (echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	ctx := &detContext.DetContext{Context: echo.New().NewContext(
+		httptest.NewRequest(http.MethodGet, path, nil), rec)}
+	ctx.SetUser(user)
+	return ctx, rec
+}
+
+func TestExperimentMetricsExport(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	trial, _ := createTestTrialWithMetrics(ctx, t, api, curUser, false)
+
+	c, rec := newMetricsExportEchoContext(curUser,
+		"/?metrics=loss,epoch&start_batches=2&end_batches=4")
+	c.SetParamNames("experiment_id")
+	c.SetParamValues(strconv.Itoa(trial.ExperimentID))
+	require.NoError(t, api.m.getExperimentMetricsExport(c))
+	require.Equal(t, MIMETextCSV, rec.Header().Get(echo.HeaderContentType))
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	require.Equal(t, []string{
+		"trial_id", "experiment_id", "total_batches", "end_time", "loss", "epoch",
+	}, records[0])
+	for i, record := range records[1:] {
+		require.Equal(t, strconv.Itoa(trial.ID), record[0])
+		require.Equal(t, strconv.Itoa(trial.ExperimentID), record[1])
+		require.Equal(t, strconv.Itoa(i+2), record[2])
+		require.Equal(t, strconv.Itoa(i+2), record[4])
+		require.Equal(t, strconv.Itoa(i+2), record[5])
+	}
+
+	t.Run("AllMetrics", func(t *testing.T) {
+		c, rec := newMetricsExportEchoContext(curUser, "/?group=validation")
+		c.SetParamNames("experiment_id")
+		c.SetParamValues(strconv.Itoa(trial.ExperimentID))
+		require.NoError(t, api.m.getExperimentMetricsExport(c))
+
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 11)
+		require.Contains(t, records[0], "val_loss2")
+	})
+
+	t.Run("Parquet", func(t *testing.T) {
+		c, rec := newMetricsExportEchoContext(curUser, "/?format=parquet")
+		c.SetParamNames("experiment_id")
+		c.SetParamValues(strconv.Itoa(trial.ExperimentID))
+		require.NoError(t, api.m.getExperimentMetricsExport(c))
+		require.Equal(t, MIMEApplicationParquet, rec.Header().Get(echo.HeaderContentType))
+		require.True(t, strings.HasPrefix(rec.Body.String(), "PAR1"))
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		c, _ := newMetricsExportEchoContext(curUser, "/?format=xlsx")
+		c.SetParamNames("experiment_id")
+		c.SetParamValues(strconv.Itoa(trial.ExperimentID))
+		err := api.m.getExperimentMetricsExport(c)
+		require.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	})
+}
+
+func TestProjectMetricsExport(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+	exp := createTestExpWithProjectID(t, api, curUser, projectID)
+
+	c, rec := newMetricsExportEchoContext(curUser, "/")
+	c.SetParamNames("project_id")
+	c.SetParamValues(fmt.Sprint(projectID))
+	require.NoError(t, api.m.getProjectMetricsExport(c))
+	require.Contains(t, rec.Header().Get(echo.HeaderContentDisposition),
+		fmt.Sprintf("project%d_training_metrics.csv", projectID))
+	require.NotZero(t, exp.ID)
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"trial_id", "experiment_id", "total_batches", "end_time"}}, records)
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+func TestFlattenMetricsExportHParams(t *testing.T) {
+	flat := map[string]any{}
+	flattenMetricsExportHParams(map[string]any{
+		"lr":        0.1,
+		"optimizer": map[string]any{"name": "adam", "betas": []any{0.9, 0.99}},
+	}, metricsExportHParamPrefix, flat)
+	require.Equal(t, map[string]any{
+		"hparams.lr":              0.1,
+		"hparams.optimizer.name":  "adam",
+		"hparams.optimizer.betas": []any{0.9, 0.99},
+	}, flat)
+}
+
+func testMetricsExportRows() []*db.MetricsExportRow {
+	endTime := time.Date(2023, 10, 13, 12, 0, 0, 0, time.UTC)
+	return []*db.MetricsExportRow{
+		{TrialID: 1, TotalBatches: 100, EndTime: endTime, Metrics: map[string]any{
+			"loss": 0.5, "accuracy": 0.75, "note": "warmup",
+		}},
+		{TrialID: 1, TotalBatches: 200, EndTime: endTime.Add(time.Minute), Metrics: map[string]any{
+			"loss": 0.25,
+		}},
+	}
+}
+
+func TestCSVMetricsExportWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newCSVMetricsExportWriter(&buf, []string{"hparams.lr"}, []string{"accuracy", "loss", "note"})
+	require.NoError(t, w.writeHeader())
+	for _, row := range testMetricsExportRows() {
+		require.NoError(t, w.write(7, map[string]any{"hparams.lr": 0.1}, row))
+	}
+	require.NoError(t, w.close())
+
+	require.Equal(t, "trial_id,experiment_id,hparams.lr,total_batches,end_time,accuracy,loss,note\n"+
+		"1,7,0.1,100,2023-10-13T12:00:00Z,0.75,0.5,warmup\n"+
+		"1,7,0.1,200,2023-10-13T12:01:00Z,,0.25,\n", buf.String())
+}
+
+func TestParquetMetricsExportWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newParquetMetricsExportWriter(&buf, []string{"hparams.lr"}, []string{"accuracy", "loss", "note"})
+	require.NoError(t, w.writeHeader())
+	for _, row := range testMetricsExportRows() {
+		require.NoError(t, w.write(7, map[string]any{"hparams.lr": 0.1}, row))
+	}
+	require.NoError(t, w.close())
+
+	file, err := buffer.NewBufferFile(buf.Bytes())
+	require.NoError(t, err)
+	r, err := reader.NewParquetColumnReader(file, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), r.GetNumRows())
+
+	var names []string
+	for _, info := range r.SchemaHandler.Infos[1:] {
+		names = append(names, info.ExName)
+	}
+	require.Equal(t, []string{
+		"trial_id", "experiment_id", "hparams.lr", "total_batches", "end_time",
+		"accuracy", "loss", "note",
+	}, names)
+
+	endTime := time.Date(2023, 10, 13, 12, 0, 0, 0, time.UTC)
+	expected := [][]any{
+		{int64(1), int64(1)},
+		{int64(7), int64(7)},
+		{"0.1", "0.1"},
+		{int64(100), int64(200)},
+		{endTime.UnixMilli(), endTime.Add(time.Minute).UnixMilli()},
+		{0.75, nil},
+		{0.5, 0.25},
+		{nil, nil}, // Non-numeric metrics are null.
+	}
+	for i, values := range expected {
+		actual, _, _, err := r.ReadColumnByIndex(int64(i), 2)
+		require.NoError(t, err)
+		require.Equal(t, values, actual, names[i])
+	}
+}
@@ -3,13 +3,21 @@ package internal
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
 
 	"github.com/determined-ai/determined/master/internal/prom"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
+// tracer creates the spans of experiment and trial lifecycles.
+var tracer = otel.Tracer("determined-master")
+
 func (m *Master) getPrometheusTargets(c echo.Context) (interface{}, error) {
 	resp, err := m.rm.GetAgents(m.system, &apiv1.GetAgentsRequest{})
 	if err != nil {
@@ -36,3 +44,56 @@ func (m *Master) getPrometheusTargets(c echo.Context) (interface{}, error) {
 
 	return agentTargetsConfig, nil
 }
+
+// resourcePoolStats gathers the job queue and allocation states of every resource pool for the
+// Prometheus exporter.
+func (m *Master) resourcePoolStats() (map[string]prom.ResourcePoolStats, error) {
+	pools, err := m.rm.GetResourcePools(m.system, &apiv1.GetResourcePoolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("get resource pools: %w", err)
+	}
+
+	stats := make(map[string]prom.ResourcePoolStats, len(pools.ResourcePools))
+	for _, pool := range pools.ResourcePools {
+		jobs, err := m.rm.GetJobQ(m.system, sproto.GetJobQ{ResourcePool: pool.Name})
+		if err != nil {
+			return nil, fmt.Errorf("get job queue for resource pool %s: %w", pool.Name, err)
+		}
+
+		s := prom.ResourcePoolStats{Allocations: map[model.AllocationState]int{}}
+		for _, j := range jobs {
+			if j.State == sproto.SchedulingStateQueued {
+				s.QueuedJobs++
+			} else {
+				s.ScheduledJobs++
+			}
+		}
+		stats[pool.Name] = s
+	}
+
+	summaries, err := m.rm.GetAllocationSummaries(m.system, sproto.GetAllocationSummaries{})
+	if err != nil {
+		return nil, fmt.Errorf("get allocation summaries: %w", err)
+	}
+	now := time.Now()
+	for id, summary := range summaries {
+		s, ok := stats[summary.ResourcePool]
+		if !ok {
+			continue
+		}
+		state, err := task.DefaultService.State(id)
+		if err != nil {
+			// The allocation exited after the summaries were taken.
+			continue
+		}
+		s.Allocations[state.State]++
+		if state.State == model.AllocationStatePending {
+			pending := now.Sub(summary.RegisteredTime).Seconds()
+			if pending > s.OldestPendingSeconds {
+				s.OldestPendingSeconds = pending
+			}
+		}
+		stats[summary.ResourcePool] = s
+	}
+	return stats, nil
+}
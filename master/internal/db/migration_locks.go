@@ -0,0 +1,155 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LockWarning is a migration statement that likely takes a lock blocking other queries on a
+// whole table while it runs.
+type LockWarning struct {
+	Lock      string
+	Table     string
+	Statement string
+}
+
+const (
+	accessExclusiveLock    = "ACCESS EXCLUSIVE"
+	shareLock              = "SHARE"
+	shareRowExclusiveLock  = "SHARE ROW EXCLUSIVE"
+	rowExclusiveEveryRow   = "ROW EXCLUSIVE on every row"
+	maxLockWarningStmtSize = 100
+)
+
+var (
+	tableName = `(?:ONLY\s+)?([\w."]+)`
+
+	lockHeavyStatements = []struct {
+		re   *regexp.Regexp
+		lock string
+	}{
+		{regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?` + tableName), accessExclusiveLock},
+		{regexp.MustCompile(`(?i)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?` + tableName), accessExclusiveLock},
+		{regexp.MustCompile(`(?i)^TRUNCATE\s+(?:TABLE\s+)?` + tableName), accessExclusiveLock},
+		{regexp.MustCompile(`(?i)^REFRESH\s+MATERIALIZED\s+VIEW\s+` + tableName), accessExclusiveLock},
+		{regexp.MustCompile(`(?i)^CREATE\s+(?:UNIQUE\s+)?INDEX\s.*?\sON\s+` + tableName), shareLock},
+		{regexp.MustCompile(`(?i)^CREATE\s+(?:OR\s+REPLACE\s+)?TRIGGER\s.*?\sON\s+` + tableName),
+			shareRowExclusiveLock},
+		{regexp.MustCompile(`(?i)^UPDATE\s+` + tableName), rowExclusiveEveryRow},
+		{regexp.MustCompile(`(?i)^DELETE\s+FROM\s+` + tableName), rowExclusiveEveryRow},
+	}
+
+	explicitLock    = regexp.MustCompile(`(?i)^LOCK\s+(?:TABLE\s+)?` + tableName + `(?:\s+IN\s+(.+)\s+MODE)?`)
+	concurrently    = regexp.MustCompile(`(?i)\sCONCURRENTLY\s`)
+	whereClause     = regexp.MustCompile(`(?i)\sWHERE\s`)
+	whitespaceRunes = regexp.MustCompile(`\s+`)
+)
+
+// LockHeavyStatements estimates which statements of a migration take table-wide locks that
+// block reads or writes while they run. It only looks at the statement text, so it cannot tell
+// how long a statement holds its lock.
+func LockHeavyStatements(sql string) []LockWarning {
+	var warnings []LockWarning
+	for _, stmt := range splitSQLStatements(sql) {
+		stmt = whitespaceRunes.ReplaceAllString(stmt, " ")
+		if concurrently.MatchString(stmt) {
+			continue
+		}
+
+		summary := stmt
+		if len(summary) > maxLockWarningStmtSize {
+			summary = summary[:maxLockWarningStmtSize] + "..."
+		}
+
+		if m := explicitLock.FindStringSubmatch(stmt); m != nil {
+			lock := strings.ToUpper(m[2])
+			if lock == "" {
+				lock = accessExclusiveLock
+			}
+			warnings = append(warnings, LockWarning{Lock: lock, Table: m[1], Statement: summary})
+			continue
+		}
+
+		for _, heavy := range lockHeavyStatements {
+			m := heavy.re.FindStringSubmatch(stmt)
+			if m == nil {
+				continue
+			}
+			if heavy.lock == rowExclusiveEveryRow && whereClause.MatchString(stmt) {
+				break
+			}
+			warnings = append(warnings, LockWarning{Lock: heavy.lock, Table: m[1], Statement: summary})
+			break
+		}
+	}
+	return warnings
+}
+
+// splitSQLStatements splits a SQL script into its statements, dropping comments. Semicolons in
+// string literals and dollar-quoted bodies do not end a statement.
+func splitSQLStatements(sql string) []string {
+	var stmts []string
+	var cur strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		cur.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end == -1 {
+				i = len(sql)
+			} else {
+				i += end
+				cur.WriteByte('\n')
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end == -1 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+		case sql[i] == '\'':
+			end := strings.IndexByte(sql[i+1:], '\'')
+			if end == -1 {
+				cur.WriteString(sql[i:])
+				i = len(sql)
+				continue
+			}
+			cur.WriteString(sql[i : i+end+2])
+			i += end + 1
+		case sql[i] == '$':
+			tag := dollarQuoteTag(sql[i:])
+			if tag == "" {
+				cur.WriteByte(sql[i])
+				continue
+			}
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end == -1 {
+				cur.WriteString(sql[i:])
+				i = len(sql)
+				continue
+			}
+			cur.WriteString(sql[i : i+len(tag)+end+len(tag)])
+			i += len(tag) + end + len(tag) - 1
+		case sql[i] == ';':
+			flush()
+		default:
+			cur.WriteByte(sql[i])
+		}
+	}
+	flush()
+	return stmts
+}
+
+var dollarQuote = regexp.MustCompile(`^\$\w*\$`)
+
+// dollarQuoteTag returns the opening tag, such as $$ or $body$, that s starts with, if any.
+func dollarQuoteTag(s string) string {
+	return dollarQuote.FindString(s)
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockHeavyStatements(t *testing.T) {
+	sql := `
+-- A comment; with a semicolon.
+ALTER TABLE public.trials ADD COLUMN summary_metrics jsonb;
+CREATE INDEX CONCURRENTLY ix_trials_state ON trials (state);
+CREATE UNIQUE INDEX ix_users_name ON ONLY users (username);
+UPDATE experiments SET notes = 'a; b';
+UPDATE experiments SET notes = '' WHERE id = 1;
+DELETE FROM tasks;
+/* LOCK TABLE checkpoints_v2; */
+LOCK TABLE allocations IN share row exclusive MODE;
+CREATE FUNCTION bump() RETURNS trigger AS $$
+BEGIN
+  UPDATE projects SET n = n + 1;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER bump_trigger AFTER INSERT ON experiments FOR EACH ROW EXECUTE PROCEDURE bump();
+SELECT 1`
+
+	var got [][2]string
+	for _, w := range LockHeavyStatements(sql) {
+		got = append(got, [2]string{w.Lock, w.Table})
+	}
+	require.Equal(t, [][2]string{
+		{accessExclusiveLock, "public.trials"},
+		{shareLock, "users"},
+		{rowExclusiveEveryRow, "experiments"},
+		{rowExclusiveEveryRow, "tasks"},
+		{"SHARE ROW EXCLUSIVE", "allocations"},
+		{shareRowExclusiveLock, "experiments"},
+	}, got)
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	require.Equal(t, []string{
+		"SELECT 'a;b'",
+		"DO $body$ BEGIN PERFORM 1; END $body$",
+		"SELECT 2",
+	}, splitSQLStatements("SELECT 'a;b'; -- trailing;\nDO $body$ BEGIN PERFORM 1; END $body$;;SELECT 2"))
+}
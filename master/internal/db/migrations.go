@@ -1,8 +1,12 @@
 package db
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 
 	"github.com/go-pg/migrations/v8"
@@ -12,6 +16,9 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// Arbitrarily chosen unique consistent ID for the migration lock.
+const migrationLockID = 0x33ad0708c9bed25b
+
 func makeGoPgOpts(dbURL string) (*pg.Options, error) {
 	// go-pg ParseURL doesn't support sslrootcert, so strip it and do manually.
 	// TODO(DET-6084): make an upstream PR for this.
@@ -141,11 +148,7 @@ func (db *PgDB) Migrate(migrationURL string, actions []string) error {
 
 	// In integration tests, multiple processes can be running this code at once, which can lead to
 	// errors because PostgreSQL's CREATE TABLE IF NOT EXISTS is not great with concurrency.
-
-	// Arbitrarily chosen unique consistent ID for the lock.
-	const MigrationLockID = 0x33ad0708c9bed25b
-
-	_, err = tx.Exec("SELECT pg_advisory_xact_lock(?)", MigrationLockID)
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID)
 	if err != nil {
 		return err
 	}
@@ -160,20 +163,10 @@ func (db *PgDB) Migrate(migrationURL string, actions []string) error {
 
 	log.Infof("running DB migrations from %s; this might take a while...", migrationURL)
 
-	re := regexp.MustCompile(`file://(.+)`)
-	match := re.FindStringSubmatch(migrationURL)
-	if len(match) != 2 {
-		return fmt.Errorf("failed to parse migrationsURL: %s", migrationURL)
-	}
-
-	collection := migrations.NewCollection()
-	collection.DisableSQLAutodiscover(true)
-	if err = collection.DiscoverSQLMigrations(match[1]); err != nil {
+	collection, err := discoverMigrations(migrationURL)
+	if err != nil {
 		return err
 	}
-	if len(collection.Migrations()) == 0 {
-		return errors.New("failed to discover any migrations")
-	}
 
 	oldVersion, newVersion, err := collection.Run(pgConn, actions...)
 	if err != nil {
@@ -189,3 +182,216 @@ func (db *PgDB) Migrate(migrationURL string, actions []string) error {
 	log.Info("DB migrations completed")
 	return nil
 }
+
+// migrationsDir returns the directory that a file:// migrations URL points at.
+func migrationsDir(migrationURL string) (string, error) {
+	re := regexp.MustCompile(`file://(.+)`)
+	match := re.FindStringSubmatch(migrationURL)
+	if len(match) != 2 {
+		return "", fmt.Errorf("failed to parse migrationsURL: %s", migrationURL)
+	}
+	return match[1], nil
+}
+
+func discoverMigrations(migrationURL string) (*migrations.Collection, error) {
+	dir, err := migrationsDir(migrationURL)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := migrations.NewCollection()
+	collection.DisableSQLAutodiscover(true)
+	if err = collection.DiscoverSQLMigrations(dir); err != nil {
+		return nil, err
+	}
+	if len(collection.Migrations()) == 0 {
+		return nil, errors.New("failed to discover any migrations")
+	}
+	return collection, nil
+}
+
+// PendingMigration is a migration that has not been applied to the database yet.
+type PendingMigration struct {
+	Version int64
+	Name    string
+	// Locks are the statements of the migration that likely block queries on a whole table.
+	Locks []LockWarning
+}
+
+var upMigrationFile = regexp.MustCompile(`^(\d+)_(.+?)(\.tx)?\.up\.sql$`)
+
+// PendingMigrations returns the current migration version of the database and the migrations
+// from the specified directory URL that Migrate would apply, without applying them.
+func (db *PgDB) PendingMigrations(migrationURL string) (int64, []PendingMigration, error) {
+	dir, err := migrationsDir(migrationURL)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var current int64
+	var exists bool
+	if err := db.sql.QueryRow(
+		`SELECT to_regclass('gopg_migrations') IS NOT NULL`,
+	).Scan(&exists); err != nil {
+		return 0, nil, errors.Wrap(err, "checking for migration metadata")
+	}
+	if exists {
+		if current, err = MigrationVersion(context.TODO()); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "listing migrations")
+	}
+
+	var pending []PendingMigration
+	for _, f := range files {
+		match := upMigrationFile.FindStringSubmatch(f.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "parsing version of %s", f.Name())
+		}
+		if version <= current {
+			continue
+		}
+
+		sql, err := os.ReadFile(filepath.Join(dir, f.Name())) // #nosec G304
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "reading %s", f.Name())
+		}
+		pending = append(pending, PendingMigration{
+			Version: version,
+			Name:    match[2],
+			Locks:   LockHeavyStatements(string(sql)),
+		})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+	return current, pending, nil
+}
+
+// PreviousMigrationVersion returns the version of the last migration from the specified directory
+// URL that was applied before the database's current version.
+func (db *PgDB) PreviousMigrationVersion(migrationURL string) (int64, error) {
+	collection, err := discoverMigrations(migrationURL)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := MigrationVersion(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+
+	previous := int64(-1)
+	for _, m := range collection.Migrations() {
+		if m.Version < current {
+			previous = m.Version
+		}
+	}
+	if previous < 0 {
+		return 0, fmt.Errorf("no migration before version %d to revert to", current)
+	}
+	return previous, nil
+}
+
+// MigrateDown reverts every migration newer than target, in a single transaction, and returns
+// the versions before and after. target must be the version of one of the migrations. Unless force
+// is set, it refuses to revert below the migration version recorded by a running master, since
+// that master expects the newer schema.
+func (db *PgDB) MigrateDown(migrationURL string, target int64, force bool) (int64, int64, error) {
+	collection, err := discoverMigrations(migrationURL)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !hasMigrationVersion(collection.Migrations(), target) {
+		return 0, 0, fmt.Errorf("no migration with version %d", target)
+	}
+
+	if !force {
+		running, err := db.MasterMigrationVersion()
+		if err != nil {
+			return 0, 0, err
+		}
+		if running != nil && target < *running {
+			return 0, 0, fmt.Errorf(
+				"a running master expects migration version %d; stop it first, or retry with "+
+					"--force if it exited without recording that it stopped",
+				*running,
+			)
+		}
+	}
+
+	pgOpts, err := makeGoPgOpts(db.url)
+	if err != nil {
+		return 0, 0, err
+	}
+	pgConn := pg.Connect(pgOpts)
+	defer func() {
+		if errd := pgConn.Close(); errd != nil {
+			log.Errorf("error closing pg connection: %s", errd)
+		}
+	}()
+
+	tx, err := pgConn.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		// Rollback unless it has already been committed.
+		if errd := tx.Close(); errd != nil {
+			log.Errorf("failed to rollback pg transaction while migrating: %s", errd)
+		}
+	}()
+
+	if _, err = tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID); err != nil {
+		return 0, 0, err
+	}
+	if _, err = tx.Exec("LOCK TABLE gopg_migrations IN EXCLUSIVE MODE"); err != nil {
+		return 0, 0, err
+	}
+
+	current, err := collection.Version(tx)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "reading migration version")
+	}
+	if target >= current {
+		return current, current, nil
+	}
+
+	ms := collection.Migrations()
+	for i := len(ms) - 1; i >= 0; i-- {
+		m := ms[i]
+		if m.Version > current || m.Version <= target {
+			continue
+		}
+		if m.Down == nil {
+			return 0, 0, fmt.Errorf("migration %d has no down migration", m.Version)
+		}
+		log.Infof("reverting migration %d", m.Version)
+		if err := m.Down(tx); err != nil {
+			return 0, 0, errors.Wrapf(err, "reverting migration %d", m.Version)
+		}
+	}
+
+	if err := collection.SetVersion(tx, target); err != nil {
+		return 0, 0, errors.Wrap(err, "setting migration version")
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return current, target, nil
+}
+
+func hasMigrationVersion(ms []*migrations.Migration, version int64) bool {
+	for _, m := range ms {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDownRejectsUnknownTarget(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"20231001000000_first.tx.up.sql",
+		"20231001000000_first.tx.down.sql",
+		"20231002000000_second.tx.up.sql",
+		"20231002000000_second.tx.down.sql",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o600))
+	}
+
+	// The target is validated before connecting, so the database is never used.
+	_, _, err := (&PgDB{}).MigrateDown("file://"+dir, 20231001500000, false)
+	require.ErrorContains(t, err, "no migration with version 20231001500000")
+}
@@ -0,0 +1,459 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/version"
+)
+
+// ArchiveFormatVersion is the version of the archive layout written by Export. Import refuses
+// archives written with a different format version.
+const ArchiveFormatVersion = 1
+
+// ArchiveManifest is the first record of an archive and describes where it came from.
+type ArchiveManifest struct {
+	FormatVersion    int       `json:"format_version"`
+	MigrationVersion int64     `json:"migration_version"`
+	MasterVersion    string    `json:"master_version"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// archiveRecord is a single table row of an archive.
+type archiveRecord struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// archiveTable describes how the rows of a table are exported and re-imported.
+type archiveTable struct {
+	name string
+	// id is the generated primary key column that is reassigned on import, if any.
+	id string
+	// refs maps columns to the tables whose ids they hold. The referenced tables must come
+	// earlier in archiveTables.
+	refs map[string]string
+	// deferredRefs are refs to tables that come later in archiveTables; they are filled in
+	// once every table is imported.
+	deferredRefs map[string]string
+	// naturalKey identifies rows that may already exist in the target database, such as the
+	// default admin user. Matching rows are reused rather than inserted.
+	naturalKey []string
+	// uniqueName is a column of unique names. Rows whose name is taken in the target database
+	// are imported with a numbered suffix, such as "name (2)", rather than reused, since their
+	// children would conflict with those of the existing row.
+	uniqueName string
+	// partition is the column that partitions the table, if each partition assigns its own
+	// ids. Ids are then remapped per partition, and refs to the table name the partition as
+	// "table/partition".
+	partition string
+}
+
+// archiveTables lists the exported tables in import order.
+var archiveTables = []archiveTable{
+	{name: "users", id: "id", naturalKey: []string{"username"}},
+	{
+		name: "workspaces", id: "id",
+		refs:       map[string]string{"user_id": "users"},
+		naturalKey: []string{"name"},
+	},
+	{
+		name: "projects", id: "id",
+		refs:       map[string]string{"workspace_id": "workspaces", "user_id": "users"},
+		naturalKey: []string{"workspace_id", "name"},
+	},
+	{
+		name:       "templates",
+		refs:       map[string]string{"workspace_id": "workspaces"},
+		naturalKey: []string{"name"},
+	},
+	{name: "jobs", refs: map[string]string{"owner_id": "users"}},
+	{
+		name: "experiments", id: "id",
+		refs: map[string]string{
+			"owner_id": "users", "project_id": "projects", "parent_id": "experiments",
+		},
+		deferredRefs: map[string]string{"best_trial_id": "trials"},
+	},
+	{name: "experiment_snapshots", id: "id", refs: map[string]string{"experiment_id": "experiments"}},
+	{name: "tasks"},
+	{name: "allocations"},
+	{name: "checkpoints_v2", id: "id"},
+	{
+		name: "trials", id: "id",
+		refs: map[string]string{
+			"experiment_id": "experiments", "warm_start_checkpoint_id": "checkpoints_v2",
+		},
+		deferredRefs: map[string]string{
+			"latest_validation_id": "metrics/VALIDATION", "best_validation_id": "metrics/VALIDATION",
+		},
+	},
+	{name: "trial_id_task_id", refs: map[string]string{"trial_id": "trials"}},
+	{
+		name: "metrics", id: "id", partition: "partition_type",
+		refs: map[string]string{"trial_id": "trials"},
+	},
+	{
+		name: "models", id: "id",
+		refs:       map[string]string{"user_id": "users", "workspace_id": "workspaces"},
+		uniqueName: "name",
+	},
+	{
+		name: "model_versions", id: "id",
+		refs: map[string]string{"model_id": "models", "user_id": "users"},
+	},
+	{
+		name: "model_version_stage_transitions", id: "id",
+		refs: map[string]string{"model_version_id": "model_versions", "user_id": "users"},
+	},
+	{
+		name: "model_version_provenance", id: "id",
+		refs: map[string]string{"model_version_id": "model_versions"},
+	},
+	{
+		name: "trial_source_infos",
+		refs: map[string]string{"trial_id": "trials", "model_id": "models"},
+	},
+}
+
+// archiveIDs maps table names, or "table/partition" for partitioned tables, to the ids that rows
+// were exported with and the ids they were imported with.
+type archiveIDs map[string]map[int64]int64
+
+// deferredRef is a reference that is filled in after every table is imported.
+type deferredRef struct {
+	table  string
+	id     int64
+	column string
+	ref    int64
+}
+
+// MigrationVersion returns the version of the latest migration applied to the database.
+func MigrationVersion(ctx context.Context) (int64, error) {
+	var v int64
+	err := Bun().NewSelect().Table("gopg_migrations").Column("version").
+		Order("id DESC").Limit(1).Scan(ctx, &v)
+	return v, errors.Wrap(err, "reading migration version")
+}
+
+// Export writes users, workspaces, projects, templates, experiments and everything recorded
+// about them, and models to w as a versioned archive of JSON lines.
+func Export(ctx context.Context, w io.Writer) error {
+	migrationVersion, err := MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(ArchiveManifest{
+		FormatVersion:    ArchiveFormatVersion,
+		MigrationVersion: migrationVersion,
+		MasterVersion:    version.Version,
+		CreatedAt:        time.Now().UTC(),
+	}); err != nil {
+		return errors.Wrap(err, "writing archive manifest")
+	}
+
+	return Bun().RunInTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead},
+		func(ctx context.Context, tx bun.Tx) error {
+			for _, t := range archiveTables {
+				count, err := exportTable(ctx, tx, t, enc)
+				if err != nil {
+					return errors.Wrapf(err, "exporting %s", t.name)
+				}
+				log.Infof("exported %d rows from %s", count, t.name)
+			}
+			return nil
+		})
+}
+
+func exportTable(ctx context.Context, tx bun.Tx, t archiveTable, enc *json.Encoder) (int, error) {
+	query := fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t", pgx.Identifier{t.name}.Sanitize())
+	if t.id != "" {
+		query += " ORDER BY " + pgx.Identifier{t.id}.Sanitize()
+	}
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return count, err
+		}
+		if err := enc.Encode(archiveRecord{Table: t.name, Row: json.RawMessage(row)}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// Import reads an archive written by Export into the database in a single transaction. Rows
+// are assigned new ids and references between them are rewritten to match. The database must
+// be migrated to the same version that the archive was exported at.
+func Import(ctx context.Context, r io.Reader) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+
+	var manifest ArchiveManifest
+	if err := dec.Decode(&manifest); err != nil {
+		return errors.Wrap(err, "reading archive manifest")
+	}
+	if manifest.FormatVersion != ArchiveFormatVersion {
+		return fmt.Errorf("archive format version %d is not supported, expected %d",
+			manifest.FormatVersion, ArchiveFormatVersion)
+	}
+	migrationVersion, err := MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if manifest.MigrationVersion != migrationVersion {
+		return fmt.Errorf(
+			"archive was exported at migration version %d by master %s, but the database is at "+
+				"version %d; import with a master at the same version as the exporting master",
+			manifest.MigrationVersion, manifest.MasterVersion, migrationVersion)
+	}
+
+	tables := make(map[string]archiveTable, len(archiveTables))
+	for _, t := range archiveTables {
+		tables[t.name] = t
+	}
+
+	return Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		ids := archiveIDs{}
+		var deferred []deferredRef
+		counts := map[string]int{}
+		for {
+			var record archiveRecord
+			if err := dec.Decode(&record); err == io.EOF {
+				break
+			} else if err != nil {
+				return errors.Wrap(err, "reading archive")
+			}
+
+			t, ok := tables[record.Table]
+			if !ok {
+				return fmt.Errorf("archive contains unknown table %s", record.Table)
+			}
+			refs, err := importRow(ctx, tx, t, record.Row, ids)
+			if err != nil {
+				return errors.Wrapf(err, "importing %s", t.name)
+			}
+			deferred = append(deferred, refs...)
+			counts[t.name]++
+		}
+
+		for _, ref := range deferred {
+			newRef, ok := ids[tables[ref.table].deferredRefs[ref.column]][ref.ref]
+			if !ok {
+				continue
+			}
+			t := tables[ref.table]
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?",
+				pgx.Identifier{t.name}.Sanitize(), pgx.Identifier{ref.column}.Sanitize(),
+				pgx.Identifier{t.id}.Sanitize()), newRef, ref.id); err != nil {
+				return errors.Wrapf(err, "setting %s.%s", t.name, ref.column)
+			}
+		}
+
+		for _, t := range archiveTables {
+			log.Infof("imported %d rows into %s", counts[t.name], t.name)
+		}
+		return nil
+	})
+}
+
+// importRow inserts a single archived row, or matches it to an existing row by its natural
+// key, and records the id it was assigned.
+func importRow(
+	ctx context.Context, tx bun.Tx, t archiveTable, raw json.RawMessage, ids archiveIDs,
+) ([]deferredRef, error) {
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	var row map[string]any
+	if err := dec.Decode(&row); err != nil {
+		return nil, err
+	}
+
+	idKey, err := t.idKey(row)
+	if err != nil {
+		return nil, err
+	}
+	oldID, deferred, err := t.remapRow(row, ids)
+	if err != nil {
+		return nil, err
+	}
+	if t.uniqueName != "" {
+		if err := t.renameIfTaken(ctx, tx, row); err != nil {
+			return nil, err
+		}
+	}
+	bs, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+
+	table := pgx.Identifier{t.name}.Sanitize()
+	returning := "NULL"
+	if t.id != "" {
+		returning = pgx.Identifier{t.id}.Sanitize()
+	}
+
+	var newID *int64
+	if len(t.naturalKey) > 0 {
+		conds := make([]string, 0, len(t.naturalKey))
+		for _, col := range t.naturalKey {
+			col = pgx.Identifier{col}.Sanitize()
+			conds = append(conds, fmt.Sprintf("t.%s = r.%s", col, col))
+		}
+		err := tx.QueryRowContext(ctx, fmt.Sprintf(
+			"SELECT t.%s FROM %s t, json_populate_record(NULL::%s, ?) r WHERE %s",
+			returning, table, table, strings.Join(conds, " AND "),
+		), string(bs)).Scan(&newID)
+		switch {
+		case err == nil:
+			if t.id != "" {
+				ids.add(idKey, oldID, *newID)
+			}
+			return nil, nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, err
+		}
+	}
+
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, pgx.Identifier{col}.Sanitize())
+	}
+	colList := strings.Join(cols, ", ")
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (%s) SELECT %s FROM json_populate_record(NULL::%s, ?) RETURNING %s",
+		table, colList, colList, table, returning,
+	), string(bs)).Scan(&newID); err != nil {
+		return nil, err
+	}
+
+	if t.id == "" {
+		return nil, nil
+	}
+	ids.add(idKey, oldID, *newID)
+	for i := range deferred {
+		deferred[i].id = *newID
+	}
+	return deferred, nil
+}
+
+// renameIfTaken gives the row the first free name of "name", "name (2)", "name (3)" and so on.
+func (t archiveTable) renameIfTaken(ctx context.Context, tx bun.Tx, row map[string]any) error {
+	name, ok := row[t.uniqueName].(string)
+	if !ok {
+		return fmt.Errorf("expected %s to be a string, got %v", t.uniqueName, row[t.uniqueName])
+	}
+	candidate := name
+	for n := 2; ; n++ {
+		taken, err := tx.NewSelect().Table(t.name).Where("? = ?", bun.Ident(t.uniqueName), candidate).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			break
+		}
+		candidate = fmt.Sprintf("%s (%d)", name, n)
+	}
+	if candidate != name {
+		log.Warnf("%s %q already exists; importing it as %q", t.name, name, candidate)
+		row[t.uniqueName] = candidate
+	}
+	return nil
+}
+
+// idKey returns the key of the ids that the row's id is remapped with.
+func (t archiveTable) idKey(row map[string]any) (string, error) {
+	if t.partition == "" {
+		return t.name, nil
+	}
+	partition, ok := row[t.partition].(string)
+	if !ok {
+		return "", fmt.Errorf("expected %s to be a string, got %v", t.partition, row[t.partition])
+	}
+	return t.name + "/" + partition, nil
+}
+
+// remapRow rewrites the references in an archived row to the ids assigned on import. It
+// removes the row's own id, which the database reassigns, and its deferred references, which
+// are returned to be filled in later.
+func (t archiveTable) remapRow(row map[string]any, ids archiveIDs) (int64, []deferredRef, error) {
+	var oldID int64
+	if t.id != "" {
+		id, err := archiveID(row[t.id])
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "reading %s", t.id)
+		}
+		oldID = id
+		delete(row, t.id)
+	}
+
+	for col, table := range t.refs {
+		if row[col] == nil {
+			continue
+		}
+		ref, err := archiveID(row[col])
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "reading %s", col)
+		}
+		newRef, ok := ids[table][ref]
+		if !ok {
+			log.Warnf("%s %d references missing %s %d; clearing %s", t.name, oldID, table, ref, col)
+			row[col] = nil
+			continue
+		}
+		row[col] = newRef
+	}
+
+	var deferred []deferredRef
+	for col := range t.deferredRefs {
+		if row[col] == nil {
+			delete(row, col)
+			continue
+		}
+		ref, err := archiveID(row[col])
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "reading %s", col)
+		}
+		deferred = append(deferred, deferredRef{table: t.name, column: col, ref: ref})
+		delete(row, col)
+	}
+	return oldID, deferred, nil
+}
+
+func (ids archiveIDs) add(table string, oldID, newID int64) {
+	if ids[table] == nil {
+		ids[table] = map[int64]int64{}
+	}
+	ids[table][oldID] = newID
+}
+
+func archiveID(v any) (int64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("expected an integer id, got %v", v)
+	}
+	return n.Int64()
+}
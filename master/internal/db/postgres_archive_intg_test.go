@@ -0,0 +1,143 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+var archiveCountedTables = []string{
+	"users", "experiments", "trials", "raw_steps", "raw_validations", "checkpoints_v2", "models",
+	"model_versions",
+}
+
+func archiveTableCounts(t *testing.T, db *PgDB) map[string]int {
+	counts := map[string]int{}
+	for _, table := range archiveCountedTables {
+		var count int
+		require.NoError(t, db.sql.Get(&count, "SELECT count(*) FROM "+table))
+		counts[table] = count
+	}
+	return counts
+}
+
+// requireMockArchiveData adds an experiment with a trial that has a checkpoint, training and
+// validation metrics, and a model named mnist with a version of the checkpoint.
+func requireMockArchiveData(
+	ctx context.Context, t *testing.T, db *PgDB, numSteps int,
+) (model.User, uuid.UUID) {
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+	tr, task := RequireMockTrial(t, db, exp)
+	a := RequireMockAllocation(t, db, task.TaskID)
+
+	ckptUUID := uuid.New()
+	ckpt := MockModelCheckpoint(ckptUUID, a)
+	require.NoError(t, AddCheckpointMetadata(ctx, &ckpt))
+
+	for i := 1; i <= numSteps; i++ {
+		metrics, err := structpb.NewStruct(map[string]any{"loss": float64(i)})
+		require.NoError(t, err)
+		require.NoError(t, db.AddTrainingMetrics(ctx, &trialv1.TrialMetrics{
+			TrialId:        int32(tr.ID),
+			StepsCompleted: int32(i),
+			Metrics:        &commonv1.Metrics{AvgMetrics: metrics},
+		}))
+	}
+	require.NoError(t, AddTrialValidationMetrics(ctx, ckptUUID, tr, int32(numSteps), 1, db))
+
+	var modelID int
+	require.NoError(t, db.sql.Get(&modelID, `
+INSERT INTO models (name, description, metadata, labels, notes, user_id, workspace_id,
+	creation_time, last_updated_time)
+VALUES ('mnist', '', '{}', '{}', '', $1, 1, now(), now())
+RETURNING id`, user.ID))
+	db.MustExec(t, `
+INSERT INTO model_versions (model_id, version, checkpoint_uuid, name, comment, metadata, labels,
+	notes, user_id, creation_time, last_updated_time)
+VALUES ($1, 1, $2, 'v1', '', '{}', '{}', '', $3, now(), now())`, modelID, ckptUUID, user.ID)
+	return user, ckptUUID
+}
+
+func TestArchiveExportImport(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+
+	src, cleanupSrc := MustResolveNewPostgresDatabase(t)
+	defer cleanupSrc()
+	MustMigrateTestPostgres(t, src, MigrationsFromDB)
+	user, ckptUUID := requireMockArchiveData(ctx, t, src, 3)
+	srcCounts := archiveTableCounts(t, src)
+
+	var archive bytes.Buffer
+	require.NoError(t, Export(ctx, &archive))
+
+	// The target already has a model named mnist, and metric ids that differ from the source's.
+	dst, cleanupDst := MustResolveNewPostgresDatabase(t)
+	defer cleanupDst()
+	MustMigrateTestPostgres(t, dst, MigrationsFromDB)
+	requireMockArchiveData(ctx, t, dst, 1)
+	dstCounts := archiveTableCounts(t, dst)
+
+	require.NoError(t, Import(ctx, &archive))
+
+	counts := archiveTableCounts(t, dst)
+	for _, table := range archiveCountedTables {
+		expected := dstCounts[table] + srcCounts[table]
+		if table == "users" {
+			// The default users are matched rather than imported.
+			expected = dstCounts[table] + 1
+		}
+		require.Equal(t, expected, counts[table], table)
+	}
+
+	var trial struct {
+		ID                 int
+		LatestValidationID *int `db:"latest_validation_id"`
+		BestValidationID   *int `db:"best_validation_id"`
+	}
+	require.NoError(t, dst.sql.Get(&trial, `
+SELECT t.id, t.latest_validation_id, t.best_validation_id
+FROM trials t
+JOIN experiments e ON e.id = t.experiment_id
+JOIN users u ON u.id = e.owner_id
+WHERE u.username = $1`, user.Username))
+
+	var steps int
+	require.NoError(t, dst.sql.Get(&steps,
+		"SELECT count(*) FROM raw_steps WHERE trial_id = $1", trial.ID))
+	require.Equal(t, 3, steps)
+
+	require.NotNil(t, trial.LatestValidationID)
+	require.NotNil(t, trial.BestValidationID)
+	for _, vID := range []int{*trial.LatestValidationID, *trial.BestValidationID} {
+		var validationTrialID int
+		require.NoError(t, dst.sql.Get(&validationTrialID,
+			"SELECT trial_id FROM raw_validations WHERE id = $1", vID))
+		require.Equal(t, trial.ID, validationTrialID)
+	}
+
+	var ckptTrialID int
+	require.NoError(t, dst.sql.Get(&ckptTrialID, `
+SELECT tt.trial_id FROM checkpoints_v2 c JOIN trial_id_task_id tt ON tt.task_id = c.task_id
+WHERE c.uuid = $1`, ckptUUID))
+	require.Equal(t, trial.ID, ckptTrialID)
+
+	var modelName string
+	require.NoError(t, dst.sql.Get(&modelName, `
+SELECT m.name FROM model_versions mv JOIN models m ON m.id = mv.model_id
+WHERE mv.checkpoint_uuid = $1`, ckptUUID))
+	require.Equal(t, "mnist (2)", modelName)
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveTableOrder(t *testing.T) {
+	seen := map[string]bool{}
+	for _, table := range archiveTables {
+		seen[table.name] = true
+		for col, ref := range table.refs {
+			require.True(t, seen[ref], "%s.%s references %s before it is imported", table.name, col, ref)
+		}
+	}
+	for _, table := range archiveTables {
+		for col, ref := range table.deferredRefs {
+			name, _, _ := strings.Cut(ref, "/")
+			require.True(t, seen[name], "%s.%s references unknown table %s", table.name, col, ref)
+		}
+	}
+}
+
+func TestArchiveRemapRow(t *testing.T) {
+	trials := archiveTable{
+		name: "trials",
+		id:   "id",
+		refs: map[string]string{
+			"experiment_id": "experiments", "warm_start_checkpoint_id": "checkpoints_v2",
+		},
+		deferredRefs: map[string]string{
+			"latest_validation_id": "metrics/VALIDATION", "best_validation_id": "metrics/VALIDATION",
+		},
+	}
+	ids := archiveIDs{}
+	ids.add("experiments", 7, 1)
+
+	row := map[string]any{
+		"id":                       json.Number("42"),
+		"experiment_id":            json.Number("7"),
+		"warm_start_checkpoint_id": json.Number("3"),
+		"latest_validation_id":     json.Number("100"),
+		"best_validation_id":       nil,
+		"hparams":                  map[string]any{"lr": json.Number("0.1")},
+	}
+	oldID, deferred, err := trials.remapRow(row, ids)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), oldID)
+	require.Equal(t, []deferredRef{
+		{table: "trials", column: "latest_validation_id", ref: 100},
+	}, deferred)
+	require.Equal(t, map[string]any{
+		"experiment_id":            int64(1),
+		"warm_start_checkpoint_id": nil,
+		"hparams":                  map[string]any{"lr": json.Number("0.1")},
+	}, row)
+
+	_, _, err = trials.remapRow(map[string]any{"id": "42"}, ids)
+	require.ErrorContains(t, err, "expected an integer id")
+}
+
+func TestArchiveIDKey(t *testing.T) {
+	metrics := archiveTable{name: "metrics", id: "id", partition: "partition_type"}
+	key, err := metrics.idKey(map[string]any{"id": json.Number("1"), "partition_type": "VALIDATION"})
+	require.NoError(t, err)
+	require.Equal(t, "metrics/VALIDATION", key)
+
+	// Partitions assign ids independently, so equal ids in different partitions don't collide.
+	ids := archiveIDs{}
+	ids.add(key, 1, 10)
+	ids.add("metrics/TRAINING", 1, 20)
+	require.Equal(t, int64(10), ids["metrics/VALIDATION"][1])
+
+	_, err = metrics.idKey(map[string]any{"id": json.Number("1")})
+	require.ErrorContains(t, err, "partition_type")
+
+	key, err = archiveTable{name: "trials", id: "id"}.idKey(map[string]any{})
+	require.NoError(t, err)
+	require.Equal(t, "trials", key)
+}
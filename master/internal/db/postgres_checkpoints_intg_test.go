@@ -215,10 +215,9 @@ func TestDeleteCheckpoints(t *testing.T) {
 		Name:       "checkpoint 1",
 		Comment:    "empty",
 	}
-	var mv modelv1.ModelVersion
-	err = db.QueryProto(
-		"insert_model_version", &mv, pmdl.Id, retCkpt1.Uuid, addmv.Name, addmv.Comment,
-		emptyMetadata, strings.Join(addmv.Labels, ","), addmv.Notes, user.ID,
+	_, _, err = AddModelVersionTx(
+		ctx, Bun(), pmdl.Id, retCkpt1.Uuid, addmv.Name, addmv.Comment,
+		emptyMetadata, addmv.Labels, addmv.Notes, user.ID,
 	)
 	require.NoError(t, err)
 
@@ -228,9 +227,9 @@ func TestDeleteCheckpoints(t *testing.T) {
 		Name:       "checkpoint 2",
 		Comment:    "empty",
 	}
-	err = db.QueryProto(
-		"insert_model_version", &mv, pmdl.Id, retCkpt2.Uuid, addmv.Name, addmv.Comment,
-		emptyMetadata, strings.Join(addmv.Labels, ","), addmv.Notes, user.ID,
+	_, _, err = AddModelVersionTx(
+		ctx, Bun(), pmdl.Id, retCkpt2.Uuid, addmv.Name, addmv.Comment,
+		emptyMetadata, addmv.Labels, addmv.Notes, user.ID,
 	)
 	require.NoError(t, err)
 
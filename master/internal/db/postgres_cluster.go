@@ -32,12 +32,42 @@ WHERE NOT EXISTS ( SELECT * FROM cluster_id );
 	return uuidVal[0], nil
 }
 
+// ClusterHeartbeatInterval is how often a running master updates the cluster heartbeat.
+const ClusterHeartbeatInterval = 10 * time.Minute
+
 // UpdateClusterHeartBeat updates the clusterheartbeat column in the cluster_id table.
 func (db *PgDB) UpdateClusterHeartBeat(currentClusterHeartbeat time.Time) error {
 	_, err := db.sql.Exec(`UPDATE cluster_id SET cluster_heartbeat = $1`, currentClusterHeartbeat)
 	return errors.Wrap(err, "updating cluster heartbeat")
 }
 
+// RecordMasterMigrationVersion records the migration version of the schema the running master
+// expects, or clears it with nil when the master stops. MigrateDown refuses to revert below it.
+func (db *PgDB) RecordMasterMigrationVersion(version *int64) error {
+	_, err := db.sql.Exec(`UPDATE cluster_id SET master_migration_version = $1`, version)
+	return errors.Wrap(err, "recording master migration version")
+}
+
+// MasterMigrationVersion returns the migration version recorded by a running master, or nil if
+// no master is running or the schema predates the record.
+func (db *PgDB) MasterMigrationVersion() (*int64, error) {
+	var recorded bool
+	if err := db.sql.QueryRow(`
+SELECT EXISTS (
+	SELECT 1 FROM information_schema.columns
+	WHERE table_name = 'cluster_id' AND column_name = 'master_migration_version'
+)`).Scan(&recorded); err != nil {
+		return nil, errors.Wrap(err, "checking for master migration version")
+	}
+	if !recorded {
+		return nil, nil
+	}
+
+	var version *int64
+	err := db.sql.QueryRow(`SELECT max(master_migration_version) FROM cluster_id`).Scan(&version)
+	return version, errors.Wrap(err, "reading master migration version")
+}
+
 // PeriodicTelemetryInfo returns anonymous information about the usage of the current
 // Determined cluster.
 func (db *PgDB) PeriodicTelemetryInfo() ([]byte, error) {
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun/dialect/pgdialect"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddAllocationCost records the slot-hour price of an allocation and who its cost is attributed
+// to. The user is the owner of the allocation's job, and experiment tasks are attributed to the
+// experiment's project and workspace; workspaceID attributes other tasks, such as commands.
+func AddAllocationCost(
+	ctx context.Context,
+	allocationID model.AllocationID,
+	slotHourCost float64,
+	workspaceID int,
+	labels []string,
+) error {
+	if labels == nil {
+		labels = []string{}
+	}
+	var workspace *int
+	if workspaceID != 0 {
+		workspace = &workspaceID
+	}
+
+	_, err := Bun().NewRaw(`
+INSERT INTO allocation_costs
+	(allocation_id, slot_hour_cost, user_id, workspace_id, project_id, labels)
+SELECT
+	a.allocation_id, ?, j.owner_id, coalesce(p.workspace_id, ?), e.project_id, ?
+FROM allocations a
+LEFT JOIN tasks t ON t.task_id = a.task_id
+LEFT JOIN jobs j ON j.job_id = t.job_id
+LEFT JOIN experiments e ON e.job_id = t.job_id
+LEFT JOIN projects p ON p.id = e.project_id
+WHERE a.allocation_id = ?
+ON CONFLICT (allocation_id) DO NOTHING`,
+		slotHourCost, workspace, pgdialect.Array(labels), allocationID,
+	).Exec(ctx)
+	return errors.Wrapf(err, "recording cost of allocation %s", allocationID)
+}
+
+// CostGroupBy is a dimension that cost reports can be grouped by.
+type CostGroupBy string
+
+// Cost report dimensions.
+const (
+	CostGroupByWorkspace    CostGroupBy = "workspace"
+	CostGroupByProject      CostGroupBy = "project"
+	CostGroupByUser         CostGroupBy = "user"
+	CostGroupByLabel        CostGroupBy = "label"
+	CostGroupByResourcePool CostGroupBy = "resource_pool"
+)
+
+// costGroupColumns maps each dimension to the report column it fills in.
+var costGroupColumns = map[CostGroupBy]string{
+	CostGroupByWorkspace:    "coalesce(w.name, '') AS workspace",
+	CostGroupByProject:      "coalesce(p.name, '') AS project",
+	CostGroupByUser:         "coalesce(u.username, '') AS username",
+	CostGroupByLabel:        "label",
+	CostGroupByResourcePool: "a.resource_pool",
+}
+
+// CostReportOptions selects the allocations in a cost report and how they are grouped.
+type CostReportOptions struct {
+	Start, End time.Time
+	GroupBy    []CostGroupBy
+	// SlotHourCosts prices allocations recorded before costs were, by resource pool, and
+	// DefaultSlotHourCost those in pools that are no longer configured.
+	SlotHourCosts       map[string]float64
+	DefaultSlotHourCost float64
+}
+
+// CostReportRow is one group of a cost report. Dimensions the report is not grouped by are empty.
+type CostReportRow struct {
+	Workspace    string  `bun:"workspace"`
+	Project      string  `bun:"project"`
+	Username     string  `bun:"username"`
+	Label        string  `bun:"label"`
+	ResourcePool string  `bun:"resource_pool"`
+	SlotHours    float64 `bun:"slot_hours"`
+	Cost         float64 `bun:"cost"`
+}
+
+// CostReport sums the slot-hours and cost of the time allocations spent between the start and
+// end of the report. Allocations with several labels count once toward each of their labels.
+func CostReport(ctx context.Context, opts CostReportOptions) ([]CostReportRow, error) {
+	if !opts.Start.Before(opts.End) {
+		return nil, errors.New("report start must be before its end")
+	}
+
+	var columns, groups []string
+	seen := map[CostGroupBy]bool{}
+	for _, g := range opts.GroupBy {
+		column, ok := costGroupColumns[g]
+		if !ok {
+			return nil, errors.Errorf("unknown cost report dimension %q", g)
+		}
+		if seen[g] {
+			continue
+		}
+		seen[g] = true
+		columns = append(columns, column)
+		groups = append(groups, fmt.Sprint(len(groups)+1))
+	}
+	labelJoin := ""
+	if seen[CostGroupByLabel] {
+		labelJoin = `CROSS JOIN LATERAL unnest(
+	CASE WHEN cardinality(a.labels) = 0 THEN ARRAY[''] ELSE a.labels END
+) AS label`
+	}
+	groupBy := ""
+	if len(groups) > 0 {
+		groupBy = "GROUP BY " + strings.Join(groups, ", ")
+	}
+
+	slotHourCosts, err := json.Marshal(opts.SlotHourCosts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+WITH allocs AS (
+	SELECT
+		a.resource_pool,
+		extract(EPOCH FROM (
+			least(greatest(coalesce(a.end_time, now()), a.start_time), ?0::timestamptz)
+			- greatest(a.start_time, ?1::timestamptz)
+		)) * a.slots / 3600.0 AS slot_hours,
+		coalesce(ac.slot_hour_cost, (?2::jsonb ->> a.resource_pool)::float8, ?3) AS slot_hour_cost,
+		coalesce(ac.user_id, j.owner_id) AS user_id,
+		coalesce(ac.workspace_id, ep.workspace_id) AS workspace_id,
+		coalesce(ac.project_id, e.project_id) AS project_id,
+		coalesce(ac.labels, CASE
+			WHEN jsonb_typeof(e.config -> 'labels') = 'array'
+			THEN ARRAY(SELECT jsonb_array_elements_text(e.config -> 'labels'))
+			ELSE '{}'
+		END) AS labels
+	FROM allocations a
+	LEFT JOIN allocation_costs ac ON ac.allocation_id = a.allocation_id
+	LEFT JOIN tasks t ON t.task_id = a.task_id
+	LEFT JOIN jobs j ON j.job_id = t.job_id
+	LEFT JOIN experiments e ON e.job_id = t.job_id
+	LEFT JOIN projects ep ON ep.id = e.project_id
+	WHERE a.start_time < ?0::timestamptz
+		AND coalesce(a.end_time, now()) > ?1::timestamptz
+)
+SELECT %s
+	coalesce(sum(a.slot_hours), 0) AS slot_hours,
+	coalesce(sum(a.slot_hours * a.slot_hour_cost), 0) AS cost
+FROM allocs a
+%s
+LEFT JOIN users u ON u.id = a.user_id
+LEFT JOIN workspaces w ON w.id = a.workspace_id
+LEFT JOIN projects p ON p.id = a.project_id
+%s
+ORDER BY cost DESC`,
+		strings.Join(append(columns, ""), ",\n\t"), labelJoin, groupBy,
+	)
+
+	var rows []CostReportRow
+	if err := Bun().NewRaw(
+		query, opts.End, opts.Start, string(slotHourCosts), opts.DefaultSlotHourCost,
+	).Scan(ctx, &rows); err != nil {
+		return nil, errors.Wrap(err, "building cost report")
+	}
+	return rows, nil
+}
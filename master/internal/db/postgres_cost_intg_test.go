@@ -0,0 +1,79 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestCostReport(t *testing.T) {
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db := MustResolveTestPostgres(t)
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+	ctx := context.Background()
+
+	user := RequireMockUser(t, db)
+	task := RequireMockTask(t, db, &user.ID)
+	pool := uuid.NewString()
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Four slots for two hours, recorded at 1.5 per slot-hour.
+	priced := model.Allocation{
+		AllocationID: model.AllocationID(task.TaskID + ".1"),
+		TaskID:       task.TaskID,
+		Slots:        4,
+		ResourcePool: pool,
+		StartTime:    ptrs.Ptr(start),
+		State:        ptrs.Ptr(model.AllocationStateTerminated),
+	}
+	require.NoError(t, db.AddAllocation(&priced))
+	priced.EndTime = ptrs.Ptr(start.Add(2 * time.Hour))
+	require.NoError(t, db.CompleteAllocation(&priced))
+	require.NoError(t, AddAllocationCost(ctx, priced.AllocationID, 1.5, 0, []string{"a", "b"}))
+
+	// One slot for one hour, recorded before costs were, so priced by the pool's rate.
+	unpriced := priced
+	unpriced.AllocationID = model.AllocationID(task.TaskID + ".2")
+	unpriced.Slots = 1
+	unpriced.StartTime = ptrs.Ptr(start.Add(time.Hour))
+	unpriced.EndTime = nil
+	require.NoError(t, db.AddAllocation(&unpriced))
+	unpriced.EndTime = ptrs.Ptr(start.Add(2 * time.Hour))
+	require.NoError(t, db.CompleteAllocation(&unpriced))
+
+	rows, err := CostReport(ctx, CostReportOptions{
+		// Only the second half of the first allocation falls in the report.
+		Start:         start.Add(time.Hour),
+		End:           start.Add(3 * time.Hour),
+		GroupBy:       []CostGroupBy{CostGroupByResourcePool, CostGroupByUser, CostGroupByLabel},
+		SlotHourCosts: map[string]float64{pool: 10},
+	})
+	require.NoError(t, err)
+
+	var got []CostReportRow
+	for _, row := range rows {
+		if row.ResourcePool == pool {
+			got = append(got, row)
+		}
+	}
+	require.ElementsMatch(t, []CostReportRow{
+		{Username: user.Username, Label: "", ResourcePool: pool, SlotHours: 1, Cost: 10},
+		{Username: user.Username, Label: "a", ResourcePool: pool, SlotHours: 4, Cost: 6},
+		{Username: user.Username, Label: "b", ResourcePool: pool, SlotHours: 4, Cost: 6},
+	}, got)
+
+	_, err = CostReport(ctx, CostReportOptions{
+		Start: start, End: start.Add(time.Hour), GroupBy: []CostGroupBy{"team"},
+	})
+	require.ErrorContains(t, err, "unknown cost report dimension")
+}
@@ -393,11 +393,12 @@ SELECT t.id FROM (
 // MetricMeasurements represents a metric measured by all possible
 // independent variables.
 type MetricMeasurements struct {
-	Values  map[string]interface{}
-	Batches uint
-	Time    time.Time
-	Epoch   *float64 `json:"epoch,omitempty"`
-	TrialID int32
+	Values     map[string]interface{}
+	Histograms map[string]model.Histogram
+	Batches    uint
+	Time       time.Time
+	Epoch      *float64 `json:"epoch,omitempty"`
+	TrialID    int32
 }
 
 // ExperimentBestSearcherValidation returns the best searcher validation for an experiment.
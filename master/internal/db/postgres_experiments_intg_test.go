@@ -195,10 +195,9 @@ func addCheckpointToModelRegistry(db *PgDB, checkpointUUID uuid.UUID, user model
 		Name:       "checkpoint exp",
 		Comment:    "empty",
 	}
-	var mv modelv1.ModelVersion
-	if err := db.QueryProto(
-		"insert_model_version", &mv, pmdl.Id, retCkpt1.Uuid, addmv.Name, addmv.Comment,
-		emptyMetadata, strings.Join(addmv.Labels, ","), addmv.Notes, user.ID,
+	if _, _, err := AddModelVersionTx(
+		context.TODO(), Bun(), pmdl.Id, retCkpt1.Uuid, addmv.Name, addmv.Comment,
+		emptyMetadata, addmv.Labels, addmv.Notes, user.ID,
 	); err != nil {
 		return fmt.Errorf("inserting model version: %w", err)
 	}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// compactedReportsKey marks a training metrics row as the compaction of that many reports, so
+// that it is not compacted again.
+const compactedReportsKey = "compacted_reports"
+
+// MetricRetentionPolicy is how long the training metrics of trials are kept as reported, and how
+// many consecutive reports are rolled into one once they are older than that.
+type MetricRetentionPolicy struct {
+	bun.BaseModel `bun:"table:project_metric_retention_policies"`
+
+	ProjectID int `bun:"project_id,pk"`
+	// RawRetentionDays of 0 keeps metrics as reported forever.
+	RawRetentionDays int `bun:"raw_retention_days"`
+	BucketSize       int `bun:"bucket_size"`
+}
+
+// GetProjectMetricRetentionPolicy returns the policy overriding the default for a project, or nil
+// if the project uses the default.
+func GetProjectMetricRetentionPolicy(
+	ctx context.Context, projectID int,
+) (*MetricRetentionPolicy, error) {
+	var p MetricRetentionPolicy
+	err := Bun().NewSelect().Model(&p).Where("project_id = ?", projectID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "getting metric retention policy of project %d", projectID)
+	}
+	return &p, nil
+}
+
+// SetProjectMetricRetentionPolicy overrides the default policy for a project.
+func SetProjectMetricRetentionPolicy(ctx context.Context, p *MetricRetentionPolicy) error {
+	_, err := Bun().NewInsert().Model(p).
+		On("CONFLICT (project_id) DO UPDATE").
+		Set("raw_retention_days = EXCLUDED.raw_retention_days").
+		Set("bucket_size = EXCLUDED.bucket_size").
+		Exec(ctx)
+	return errors.Wrapf(err, "setting metric retention policy of project %d", p.ProjectID)
+}
+
+// DeleteProjectMetricRetentionPolicy makes a project use the default policy again.
+func DeleteProjectMetricRetentionPolicy(ctx context.Context, projectID int) error {
+	_, err := Bun().NewDelete().Model((*MetricRetentionPolicy)(nil)).
+		Where("project_id = ?", projectID).
+		Exec(ctx)
+	return errors.Wrapf(err, "deleting metric retention policy of project %d", projectID)
+}
+
+// TrialMetricRetention is a trial with training metrics to compact, and the policy that applies
+// to it.
+type TrialMetricRetention struct {
+	TrialID          int `bun:"trial_id"`
+	RawRetentionDays int `bun:"raw_retention_days"`
+	BucketSize       int `bun:"bucket_size"`
+}
+
+// TrialsWithMetricsToCompact returns the trials with at least one bucket of training metrics
+// older than the retention of their project's policy, or of the default policy.
+func TrialsWithMetricsToCompact(
+	ctx context.Context, defaults MetricRetentionPolicy,
+) ([]TrialMetricRetention, error) {
+	var res []TrialMetricRetention
+	err := Bun().NewRaw(`
+SELECT
+	m.trial_id,
+	coalesce(p.raw_retention_days, ?0) AS raw_retention_days,
+	coalesce(p.bucket_size, ?1) AS bucket_size
+FROM metrics m
+JOIN trials t ON t.id = m.trial_id
+JOIN experiments e ON e.id = t.experiment_id
+LEFT JOIN project_metric_retention_policies p ON p.project_id = e.project_id
+WHERE m.partition_type = ?2
+	AND NOT m.archived
+	AND m.metrics->?3 IS NULL
+	AND coalesce(p.raw_retention_days, ?0) > 0
+	AND m.end_time < now() - make_interval(days => coalesce(p.raw_retention_days, ?0))
+GROUP BY m.trial_id, p.raw_retention_days, p.bucket_size
+HAVING count(*) >= coalesce(p.bucket_size, ?1)
+ORDER BY m.trial_id`,
+		defaults.RawRetentionDays, defaults.BucketSize, TrainingMetric, compactedReportsKey,
+	).Scan(ctx, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding trials with metrics to compact")
+	}
+	return res, nil
+}
+
+// CompactTrialMetrics rolls each bucket of consecutive training metric reports of a trial older
+// than the retention into a single report at the bucket's last batch. The compacted report holds
+// the mean of each metric as its average metrics, along with the min, max and number of reports
+// of the bucket, so that readers of training metrics see a downsampled series. Reports that don't
+// fill a bucket are left until they do. It returns the number of reports compacted.
+func CompactTrialMetrics(ctx context.Context, r TrialMetricRetention) (int, error) {
+	if r.RawRetentionDays <= 0 {
+		return 0, nil
+	}
+
+	var compacted int
+	err := Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var reports []metricReport
+		if err := tx.NewSelect().Table("metrics").
+			Column("id", "trial_run_id", "total_batches", "end_time", "metrics").
+			Where("trial_id = ?", r.TrialID).
+			Where("partition_type = ?", TrainingMetric).
+			Where("archived = false").
+			Where("metrics->? IS NULL", compactedReportsKey).
+			Where("end_time < now() - make_interval(days => ?)", r.RawRetentionDays).
+			Order("total_batches").
+			For("UPDATE").
+			Scan(ctx, &reports); err != nil {
+			return errors.Wrap(err, "getting metrics to compact")
+		}
+
+		for _, b := range compactMetricReports(reports, r.BucketSize) {
+			if _, err := tx.NewDelete().Table("metrics").
+				Where("trial_id = ?", r.TrialID).
+				Where("partition_type = ?", TrainingMetric).
+				Where("id IN (?)", bun.In(b.ids)).
+				Exec(ctx); err != nil {
+				return errors.Wrap(err, "deleting compacted metrics")
+			}
+			if _, err := tx.NewRaw(`
+INSERT INTO metrics
+	(trial_id, trial_run_id, end_time, metrics, total_batches, partition_type, metric_group)
+VALUES
+	(?, ?, ?, ?, ?, ?, ?)`,
+				r.TrialID, b.TrialRunID, b.EndTime, b.Metrics, b.TotalBatches,
+				TrainingMetric, model.TrainingMetricGroup,
+			).Exec(ctx); err != nil {
+				return errors.Wrap(err, "inserting compacted metrics")
+			}
+			compacted += len(b.ids)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "compacting metrics of trial %d", r.TrialID)
+	}
+	return compacted, nil
+}
+
+type metricReport struct {
+	ID           int           `bun:"id"`
+	TrialRunID   int           `bun:"trial_run_id"`
+	TotalBatches int           `bun:"total_batches"`
+	EndTime      time.Time     `bun:"end_time"`
+	Metrics      model.JSONObj `bun:"metrics"`
+}
+
+type compactedMetricReport struct {
+	metricReport
+	ids []int
+}
+
+// compactMetricReports rolls every bucketSize consecutive reports into one, dropping the batch
+// metrics. Numeric metrics are summarized by their mean, min and max; other metrics keep their
+// last value. A trailing partial bucket is not compacted.
+func compactMetricReports(reports []metricReport, bucketSize int) []compactedMetricReport {
+	if bucketSize < 1 {
+		return nil
+	}
+	metricsKey := model.TrialMetricsJSONPath(false)
+
+	var res []compactedMetricReport
+	for start := 0; start+bucketSize <= len(reports); start += bucketSize {
+		bucket := reports[start : start+bucketSize]
+		sums, counts := map[string]float64{}, map[string]int{}
+		avg, mins, maxs := map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}
+		ids := make([]int, 0, bucketSize)
+		for _, r := range bucket {
+			ids = append(ids, r.ID)
+			metrics, _ := r.Metrics[metricsKey].(map[string]interface{})
+			for name, v := range metrics {
+				f, ok := v.(float64)
+				if !ok {
+					if _, numeric := counts[name]; !numeric {
+						avg[name] = v
+					}
+					continue
+				}
+				sums[name] += f
+				counts[name]++
+				if m, ok := mins[name].(float64); !ok || f < m {
+					mins[name] = f
+				}
+				if m, ok := maxs[name].(float64); !ok || f > m {
+					maxs[name] = f
+				}
+			}
+		}
+		for name, sum := range sums {
+			avg[name] = sum / float64(counts[name])
+		}
+
+		last := bucket[len(bucket)-1]
+		res = append(res, compactedMetricReport{
+			metricReport: metricReport{
+				TrialRunID:   last.TrialRunID,
+				TotalBatches: last.TotalBatches,
+				EndTime:      last.EndTime,
+				Metrics: model.JSONObj{
+					metricsKey:          avg,
+					"min_metrics":       mins,
+					"max_metrics":       maxs,
+					compactedReportsKey: len(bucket),
+				},
+			},
+			ids: ids,
+		})
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestCompactTrialMetrics(t *testing.T) {
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db := MustResolveTestPostgres(t)
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+	ctx := context.Background()
+
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+	trialID := RequireMockTrialID(t, db, exp)
+	addMetrics(ctx, t, db, trialID,
+		`[{"loss": 4.0}, {"loss": 2.0}, {"loss": 3.0}, {"loss": 1.0}, {"loss": 5.0}]`,
+		`[{"val_loss": 1.5}]`, false)
+
+	defaults := MetricRetentionPolicy{RawRetentionDays: 0, BucketSize: 2}
+	trialsToCompact := func() []int {
+		trials, err := TrialsWithMetricsToCompact(ctx, defaults)
+		require.NoError(t, err)
+		var ids []int
+		for _, r := range trials {
+			ids = append(ids, r.TrialID)
+		}
+		return ids
+	}
+
+	// Nothing is compacted while the project keeps metrics forever.
+	require.NotContains(t, trialsToCompact(), trialID)
+
+	require.NoError(t, SetProjectMetricRetentionPolicy(ctx, &MetricRetentionPolicy{
+		ProjectID: exp.ProjectID, RawRetentionDays: 7, BucketSize: 2,
+	}))
+	defer func() {
+		require.NoError(t, DeleteProjectMetricRetentionPolicy(ctx, exp.ProjectID))
+	}()
+	p, err := GetProjectMetricRetentionPolicy(ctx, exp.ProjectID)
+	require.NoError(t, err)
+	require.Equal(t, 7, p.RawRetentionDays)
+
+	// Metrics newer than the retention are kept as reported.
+	require.NotContains(t, trialsToCompact(), trialID)
+
+	_, err = Bun().NewUpdate().Table("metrics").
+		Set("end_time = now() - interval '8 days'").
+		Where("trial_id = ?", trialID).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	trials, err := TrialsWithMetricsToCompact(ctx, defaults)
+	require.NoError(t, err)
+	expected := TrialMetricRetention{TrialID: trialID, RawRetentionDays: 7, BucketSize: 2}
+	require.Contains(t, trials, expected)
+
+	compacted, err := CompactTrialMetrics(ctx, expected)
+	require.NoError(t, err)
+	require.Equal(t, 4, compacted)
+
+	// Readers see the compacted series, the partial bucket and untouched validation metrics.
+	training := string(model.TrainingMetricGroup)
+	reports, err := GetMetrics(ctx, trialID, 0, 10, &training)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	require.Equal(t, []int32{2, 4, 5}, []int32{
+		reports[0].TotalBatches, reports[1].TotalBatches, reports[2].TotalBatches,
+	})
+	require.Equal(t, map[string]any{
+		"avg_metrics":       map[string]any{"loss": 3.0},
+		"min_metrics":       map[string]any{"loss": 2.0},
+		"max_metrics":       map[string]any{"loss": 4.0},
+		"compacted_reports": 2.0,
+	}, reports[0].Metrics.AsMap())
+
+	validation := string(model.ValidationMetricGroup)
+	reports, err = GetMetrics(ctx, trialID, 0, 10, &validation)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+
+	// Compacted reports are not compacted again.
+	require.NotContains(t, trialsToCompact(), trialID)
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestCompactMetricReports(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := func(id int, avgMetrics map[string]interface{}) metricReport {
+		return metricReport{
+			ID:           id,
+			TrialRunID:   id / 2,
+			TotalBatches: id * 100,
+			EndTime:      start.Add(time.Duration(id) * time.Minute),
+			Metrics: model.JSONObj{
+				"avg_metrics":   avgMetrics,
+				"batch_metrics": []interface{}{avgMetrics},
+			},
+		}
+	}
+	reports := []metricReport{
+		report(1, map[string]interface{}{"loss": 4.0, "phase": "warmup"}),
+		report(2, map[string]interface{}{"loss": 2.0, "lr": 0.1, "phase": "train"}),
+		report(3, map[string]interface{}{"loss": 3.0, "lr": "NaN"}),
+		report(4, map[string]interface{}{"loss": 1.0}),
+		report(5, map[string]interface{}{"loss": 1.0}),
+	}
+
+	require.Empty(t, compactMetricReports(reports[:1], 2))
+
+	res := compactMetricReports(reports, 2)
+	require.Len(t, res, 2)
+
+	require.Equal(t, []int{1, 2}, res[0].ids)
+	require.Equal(t, 1, res[0].TrialRunID)
+	require.Equal(t, 200, res[0].TotalBatches)
+	require.Equal(t, start.Add(2*time.Minute), res[0].EndTime)
+	require.Equal(t, model.JSONObj{
+		"avg_metrics":       map[string]interface{}{"loss": 3.0, "lr": 0.1, "phase": "train"},
+		"min_metrics":       map[string]interface{}{"loss": 2.0, "lr": 0.1},
+		"max_metrics":       map[string]interface{}{"loss": 4.0, "lr": 0.1},
+		"compacted_reports": 2,
+	}, res[0].Metrics)
+
+	// Non-numeric values of a numeric metric don't count towards its summary.
+	require.Equal(t, []int{3, 4}, res[1].ids)
+	require.Equal(t, model.JSONObj{
+		"avg_metrics":       map[string]interface{}{"loss": 2.0, "lr": "NaN"},
+		"min_metrics":       map[string]interface{}{"loss": 1.0},
+		"max_metrics":       map[string]interface{}{"loss": 3.0},
+		"compacted_reports": 2,
+	}, res[1].Metrics)
+}
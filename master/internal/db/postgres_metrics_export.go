@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// MetricsExportOptions selects the metric reports of a bulk metrics export.
+type MetricsExportOptions struct {
+	ExperimentIDs []int
+	Group         model.MetricGroup
+	// Metrics are the names of the metrics to export. All metrics of the group are exported if
+	// it is empty.
+	Metrics      []string
+	StartBatches int
+	// EndBatches of 0 exports reports up to the last batch.
+	EndBatches int
+}
+
+// MetricsExportTrial is a trial whose metrics are exported.
+type MetricsExportTrial struct {
+	ID           int           `bun:"id"`
+	ExperimentID int           `bun:"experiment_id"`
+	HParams      model.JSONObj `bun:"hparams"`
+}
+
+// MetricsExportRow is a metric report of a trial in a metrics export.
+type MetricsExportRow struct {
+	TrialID      int            `bun:"trial_id"`
+	TotalBatches int            `bun:"total_batches"`
+	EndTime      time.Time      `bun:"end_time"`
+	Metrics      map[string]any `bun:"metrics"`
+}
+
+// MetricsExportTrials returns the trials of the experiments, ordered by ID.
+func MetricsExportTrials(ctx context.Context, experimentIDs []int) ([]MetricsExportTrial, error) {
+	var trials []MetricsExportTrial
+	if len(experimentIDs) == 0 {
+		return trials, nil
+	}
+	if err := Bun().NewSelect().Table("trials").
+		Column("id", "experiment_id", "hparams").
+		Where("experiment_id IN (?)", bun.In(experimentIDs)).
+		Order("id").
+		Scan(ctx, &trials); err != nil {
+		return nil, errors.Wrap(err, "getting trials to export metrics of")
+	}
+	return trials, nil
+}
+
+// MetricsExportNames returns the names of the metrics of the group reported by the trials of the
+// experiments, in order.
+func MetricsExportNames(
+	ctx context.Context, experimentIDs []int, group model.MetricGroup,
+) ([]string, error) {
+	names := []string{}
+	if len(experimentIDs) == 0 {
+		return names, nil
+	}
+	if err := Bun().NewSelect().Table("trials").
+		ColumnExpr("DISTINCT jsonb_object_keys(summary_metrics->?) AS name",
+			model.TrialSummaryMetricsJSONPath(group)).
+		Where("experiment_id IN (?)", bun.In(experimentIDs)).
+		Where("summary_metrics->? IS NOT NULL", model.TrialSummaryMetricsJSONPath(group)).
+		Order("name").
+		Scan(ctx, &names); err != nil {
+		return nil, errors.Wrap(err, "getting names of metrics to export")
+	}
+	return names, nil
+}
+
+// ExportMetrics calls fn with each unarchived metric report of the group of the trials of the
+// experiments within the batch range, ordered by trial and batch. Reports are read from the
+// database as they are exported rather than all at once, and only hold the selected metrics.
+// Reports holding none of the selected metrics are skipped.
+func ExportMetrics(
+	ctx context.Context, opts MetricsExportOptions, fn func(*MetricsExportRow) error,
+) error {
+	if len(opts.ExperimentIDs) == 0 {
+		return nil
+	}
+	jsonPath := model.TrialMetricsJSONPath(opts.Group == model.ValidationMetricGroup)
+
+	q := BunSelectMetricsQuery(opts.Group, false).
+		TableExpr("metrics").
+		Column("trial_id", "total_batches", "end_time").
+		ColumnExpr("metrics->? AS metrics", jsonPath).
+		Where("trial_id IN (SELECT id FROM trials WHERE experiment_id IN (?))",
+			bun.In(opts.ExperimentIDs)).
+		Where("total_batches >= ?", opts.StartBatches).
+		Order("trial_id", "total_batches")
+	if opts.EndBatches > 0 {
+		q.Where("total_batches <= ?", opts.EndBatches)
+	}
+	if len(opts.Metrics) > 0 {
+		q.Where("jsonb_exists_any(metrics->?, ?)", jsonPath, pgdialect.Array(opts.Metrics))
+	}
+
+	rows, err := q.Rows(ctx)
+	if err != nil {
+		return errors.Wrap(err, "querying metrics to export")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row MetricsExportRow
+		if err := Bun().ScanRow(ctx, rows, &row); err != nil {
+			return errors.Wrap(err, "reading metrics to export")
+		}
+		if len(opts.Metrics) > 0 {
+			selected := make(map[string]any, len(opts.Metrics))
+			for _, name := range opts.Metrics {
+				if v, ok := row.Metrics[name]; ok {
+					selected[name] = v
+				}
+			}
+			row.Metrics = selected
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(rows.Err(), "reading metrics to export")
+}
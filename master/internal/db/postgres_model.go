@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddModelVersionTx registers a checkpoint as the next version of a model and returns the ID and
+// number of the new version.
+func AddModelVersionTx(
+	ctx context.Context, idb bun.IDB, modelID int32, checkpointUUID, name, comment string,
+	metadata []byte, labels []string, notes string, userID model.UserID,
+) (int, int32, error) {
+	var id int
+	var version int32
+	if err := idb.NewRaw(`
+INSERT INTO model_versions (
+    model_id, version, checkpoint_uuid, name, comment, metadata, labels, notes, user_id,
+    creation_time, last_updated_time
+)
+VALUES (
+    ?, (SELECT COALESCE(MAX(version), 0) + 1 FROM model_versions WHERE model_id = ?),
+    ?, ?, ?, ?, STRING_TO_ARRAY(?, ','), ?, ?, current_timestamp, current_timestamp
+)
+RETURNING id, version`,
+		modelID, modelID, checkpointUUID, name, comment, string(metadata),
+		strings.Join(labels, ","), notes, userID,
+	).Scan(ctx, &id, &version); err != nil {
+		return 0, 0, err
+	}
+	return id, version, nil
+}
@@ -143,21 +143,19 @@ func TestModels(t *testing.T) {
 				Labels:     []string{"some label"},
 				Notes:      "some notes",
 			}
-			var mv modelv1.ModelVersion
-			err = db.QueryProto(
-				"insert_model_version", &mv, pmdl.Id, ckpt.UUID, expected.Name, expected.Comment,
-				emptyMetadata, strings.Join(expected.Labels, ","), expected.Notes, user.ID,
+			_, version, err := AddModelVersionTx(
+				ctx, Bun(), pmdl.Id, ckpt.UUID.String(), expected.Name, expected.Comment,
+				emptyMetadata, expected.Labels, expected.Notes, user.ID,
 			)
 			require.NoError(t, err)
-			requireModelVersionOK(expected, &mv)
 
 			var retMv modelv1.ModelVersion
-			err = db.QueryProto("get_model_version", &retMv, pmdl.Id, mv.Version)
+			err = db.QueryProto("get_model_version", &retMv, pmdl.Id, version)
 			require.NoError(t, err)
-			requireModelVersionOK(expected, &mv)
+			requireModelVersionOK(expected, &retMv)
 
 			var retMvs []*modelv1.ModelVersion
-			err = db.QueryProto("get_model_versions", &retMvs, pmdl.Id)
+			err = db.QueryProto("get_model_versions", &retMvs, pmdl.Id, "")
 			require.NoError(t, err)
 			require.Len(t, retMvs, 1)
 			requireModelVersionOK(expected, retMvs[0])
@@ -13,10 +13,12 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
 	"github.com/determined-ai/determined/proto/pkg/trialv1"
 )
 
@@ -254,6 +256,7 @@ func (db *PgDB) calculateFullTrialSummaryMetrics(
 		if err = rows.Scan(&name, &metric); err != nil {
 			return nil, fmt.Errorf("scanning summary metric row: %w", err)
 		}
+		addHistogramQuantiles(metric)
 		metrics[name] = metric
 	}
 
@@ -398,6 +401,14 @@ func (db *PgDB) addTrialMetrics(
 	default:
 		return 0, fmt.Errorf("cannot add metric with non numeric 'epoch' value got %v", v)
 	}
+	if len(m.Metrics.Histograms) > 0 {
+		avgMetrics, err := withHistogramMetrics(m.Metrics.AvgMetrics, m.Metrics.Histograms)
+		if err != nil {
+			return 0, err
+		}
+		m = proto.Clone(m).(*trialv1.TrialMetrics)
+		m.Metrics.AvgMetrics = avgMetrics
+	}
 	return rollbacks, db.withTransaction(fmt.Sprintf("add trial metrics %s", mGroup),
 		func(tx *sqlx.Tx) error {
 			rollbacks, err = db._addTrialMetricsTx(ctx, tx, m, mGroup)
@@ -405,6 +416,33 @@ func (db *PgDB) addTrialMetrics(
 		})
 }
 
+// withHistogramMetrics returns the aggregate metrics with the histogram metrics added, in the
+// form they are stored in.
+func withHistogramMetrics(
+	avgMetrics *structpb.Struct, histograms map[string]*commonv1.Histogram,
+) (*structpb.Struct, error) {
+	res := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	for name, v := range avgMetrics.GetFields() {
+		res.Fields[name] = v
+	}
+	for name, h := range histograms {
+		if _, ok := res.Fields[name]; ok {
+			return nil, api.AsValidationError(
+				"histogram metric %q has the same name as another metric", name)
+		}
+		hist := model.HistogramFromProto(h)
+		if err := hist.Validate(); err != nil {
+			return nil, api.AsValidationError("histogram metric %q: %s", name, err)
+		}
+		v, err := structpb.NewValue(hist.ToJSON())
+		if err != nil {
+			return nil, errors.Wrapf(err, "converting histogram metric %q", name)
+		}
+		res.Fields[name] = v
+	}
+	return res, nil
+}
+
 const (
 	// InfPostgresString how we store infinity in JSONB in postgres.
 	InfPostgresString = "Infinity"
@@ -427,6 +465,8 @@ const (
 	MetricTypeArray = "array"
 	// MetricTypeNull is the summary metric type for array types.
 	MetricTypeNull = "null"
+	// MetricTypeHistogram is the summary metric type for histogram metrics.
+	MetricTypeHistogram = model.HistogramMetricType
 )
 
 func jsonAnyToFloat(v any) float64 {
@@ -505,7 +545,11 @@ func calculateNewSummaryMetrics(
 		case bool:
 			metricType = MetricTypeBool
 		case map[string]any:
-			metricType = MetricTypeObject
+			if _, ok := model.HistogramFromJSON(metricValue); ok {
+				metricType = MetricTypeHistogram
+			} else {
+				metricType = MetricTypeObject
+			}
 		case []any:
 			metricType = MetricTypeArray
 		case nil:
@@ -561,11 +605,34 @@ func calculateNewSummaryMetrics(
 		}
 
 		metric["last"] = replaceSpecialFloatsWithString(metrics.Fields[metricName])
+		addHistogramQuantiles(metric)
 	}
 
 	return summaryMetrics
 }
 
+// addHistogramQuantiles records the quantiles of the last value of a histogram summary metric,
+// so that they can be shown and filtered on like numeric summaries.
+func addHistogramQuantiles(summaryMetric map[string]any) {
+	if summaryMetric["type"] != MetricTypeHistogram {
+		return
+	}
+	for _, q := range model.HistogramQuantiles {
+		delete(summaryMetric, q.Key)
+	}
+	last := summaryMetric["last"]
+	if v, ok := last.(*structpb.Value); ok {
+		last = v.AsInterface()
+	}
+	h, ok := model.HistogramFromJSON(last)
+	if !ok {
+		return
+	}
+	for _, q := range model.HistogramQuantiles {
+		summaryMetric[q.Key] = replaceSpecialFloatsWithString(h.Quantile(q.Quantile))
+	}
+}
+
 // AddCheckpointMetadata persists metadata for a completed checkpoint to the database.
 func AddCheckpointMetadata(ctx context.Context, m *model.CheckpointV2) error {
 	var size int64
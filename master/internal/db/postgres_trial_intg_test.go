@@ -1316,3 +1316,49 @@ func TestConcurrentMetricUpdate(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestHistogramMetricsReported(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db := MustResolveTestPostgres(t)
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+	trialID := RequireMockTrialID(t, db, exp)
+
+	weights := &commonv1.Histogram{BucketEdges: []float64{0, 1, 2}, Counts: []float64{1, 3}}
+	avgMetrics, err := structpb.NewStruct(map[string]any{"loss": 0.5})
+	require.NoError(t, err)
+	require.NoError(t, db.AddTrainingMetrics(ctx, &trialv1.TrialMetrics{
+		TrialId:        int32(trialID),
+		StepsCompleted: 1,
+		Metrics: &commonv1.Metrics{
+			AvgMetrics: avgMetrics,
+			Histograms: map[string]*commonv1.Histogram{"weights": weights},
+		},
+	}))
+
+	training := string(model.TrainingMetricGroup)
+	reports, err := GetMetrics(ctx, trialID, 0, 10, &training)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, weights.Counts, reports[0].Histograms["weights"].Counts)
+	require.NotContains(t, reports[0].Metrics.AsMap()["avg_metrics"], "weights")
+
+	// Both incremental and full summary computations record quantiles of the last histogram.
+	validate := func() {
+		var summary model.JSONObj
+		require.NoError(t, Bun().NewSelect().Table("trials").
+			ColumnExpr("summary_metrics->'avg_metrics'->'weights'").
+			Where("id = ?", trialID).
+			Scan(ctx, &summary))
+		require.Equal(t, MetricTypeHistogram, summary["type"])
+		require.InDelta(t, 4.0/3, summary["p50"], 1e-9)
+	}
+	validate()
+	require.NoError(t, db.withTransaction("recompute summary metrics", func(tx *sqlx.Tx) error {
+		return db.fullTrialSummaryMetricsRecompute(ctx, tx, trialID)
+	}))
+	validate()
+}
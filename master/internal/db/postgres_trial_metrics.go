@@ -14,6 +14,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
 	"github.com/determined-ai/determined/proto/pkg/trialv1"
 )
 
@@ -320,11 +321,36 @@ func GetMetrics(ctx context.Context, trialID, afterBatches, limit int,
 		// Truncate the timestamp to milliseconds to play nice with the harness's
 		// parse_protobuf_timestamp function
 		res[i].EndTime = timestamppb.New(res[i].EndTime.AsTime().Truncate(time.Millisecond))
+		moveHistogramMetrics(res[i])
 	}
 
 	return res, err
 }
 
+// moveHistogramMetrics moves the histogram metrics of a report out of its metrics and into its
+// typed histograms.
+func moveHistogramMetrics(r *trialv1.MetricsReport) {
+	for _, path := range []string{
+		model.TrialMetricsJSONPath(false), model.TrialMetricsJSONPath(true),
+	} {
+		metrics := r.Metrics.GetFields()[path].GetStructValue()
+		for name, v := range metrics.GetFields() {
+			if v.GetStructValue().GetFields()["type"].GetStringValue() != MetricTypeHistogram {
+				continue
+			}
+			h, ok := model.HistogramFromJSON(v.AsInterface())
+			if !ok {
+				continue
+			}
+			if r.Histograms == nil {
+				r.Histograms = map[string]*commonv1.Histogram{}
+			}
+			r.Histograms[name] = h.ToProto()
+			delete(metrics.Fields, name)
+		}
+	}
+}
+
 // shallowUnionMetrics unions non-overlapping keys of two metrics bodies.
 func shallowUnionMetrics(oldBody, newBody *metricsBody) (*metricsBody, error) {
 	if oldBody == nil {
@@ -8,6 +8,8 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
 )
 
 func TestMetricsBodyToJSON(t *testing.T) {
@@ -52,3 +54,39 @@ func TestMetricsBodyToJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestHistogramMetrics(t *testing.T) {
+	avgMetrics, err := structpb.NewStruct(map[string]any{"loss": 1.0})
+	require.NoError(t, err)
+	weights := &commonv1.Histogram{BucketEdges: []float64{0, 1, 2}, Counts: []float64{1, 3}}
+
+	_, err = withHistogramMetrics(avgMetrics, map[string]*commonv1.Histogram{"loss": weights})
+	require.ErrorContains(t, err, "same name")
+	_, err = withHistogramMetrics(avgMetrics, map[string]*commonv1.Histogram{
+		"weights": {BucketEdges: []float64{0}, Counts: []float64{1}},
+	})
+	require.ErrorContains(t, err, "bucket edges")
+
+	merged, err := withHistogramMetrics(avgMetrics,
+		map[string]*commonv1.Histogram{"weights": weights})
+	require.NoError(t, err)
+	require.Len(t, avgMetrics.Fields, 1)
+
+	// Summaries record the quantiles of the last histogram.
+	summary := calculateNewSummaryMetrics(model.JSONObj{}, merged)
+	weightsSummary := summary["weights"].(map[string]any)
+	require.Equal(t, MetricTypeHistogram, weightsSummary["type"])
+	require.InDelta(t, 4.0/3, weightsSummary["p50"], 1e-9)
+	require.NotContains(t, weightsSummary, "count")
+	require.Equal(t, MetricTypeNumber, summary["loss"].(map[string]any)["type"])
+
+	// Reports return histograms in their typed form only.
+	metrics, err := structpb.NewStruct(map[string]any{"avg_metrics": merged.AsMap()})
+	require.NoError(t, err)
+	report := &trialv1.MetricsReport{Metrics: metrics}
+	moveHistogramMetrics(report)
+	require.Equal(t, map[string]any{"avg_metrics": map[string]any{"loss": 1.0}},
+		report.Metrics.AsMap())
+	require.Equal(t, weights.BucketEdges, report.Histograms["weights"].BucketEdges)
+	require.Equal(t, weights.Counts, report.Histograms["weights"].Counts)
+}
@@ -140,6 +140,7 @@ func newExperiment(
 	taskSpec *tasks.TaskSpec,
 	system *actor.System,
 ) (*experiment, []command.LaunchWarning, error) {
+	conf := m.config.Load()
 	resources := activeConfig.Resources()
 	workspaceModel, err := workspace.WorkspaceByProjectID(context.TODO(), expModel.ProjectID)
 	if err != nil && errors.Cause(err) != sql.ErrNoRows {
@@ -167,7 +168,7 @@ func newExperiment(
 		if err != nil {
 			return nil, launchWarnings, fmt.Errorf("getting resource availability: %w", err)
 		}
-		if m.config.ResourceManager.AgentRM != nil && m.config.LaunchError && len(launchWarnings) > 0 {
+		if conf.ResourceManager.AgentRM != nil && conf.LaunchError && len(launchWarnings) > 0 {
 			return nil, nil, errors.New("slots requested exceeds cluster capacity")
 		}
 	}
@@ -32,8 +32,8 @@ const (
 	metricIDValidation    string = "validation"
 )
 
-var metricIDTemplate = regexp.MustCompile(
-	`(?P<group>[[:print:]]+?)\.(?P<name>[[:print:]]+)\.(?P<qualifier>min|max|mean|last)`)
+var metricIDTemplate = regexp.MustCompile(`(?P<group>[[:print:]]+?)\.(?P<name>[[:print:]]+)\.` +
+	`(?P<qualifier>min|max|mean|last|p25|p50|p75|p95|p5)`)
 
 type (
 	filterConjunction string
@@ -704,6 +704,20 @@ func (_m *ResourceManager) TaskContainerDefaults(ctx actor.Messenger, resourcePo
 	return r0, r1
 }
 
+// UpdateResourcePoolConfigs provides a mock function with given fields: _a0, _a1
+func (_m *ResourceManager) UpdateResourcePoolConfigs(_a0 actor.Messenger, _a1 sproto.UpdateResourcePoolConfigs) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(actor.Messenger, sproto.UpdateResourcePoolConfigs) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateCommandResources provides a mock function with given fields: _a0, _a1
 func (_m *ResourceManager) ValidateCommandResources(_a0 actor.Messenger, _a1 sproto.ValidateCommandResourcesRequest) (sproto.ValidateCommandResourcesResponse, error) {
 	ret := _m.Called(_a0, _a1)
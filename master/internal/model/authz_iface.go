@@ -19,12 +19,15 @@ type ModelAuthZ interface {
 	// GET /api/v1/models/{model_name}
 	// GET /api/v1/models/{model_name}/versions/{model_version_num}
 	// GET /api/v1/models/{model_name}/versions
+	// GET /api/v1/models/{model_name}/versions/{model_version_num}/transitions
+	// GET /api/v1/models/{model_name}/versions/{model_version_num}/lineage
 	CanGetModel(ctx context.Context, curUser model.User,
 		m *modelv1.Model, workspaceID int32,
 	) error
 	// PATCH /api/v1/models/{model_name}
 	// PATCH /api/v1/models/{model_name}/versions/{model_version_num}
 	// POST /api/v1/models/{model_name}/versions
+	// POST /api/v1/models/{model_name}/versions/{model_version_num}/transition
 	// POST /api/v1/models/{model_name}/archive
 	// POST /api/v1/models/{model_name}/unarchive
 	CanEditModel(ctx context.Context, curUser model.User,
@@ -0,0 +1,223 @@
+package prom
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+var (
+	trialMetricDesc = prometheus.NewDesc(
+		"det_trial_latest_metric",
+		"the latest value reported for a metric of an active trial",
+		[]string{"experiment_id", "trial_id", "workspace", "project", "metric_group", "metric_name"},
+		nil,
+	)
+
+	trialMetricSeriesDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: "det",
+		Name:      "trial_metric_series_dropped_total",
+		Help:      "the number of trial metric series not exported because of max_trial_series",
+	})
+
+	poolQueuedJobsDesc = prometheus.NewDesc(
+		"det_resource_pool_queued_jobs",
+		"the number of jobs queued in a resource pool",
+		[]string{"resource_pool"}, nil,
+	)
+	poolScheduledJobsDesc = prometheus.NewDesc(
+		"det_resource_pool_scheduled_jobs",
+		"the number of jobs scheduled in a resource pool",
+		[]string{"resource_pool"}, nil,
+	)
+	poolOldestPendingDesc = prometheus.NewDesc(
+		"det_resource_pool_oldest_pending_allocation_seconds",
+		"how long the oldest allocation still pending in a resource pool has been waiting",
+		[]string{"resource_pool"}, nil,
+	)
+	poolAllocationsDesc = prometheus.NewDesc(
+		"det_resource_pool_allocations",
+		"the number of allocations in a resource pool by allocation state",
+		[]string{"resource_pool", "state"}, nil,
+	)
+
+	// ExporterMetrics is a prometheus registry containing the trial metrics and resource pool
+	// stats exported when observability.exporter is enabled.
+	ExporterMetrics = prometheus.NewRegistry()
+
+	trialMetrics = newTrialMetricsCollector()
+)
+
+// ResourcePoolStats is a snapshot of the scheduling state of a resource pool.
+type ResourcePoolStats struct {
+	QueuedJobs    int
+	ScheduledJobs int
+	// OldestPendingSeconds is how long the oldest pending allocation has waited, 0 if none are.
+	OldestPendingSeconds float64
+	Allocations          map[model.AllocationState]int
+}
+
+// EnableExporter starts exporting trial metrics and the resource pool stats returned by
+// poolStats, which is called on every scrape.
+func EnableExporter(
+	conf config.PrometheusExporterConfig,
+	poolStats func() (map[string]ResourcePoolStats, error),
+) {
+	trialMetrics.enable(conf.MaxTrialSeries)
+	ExporterMetrics.MustRegister(trialMetrics)
+	ExporterMetrics.MustRegister(trialMetricSeriesDropped)
+	ExporterMetrics.MustRegister(&resourcePoolCollector{stats: poolStats})
+}
+
+// AssociateTrial starts exporting the metrics a trial reports, labeled with its experiment,
+// workspace and project.
+func AssociateTrial(trialID, experimentID int, workspace, project string) {
+	trialMetrics.associate(trialID, []string{
+		strconv.Itoa(experimentID), strconv.Itoa(trialID), workspace, project,
+	})
+}
+
+// DisassociateTrial stops exporting the metrics of a trial.
+func DisassociateTrial(trialID int) {
+	trialMetrics.disassociate(trialID)
+}
+
+// SetTrialMetrics records the latest numeric values of the metrics reported by an associated
+// trial. Non-numeric values are ignored.
+func SetTrialMetrics(trialID int, group model.MetricGroup, metrics map[string]interface{}) {
+	trialMetrics.set(trialID, string(group), metrics)
+}
+
+type trialMetricKey struct {
+	group string
+	name  string
+}
+
+type trialMetricSeries struct {
+	labels []string
+	values map[trialMetricKey]float64
+}
+
+type trialMetricsCollector struct {
+	mu        sync.Mutex
+	enabled   bool
+	maxSeries int
+	series    int
+	trials    map[int]*trialMetricSeries
+}
+
+func newTrialMetricsCollector() *trialMetricsCollector {
+	return &trialMetricsCollector{trials: map[int]*trialMetricSeries{}}
+}
+
+func (c *trialMetricsCollector) enable(maxSeries int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = true
+	c.maxSeries = maxSeries
+}
+
+func (c *trialMetricsCollector) associate(trialID int, labels []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return
+	}
+	if _, ok := c.trials[trialID]; ok {
+		return
+	}
+	c.trials[trialID] = &trialMetricSeries{
+		labels: labels,
+		values: map[trialMetricKey]float64{},
+	}
+}
+
+func (c *trialMetricsCollector) disassociate(trialID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.trials[trialID]; ok {
+		c.series -= len(t.values)
+		delete(c.trials, trialID)
+	}
+}
+
+func (c *trialMetricsCollector) set(trialID int, group string, metrics map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.trials[trialID]
+	if !ok {
+		return
+	}
+	for name, v := range metrics {
+		f, ok := v.(float64)
+		if !ok {
+			continue
+		}
+		key := trialMetricKey{group: group, name: name}
+		if _, ok := t.values[key]; !ok {
+			if c.series >= c.maxSeries {
+				trialMetricSeriesDropped.Inc()
+				continue
+			}
+			c.series++
+		}
+		t.values[key] = f
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *trialMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- trialMetricDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *trialMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.trials {
+		for key, v := range t.values {
+			labels := append(append([]string{}, t.labels...), key.group, key.name)
+			ch <- prometheus.MustNewConstMetric(trialMetricDesc, prometheus.GaugeValue, v, labels...)
+		}
+	}
+}
+
+type resourcePoolCollector struct {
+	stats func() (map[string]ResourcePoolStats, error)
+}
+
+// Describe implements prometheus.Collector.
+func (c *resourcePoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolQueuedJobsDesc
+	ch <- poolScheduledJobsDesc
+	ch <- poolOldestPendingDesc
+	ch <- poolAllocationsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *resourcePoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.stats()
+	if err != nil {
+		log.WithError(err).Warn("failed to get resource pool stats for prometheus")
+		ch <- prometheus.NewInvalidMetric(poolQueuedJobsDesc, err)
+		return
+	}
+
+	for pool, s := range stats {
+		ch <- prometheus.MustNewConstMetric(
+			poolQueuedJobsDesc, prometheus.GaugeValue, float64(s.QueuedJobs), pool)
+		ch <- prometheus.MustNewConstMetric(
+			poolScheduledJobsDesc, prometheus.GaugeValue, float64(s.ScheduledJobs), pool)
+		ch <- prometheus.MustNewConstMetric(
+			poolOldestPendingDesc, prometheus.GaugeValue, s.OldestPendingSeconds, pool)
+		for state, n := range s.Allocations {
+			ch <- prometheus.MustNewConstMetric(
+				poolAllocationsDesc, prometheus.GaugeValue, float64(n), pool, string(state))
+		}
+	}
+}
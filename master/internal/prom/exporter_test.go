@@ -0,0 +1,89 @@
+package prom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestTrialMetricsCollector(t *testing.T) {
+	c := newTrialMetricsCollector()
+
+	// Nothing is recorded until the exporter is enabled.
+	c.associate(1, []string{"10", "1", "ws", "proj"})
+	c.set(1, "training", map[string]interface{}{"loss": 0.5})
+	require.Equal(t, 0, testutil.CollectAndCount(c))
+
+	c.enable(3)
+	c.associate(1, []string{"10", "1", "ws", "proj"})
+	c.associate(2, []string{"10", "2", "ws", "proj"})
+	c.set(1, "training", map[string]interface{}{"loss": 0.5, "note": "not a number"})
+	c.set(1, "training", map[string]interface{}{"loss": 0.25})
+	c.set(1, "validation", map[string]interface{}{"loss": 0.75})
+	c.set(3, "training", map[string]interface{}{"loss": 1.0})
+
+	expected := `
+# HELP det_trial_latest_metric the latest value reported for a metric of an active trial
+# TYPE det_trial_latest_metric gauge
+det_trial_latest_metric{experiment_id="10",metric_group="training",metric_name="loss",project="proj",trial_id="1",workspace="ws"} 0.25
+det_trial_latest_metric{experiment_id="10",metric_group="validation",metric_name="loss",project="proj",trial_id="1",workspace="ws"} 0.75
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+
+	// Past the cap, new series are dropped until a trial holding series exits.
+	dropped := testutil.ToFloat64(trialMetricSeriesDropped)
+	c.set(2, "training", map[string]interface{}{"loss": 2.0, "accuracy": 0.1})
+	require.Equal(t, 3, testutil.CollectAndCount(c))
+	require.Equal(t, dropped+1, testutil.ToFloat64(trialMetricSeriesDropped))
+
+	c.disassociate(1)
+	c.set(2, "training", map[string]interface{}{"loss": 2.0, "accuracy": 0.1})
+	require.Equal(t, 2, testutil.CollectAndCount(c))
+}
+
+func TestResourcePoolCollector(t *testing.T) {
+	c := &resourcePoolCollector{stats: func() (map[string]ResourcePoolStats, error) {
+		return map[string]ResourcePoolStats{
+			"default": {
+				QueuedJobs:           2,
+				ScheduledJobs:        1,
+				OldestPendingSeconds: 30,
+				Allocations: map[model.AllocationState]int{
+					model.AllocationStatePending: 2,
+					model.AllocationStateRunning: 1,
+				},
+			},
+		}, nil
+	}}
+
+	expected := `
+# HELP det_resource_pool_allocations the number of allocations in a resource pool by allocation state
+# TYPE det_resource_pool_allocations gauge
+det_resource_pool_allocations{resource_pool="default",state="PENDING"} 2
+det_resource_pool_allocations{resource_pool="default",state="RUNNING"} 1
+# HELP det_resource_pool_oldest_pending_allocation_seconds how long the oldest allocation still pending in a resource pool has been waiting
+# TYPE det_resource_pool_oldest_pending_allocation_seconds gauge
+det_resource_pool_oldest_pending_allocation_seconds{resource_pool="default"} 30
+# HELP det_resource_pool_queued_jobs the number of jobs queued in a resource pool
+# TYPE det_resource_pool_queued_jobs gauge
+det_resource_pool_queued_jobs{resource_pool="default"} 2
+# HELP det_resource_pool_scheduled_jobs the number of jobs scheduled in a resource pool
+# TYPE det_resource_pool_scheduled_jobs gauge
+det_resource_pool_scheduled_jobs{resource_pool="default"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+
+	failing := &resourcePoolCollector{stats: func() (map[string]ResourcePoolStats, error) {
+		return nil, errors.New("rm unavailable")
+	}}
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(failing)
+	_, err := reg.Gather()
+	require.Error(t, err)
+}
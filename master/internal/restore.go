@@ -109,18 +109,19 @@ func (m *Master) restoreExperiment(expModel *model.Experiment) error {
 	taskContainerDefaults, err := m.rm.TaskContainerDefaults(
 		m.system,
 		poolName,
-		m.config.TaskContainerDefaults,
+		m.config.Load().TaskContainerDefaults,
 	)
 	if err != nil {
 		return fmt.Errorf("error getting TaskContainerDefaults: %w", err)
 	}
-	taskSpec := *m.taskSpec
+	taskSpec := *m.taskSpec.Load()
 	taskSpec.TaskContainerDefaults = taskContainerDefaults
 	owner, err := user.ByUsername(context.TODO(), expModel.Username)
 	if err != nil {
 		return errors.Wrapf(err, "retrieving full user on restart")
 	}
 	taskSpec.Owner = owner
+	taskSpec.WorkspaceID = workspaceID
 
 	log.WithField("experiment", expModel.ID).Debug("restoring experiment")
 	snapshot, err := m.retrieveExperimentSnapshot(expModel)
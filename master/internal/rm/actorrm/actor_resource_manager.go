@@ -323,6 +323,14 @@ func (r ResourceManager) TaskContainerDefaults(
 	return fallbackConfig, nil
 }
 
+// UpdateResourcePoolConfigs applies reloaded resource pool settings to the RM.
+func (r *ResourceManager) UpdateResourcePoolConfigs(
+	ctx actor.Messenger,
+	msg sproto.UpdateResourcePoolConfigs,
+) error {
+	return r.Ask(ctx, msg, nil)
+}
+
 func agentAddr(agentID string) actor.Address {
 	return sproto.AgentsAddr.Child(agentID)
 }
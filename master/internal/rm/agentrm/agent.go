@@ -29,6 +29,10 @@ import (
 	proto "github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
+// defaultMigrationTimeout is the time allocations have to migrate off of a draining agent before
+// they are killed.
+const defaultMigrationTimeout = 10 * time.Minute
+
 type (
 	agent struct {
 		address          string
@@ -44,6 +48,10 @@ type (
 		// and not be copied to agents.
 		maxZeroSlotContainers int
 		agentReconnectWait    time.Duration
+		// prefetchImages are the images of the resource pool the agent prefetches.
+		prefetchImages []string
+		// cachedImages are the images last reported cached by the agent.
+		cachedImages []string
 		// awaitingReconnect et al contain reconnect related state. The pattern for
 		// reconnecting agents is
 		//  * They have a small window to reconnect.
@@ -76,6 +84,10 @@ type (
 	}
 
 	reconnectTimeout struct{}
+	// migrationTimeout kills the allocations still migrating off of the agent by the deadline.
+	migrationTimeout struct {
+		deadline time.Time
+	}
 
 	// getAgentState response is agent.agentState.
 	getAgentState struct{}
@@ -94,6 +106,8 @@ type (
 	allocateFreeDevices struct {
 		slots       int
 		containerID cproto.ID
+		cpus        float64
+		memory      int64
 	}
 	// allocateFreeDevicesResponse is a response to allocateFreeDevices.
 	allocateFreeDevicesResponse struct {
@@ -125,6 +139,9 @@ func (a *agent) receive(ctx *actor.Context, msg interface{}) error {
 			// Ensure RP is aware of the agent.
 			ctx.Ask(a.resourcePool, sproto.AddAgent{Agent: ctx.Self()}).Get()
 			a.socketDisconnected(ctx)
+			if deadline := a.agentState.migrationDeadline; deadline != nil {
+				actors.NotifyAfter(ctx, time.Until(*deadline), migrationTimeout{deadline: *deadline})
+			}
 		}
 		a.slots, _ = ctx.ActorOf("slots", &slots{})
 	case model.AgentSummary:
@@ -145,14 +162,12 @@ func (a *agent) receive(ctx *actor.Context, msg interface{}) error {
 
 		a.adjustAgentIPAddrIfRunningDevClusterOnHpcUsingAnSSHTunnel(ctx, msg)
 
-		var masterSetAgentOptions aproto.AgentMessage
+		optsCopy := *a.opts
+		optsCopy.PrefetchImages = a.prefetchImages
 		if a.awaitingReconnect {
-			optsCopy := *a.opts
 			optsCopy.ContainersToReattach = a.gatherContainersToReattach(ctx)
-			masterSetAgentOptions = aproto.AgentMessage{MasterSetAgentOptions: &optsCopy}
-		} else {
-			masterSetAgentOptions = aproto.AgentMessage{MasterSetAgentOptions: a.opts}
 		}
+		masterSetAgentOptions := aproto.AgentMessage{MasterSetAgentOptions: &optsCopy}
 
 		if a.awaitingRestore {
 			a.awaitingRestore = false
@@ -272,6 +287,7 @@ func (a *agent) receive(ctx *actor.Context, msg interface{}) error {
 		}
 
 		a.agentState.enable(ctx)
+		a.setMigrationDeadline(ctx, nil)
 		a.agentState.patchAllSlotsState(ctx, patchAllSlotsState{
 			enabled: &a.agentState.enabled,
 			drain:   &a.agentState.draining,
@@ -289,15 +305,18 @@ func (a *agent) receive(ctx *actor.Context, msg interface{}) error {
 			return nil
 		}
 
-		// Mark current agent as disabled with RP.
-		a.agentState.disable(ctx, msg.Drain)
+		// Mark current agent as disabled with RP. Migrating allocations off of it drains it.
+		a.agentState.disable(ctx, msg.Drain || msg.Migrate)
 		// Update individual slot state.
 		a.agentState.patchAllSlotsState(ctx, patchAllSlotsState{
 			enabled: &a.agentState.enabled,
 			drain:   &a.agentState.draining,
 		})
-		// Kill both slotted and zero-slot tasks, unless draining.
-		if !msg.Drain {
+		// Kill both slotted and zero-slot tasks, unless draining or migrating them.
+		switch {
+		case msg.Migrate:
+			a.migrate(ctx, msg.MigrationTimeoutSeconds)
+		case !msg.Drain:
 			for _, aID := range a.agentState.containerAllocation {
 				rmevents.Publish(aID, &sproto.ReleaseResources{
 					Reason:    "agent disabled",
@@ -343,6 +362,24 @@ func (a *agent) receive(ctx *actor.Context, msg interface{}) error {
 		if a.awaitingReconnect {
 			return errors.New("agent failed to reconnect by deadline")
 		}
+	case migrationTimeout:
+		if !a.started || a.agentState.migrationDeadline == nil ||
+			!a.agentState.migrationDeadline.Equal(msg.deadline) {
+			return nil
+		}
+		if a.awaitingReconnect {
+			// The allocations on the agent are only known again once it reconnects.
+			a.bufferForRecovery(ctx, msg)
+			return nil
+		}
+		a.setMigrationDeadline(ctx, nil)
+		for _, aID := range a.allocationIDs() {
+			ctx.Log().Infof("killing allocation %s, which did not migrate by the deadline", aID)
+			rmevents.Publish(aID, &sproto.ReleaseResources{
+				Reason:    "agent migration timed out",
+				ForceKill: true,
+			})
+		}
 	case getAgentState:
 		if !a.started {
 			ctx.Respond(errors.New("agent state is not available: agent not started"))
@@ -379,6 +416,7 @@ func (a *agent) receive(ctx *actor.Context, msg interface{}) error {
 		if err != nil {
 			ctx.Respond(err)
 		} else {
+			a.agentState.reserveHostResources(msg.containerID, msg.cpus, msg.memory)
 			ctx.Respond(allocateFreeDevicesResponse{
 				devices: devices,
 			})
@@ -515,6 +553,9 @@ func (a *agent) handleIncomingWSMessage(ctx *actor.Context, msg aproto.MasterMes
 				ctx.Self().Stop()
 				return
 			}
+			a.agentState.labels = msg.AgentStarted.Labels
+			a.agentState.cpus = msg.AgentStarted.CPUs
+			a.agentState.memory = msg.AgentStarted.Memory
 		} else {
 			a.agentStarted(ctx, msg.AgentStarted)
 		}
@@ -558,6 +599,16 @@ func (a *agent) handleIncomingWSMessage(ctx *actor.Context, msg aproto.MasterMes
 				log.Errorf("error recording task stats %s", err)
 			}
 		}
+	case msg.ImagesCached != nil:
+		a.cachedImages = msg.ImagesCached.Images
+	case msg.DevicesHealthChanged != nil:
+		if a.agentState == nil {
+			log.Warn("received DevicesHealthChanged before the agent started")
+			return
+		}
+		if a.agentState.devicesHealthChanged(ctx, msg.DevicesHealthChanged) {
+			ctx.Tell(a.resourcePool, sproto.UpdateAgent{Agent: ctx.Self()})
+		}
 
 	default:
 		check.Panic(errors.Errorf("error parsing incoming message"))
@@ -611,6 +662,66 @@ func (a *agent) containerStateChanged(ctx *actor.Context, sc aproto.ContainerSta
 
 	rmevents.Publish(aID, sproto.FromContainerStateChanged(sc))
 	a.agentState.containerStateChanged(ctx, sc)
+
+	if sc.Container.State == cproto.Terminated && a.readyForMaintenance() {
+		a.setMigrationDeadline(ctx, nil)
+		ctx.Log().Info("agent is drained and ready for maintenance")
+	}
+}
+
+// migrate asks the allocations on the agent to checkpoint and exit, so that they are rescheduled
+// on other agents, and kills those that are still running after the timeout. Allocations that
+// don't support preemption keep running until they are killed.
+func (a *agent) migrate(ctx *actor.Context, timeoutSeconds int32) {
+	timeout := defaultMigrationTimeout
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+
+	aIDs := a.allocationIDs()
+	if len(aIDs) == 0 {
+		ctx.Log().Info("agent is drained and ready for maintenance")
+		return
+	}
+
+	// The deadline is kept to the precision of the database, so that it matches once restored.
+	deadline := time.Now().Add(timeout).Truncate(time.Microsecond)
+	a.setMigrationDeadline(ctx, &deadline)
+	for _, aID := range aIDs {
+		ctx.Log().Infof("migrating allocation %s off of agent, deadline %s", aID, deadline)
+		rmevents.Publish(aID, &sproto.ReleaseResources{
+			Reason:          "agent draining for maintenance",
+			ForcePreemption: true,
+		})
+	}
+	actors.NotifyAfter(ctx, timeout, migrationTimeout{deadline: deadline})
+}
+
+// setMigrationDeadline sets the migration deadline and persists it, so that a restored agent still
+// kills the allocations that did not migrate by the deadline.
+func (a *agent) setMigrationDeadline(ctx *actor.Context, deadline *time.Time) {
+	if a.agentState.migrationDeadline == nil && deadline == nil {
+		return
+	}
+	a.agentState.migrationDeadline = deadline
+	if err := a.agentState.persist(); err != nil {
+		ctx.Log().WithError(err).Warnf("setMigrationDeadline persist failure")
+	}
+}
+
+// allocationIDs returns the allocations with containers on the agent.
+func (a *agent) allocationIDs() []model.AllocationID {
+	aIDs := map[model.AllocationID]bool{}
+	for _, aID := range a.agentState.containerAllocation {
+		aIDs[aID] = true
+	}
+	return maps.Keys(aIDs)
+}
+
+// readyForMaintenance returns whether the agent is draining and no containers remain on it.
+func (a *agent) readyForMaintenance() bool {
+	return a.agentState != nil && !a.awaitingReconnect && a.agentState.draining &&
+		len(a.agentState.containerAllocation) == 0
 }
 
 func (a *agent) summarize(ctx *actor.Context) model.AgentSummary {
@@ -626,6 +737,7 @@ func (a *agent) summarize(ctx *actor.Context) model.AgentSummary {
 		Draining:      false,
 		NumContainers: 0,
 		Version:       a.version,
+		CachedImages:  a.cachedImages,
 	}
 
 	if a.agentState != nil {
@@ -633,6 +745,9 @@ func (a *agent) summarize(ctx *actor.Context) model.AgentSummary {
 		result.Enabled = a.agentState.enabled
 		result.Draining = a.agentState.draining
 		result.NumContainers = len(a.agentState.containerAllocation)
+		result.Labels = a.agentState.labels
+		result.ReadyForMaintenance = a.readyForMaintenance()
+		result.MigrationDeadline = a.agentState.migrationDeadline
 	}
 
 	return result
@@ -0,0 +1,80 @@
+//go:build integration
+// +build integration
+
+package agentrm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rm/rmevents"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+type migrateAgent struct{}
+
+func TestAgentMigrate(t *testing.T) {
+	pgDB := db.MustResolveTestPostgres(t)
+	db.MustMigrateTestPostgres(t, pgDB, "file://../../../static/migrations")
+
+	system := actor.NewSystem(t.Name())
+	a := &agent{
+		started:    true,
+		agentState: newFakeAgentState(t, system, t.Name(), 1, 0, 100, 0),
+	}
+	aID := model.AllocationID(t.Name())
+	a.agentState.containerAllocation["c1"] = aID
+	sub := rmevents.Subscribe(aID)
+	defer sub.Close()
+
+	ref, _ := system.ActorOf(actor.Addr("migrate-"+t.Name()), actor.ActorFunc(
+		func(ctx *actor.Context) error {
+			switch msg := ctx.Message().(type) {
+			case migrateAgent:
+				a.migrate(ctx, 3600)
+			case migrationTimeout:
+				return a.receive(ctx, msg)
+			}
+			return nil
+		}))
+
+	system.Ask(ref, migrateAgent{}).Get()
+	release, ok := sub.Get().(*sproto.ReleaseResources)
+	assert.Assert(t, ok)
+	assert.Assert(t, release.ForcePreemption)
+	assert.Assert(t, !release.ForceKill)
+
+	deadline := a.agentState.migrationDeadline
+	assert.Assert(t, deadline != nil)
+	assert.Assert(t, time.Until(*deadline) > 59*time.Minute)
+
+	// The deadline survives a master restart.
+	var snapshot agentSnapshot
+	assert.NilError(t, db.Bun().NewSelect().Model(&snapshot).
+		Where("agent_id = ?", a.agentState.agentID()).
+		Scan(context.Background()))
+	assert.Assert(t, snapshot.MigrationDeadline != nil)
+	assert.Assert(t, snapshot.MigrationDeadline.Equal(*deadline))
+
+	// A timeout for an earlier migration doesn't kill anything.
+	system.Ask(ref, migrationTimeout{deadline: deadline.Add(-time.Minute)}).Get()
+	assert.Equal(t, sub.Len(), 0)
+	assert.Assert(t, a.agentState.migrationDeadline != nil)
+
+	system.Ask(ref, migrationTimeout{deadline: *deadline}).Get()
+	release, ok = sub.Get().(*sproto.ReleaseResources)
+	assert.Assert(t, ok)
+	assert.Assert(t, release.ForceKill)
+	assert.Assert(t, a.agentState.migrationDeadline == nil)
+
+	assert.NilError(t, db.Bun().NewSelect().Model(&snapshot).
+		Where("agent_id = ?", a.agentState.agentID()).
+		Scan(context.Background()))
+	assert.Assert(t, snapshot.MigrationDeadline == nil)
+}
@@ -1,6 +1,8 @@
 package agentrm
 
 import (
+	"time"
+
 	"github.com/uptrace/bun"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -15,6 +17,9 @@ type slotData struct {
 	Device      device.Device
 	UserEnabled bool
 	ContainerID *cproto.ID
+	// Unhealthy and UnhealthyReason record the device's last reported health.
+	Unhealthy       bool
+	UnhealthyReason string
 }
 
 // agentID is the agent id type.
@@ -34,6 +39,7 @@ type agentSnapshot struct {
 	MaxZeroSlotContainers int         `bun:"max_zero_slot_containers"`
 	Slots                 []slotData  `bun:"slots"`
 	Containers            []cproto.ID `bun:"containers"`
+	MigrationDeadline     *time.Time  `bun:"migration_deadline"`
 }
 
 // containerSnapshot is a database representation of `containerResources`.
@@ -45,6 +51,9 @@ type containerSnapshot struct {
 	ID         cproto.ID          `bun:"container_id" json:"id"`
 	State      cproto.State       `bun:"state"        json:"state"`
 	Devices    []device.Device    `bun:"devices"      json:"devices"`
+	// CPUs and Memory are the share of the agent's host reserved for the container.
+	CPUs   float64 `bun:"cpus"   json:"cpus"`
+	Memory int64   `bun:"memory" json:"memory"`
 
 	// Relations
 	ResourcesWithState taskmodel.ResourcesWithState `bun:"rel:belongs-to,join:resource_id=resource_id"`
@@ -379,6 +379,9 @@ func (a *agentResourceManager) Receive(ctx *actor.Context) error {
 	case taskContainerDefaults:
 		ctx.Respond(a.getTaskContainerDefaults(msg))
 
+	case sproto.UpdateResourcePoolConfigs:
+		a.updateResourcePoolConfigs(ctx, msg)
+
 	case sproto.GetExternalJobs:
 		ctx.Respond(rmerrors.ErrNotSupported)
 
@@ -388,6 +391,28 @@ func (a *agentResourceManager) Receive(ctx *actor.Context) error {
 	return nil
 }
 
+func (a *agentResourceManager) updateResourcePoolConfigs(
+	ctx *actor.Context, msg sproto.UpdateResourcePoolConfigs,
+) {
+	if msg.DefaultScheduler != nil {
+		rmConfig := *a.config
+		rmConfig.Scheduler = msg.DefaultScheduler
+		a.config = &rmConfig
+	}
+	a.poolsConfig = msg.ResourcePools
+
+	for _, pool := range a.poolsConfig {
+		ref, ok := a.pools[pool.PoolName]
+		if !ok {
+			continue
+		}
+		if pool.Scheduler == nil {
+			pool.Scheduler = a.config.Scheduler
+		}
+		ctx.Tell(ref, updateResourcePoolConfig{config: pool})
+	}
+}
+
 func (a *agentResourceManager) getTaskContainerDefaults(
 	msg taskContainerDefaults,
 ) model.TaskContainerDefaultsConfig {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -26,17 +27,27 @@ type slotEnabled struct {
 	deviceAdded  bool
 	agentEnabled bool
 	userEnabled  bool
-	draining     bool
+	// healthy is false while the agent reports the slot's device as unhealthy.
+	healthy  bool
+	draining bool
 }
 
 func (s slotEnabled) enabled() bool {
-	return s.agentEnabled && s.userEnabled
+	return s.agentEnabled && s.userEnabled && s.healthy
 }
 
 type slot struct {
 	device      device.Device
 	enabled     slotEnabled
 	containerID *cproto.ID
+	// unhealthyReason is why the agent last reported the device unhealthy, if it did.
+	unhealthyReason string
+}
+
+// hostReservation is the share of the agent's host CPUs and memory reserved for a container.
+type hostReservation struct {
+	cpus   float64
+	memory int64
 }
 
 // agentState holds the scheduler state for an agent. The implementation of agent-related operations
@@ -51,10 +62,20 @@ type agentState struct {
 	uuid             uuid.UUID
 
 	maxZeroSlotContainers int
+	// labels are the key/value labels the agent advertised when it started.
+	labels map[string]string
+	// cpus and memory are the capacity of the agent's host, or 0 if the agent didn't report it.
+	cpus   int
+	memory int64
 
 	slotStates          map[device.ID]*slot
 	containerAllocation map[cproto.ID]model.AllocationID
 	containerState      map[cproto.ID]*cproto.Container
+	hostReservations    map[cproto.ID]hostReservation
+
+	// migrationDeadline is when the allocations still migrating off of the agent, while it drains
+	// for maintenance, are killed; nil unless allocations are migrating.
+	migrationDeadline *time.Time
 }
 
 // newAgentState returns a new agent empty agent state backed by the handler.
@@ -181,9 +202,31 @@ func (a *agentState) allocateFreeDevices(slots int, cid cproto.ID) ([]device.Dev
 	return devices, nil
 }
 
+// reserveHostResources reserves a share of the host's CPUs and memory for the container.
+func (a *agentState) reserveHostResources(cid cproto.ID, cpus float64, memory int64) {
+	if cpus == 0 && memory == 0 {
+		return
+	}
+	if a.hostReservations == nil {
+		a.hostReservations = make(map[cproto.ID]hostReservation)
+	}
+	a.hostReservations[cid] = hostReservation{cpus: cpus, memory: memory}
+}
+
+// availableHostResources returns the host's CPUs and memory that aren't reserved by containers.
+func (a *agentState) availableHostResources() (cpus float64, memory int64) {
+	cpus, memory = float64(a.cpus), a.memory
+	for _, r := range a.hostReservations {
+		cpus -= r.cpus
+		memory -= r.memory
+	}
+	return cpus, memory
+}
+
 // deallocateContainer deallocates containers.
 func (a *agentState) deallocateContainer(id cproto.ID) {
 	delete(a.containerState, id)
+	delete(a.hostReservations, id)
 	for d, cid := range a.Devices {
 		if cid != nil && *cid == id {
 			a.Devices[d] = nil
@@ -197,8 +240,13 @@ func (a *agentState) deepCopy() *agentState {
 		Handler:               a.Handler,
 		Devices:               maps.Clone(a.Devices),
 		maxZeroSlotContainers: a.maxZeroSlotContainers,
+		labels:                maps.Clone(a.labels),
+		cpus:                  a.cpus,
+		memory:                a.memory,
+		hostReservations:      maps.Clone(a.hostReservations),
 		enabled:               a.enabled,
 		draining:              a.draining,
+		migrationDeadline:     a.migrationDeadline,
 		containerState:        maps.Clone(a.containerState),
 		// TODO(ilia): Deepcopy of `slotStates` may be necessary one day.
 		slotStates: a.slotStates,
@@ -238,10 +286,13 @@ func (a *agentState) removeDevice(ctx *actor.Context, device device.Device) {
 // agentStarted initializes slots from AgentStarted.Devices.
 func (a *agentState) agentStarted(ctx *actor.Context, agentStarted *aproto.AgentStarted) {
 	msg := agentStarted
+	a.labels = msg.Labels
+	a.cpus, a.memory = msg.CPUs, msg.Memory
 	for _, d := range msg.Devices {
 		enabled := slotEnabled{
 			agentEnabled: true,
 			userEnabled:  true,
+			healthy:      true,
 		}
 		a.slotStates[d.ID] = &slot{enabled: enabled, device: d}
 		a.updateSlotDeviceView(ctx, d.ID)
@@ -252,6 +303,37 @@ func (a *agentState) agentStarted(ctx *actor.Context, agentStarted *aproto.Agent
 	}
 }
 
+// devicesHealthChanged disables the slots of the devices the agent reports as unhealthy and
+// re-enables them once they are healthy again. It returns whether any slot changed.
+func (a *agentState) devicesHealthChanged(
+	ctx *actor.Context, msg *aproto.DevicesHealthChanged,
+) bool {
+	changed := false
+	for _, h := range msg.Devices {
+		s, ok := a.slotStates[h.Device.ID]
+		if !ok {
+			ctx.Log().Warnf("bad devicesHealthChanged on device: %d (%s)", h.Device.ID, a.string())
+			continue
+		}
+		if s.enabled.healthy == h.Healthy && (h.Healthy || s.unhealthyReason == h.Reason) {
+			continue
+		}
+
+		if h.Healthy {
+			ctx.Log().Infof("enabling healthy slot: %s on %s", s.device.String(), a.string())
+			s.unhealthyReason = ""
+		} else {
+			ctx.Log().Warnf("disabling unhealthy slot: %s on %s: %s",
+				s.device.String(), a.string(), h.Reason)
+			s.unhealthyReason = h.Reason
+		}
+		s.enabled.healthy = h.Healthy
+		a.updateSlotDeviceView(ctx, h.Device.ID)
+		changed = true
+	}
+	return changed
+}
+
 func (a *agentState) checkAgentStartedDevicesMatch(
 	ctx *actor.Context, agentStarted *aproto.AgentStarted,
 ) error {
@@ -374,11 +456,12 @@ func (a *agentState) getSlotSummary(deviceID device.ID) model.SlotSummary {
 	}
 
 	return model.SlotSummary{
-		ID:        strconv.Itoa(int(s.device.ID)),
-		Device:    s.device,
-		Enabled:   s.enabled.enabled(),
-		Container: container,
-		Draining:  s.enabled.draining,
+		ID:              strconv.Itoa(int(s.device.ID)),
+		Device:          s.device,
+		Enabled:         s.enabled.enabled(),
+		Container:       container,
+		Draining:        s.enabled.draining,
+		UnhealthyReason: s.unhealthyReason,
 	}
 }
 
@@ -404,8 +487,12 @@ func (a *agentState) updateSlotDeviceView(ctx *actor.Context, deviceID device.ID
 		// On `PostStop`, draining will be already set to false, and we'll kill the container
 		// whether we have the device or not.
 		if !s.enabled.draining && s.containerID != nil {
+			reason := "slot disabled"
+			if !s.enabled.healthy && s.unhealthyReason != "" {
+				reason = fmt.Sprintf("slot unhealthy: %s", s.unhealthyReason)
+			}
 			rmevents.Publish(a.containerAllocation[*s.containerID], &sproto.ReleaseResources{
-				Reason:    "slot disabled",
+				Reason:    reason,
 				ForceKill: true,
 			})
 		}
@@ -461,9 +548,11 @@ func (a *agentState) snapshot() *agentSnapshot {
 	slots := make([]slotData, 0, len(a.slotStates))
 	for _, slotState := range a.slotStates {
 		slots = append(slots, slotData{
-			Device:      slotState.device,
-			UserEnabled: slotState.enabled.userEnabled,
-			ContainerID: slotState.containerID,
+			Device:          slotState.device,
+			UserEnabled:     slotState.enabled.userEnabled,
+			ContainerID:     slotState.containerID,
+			Unhealthy:       !slotState.enabled.healthy,
+			UnhealthyReason: slotState.unhealthyReason,
 		})
 	}
 
@@ -480,6 +569,7 @@ func (a *agentState) snapshot() *agentSnapshot {
 		MaxZeroSlotContainers: a.maxZeroSlotContainers,
 		Slots:                 slots,
 		Containers:            containerIds,
+		MigrationDeadline:     a.migrationDeadline,
 	}
 
 	return &s
@@ -599,15 +689,22 @@ func newAgentStateFromSnapshot(as agentSnapshot) (*agentState, error) {
 	devices := make(map[device.Device]*cproto.ID)
 
 	for _, sd := range as.Slots {
+		// A free unhealthy slot is left out of the devices, so that nothing is scheduled on it.
+		deviceAdded := !sd.Unhealthy || sd.ContainerID != nil
 		slotStates[sd.Device.ID] = &slot{
 			device:      sd.Device,
 			containerID: sd.ContainerID,
 			enabled: slotEnabled{
-				deviceAdded:  true,
+				deviceAdded:  deviceAdded,
 				agentEnabled: as.UserEnabled,
 				userEnabled:  as.UserEnabled,
+				healthy:      !sd.Unhealthy,
 				draining:     as.UserDraining,
 			},
+			unhealthyReason: sd.UnhealthyReason,
+		}
+		if !deviceAdded {
+			continue
 		}
 		if sd.ContainerID != nil {
 			devices[sd.Device] = sd.ContainerID
@@ -617,6 +714,7 @@ func newAgentStateFromSnapshot(as agentSnapshot) (*agentState, error) {
 	}
 
 	containerState := make(map[cproto.ID]*cproto.Container)
+	var hostReservations map[cproto.ID]hostReservation
 
 	if len(as.Containers) > 0 {
 		containerSnapshots := make([]containerSnapshot, 0, len(as.Containers))
@@ -630,6 +728,15 @@ func newAgentStateFromSnapshot(as agentSnapshot) (*agentState, error) {
 		for _, containerSnapshot := range containerSnapshots {
 			container := containerSnapshot.ToContainer()
 			containerState[container.ID] = &container
+			if containerSnapshot.CPUs != 0 || containerSnapshot.Memory != 0 {
+				if hostReservations == nil {
+					hostReservations = make(map[cproto.ID]hostReservation)
+				}
+				hostReservations[container.ID] = hostReservation{
+					cpus:   containerSnapshot.CPUs,
+					memory: containerSnapshot.Memory,
+				}
+			}
 		}
 	}
 
@@ -643,6 +750,8 @@ func newAgentStateFromSnapshot(as agentSnapshot) (*agentState, error) {
 		Devices:               devices,
 		containerAllocation:   make(map[cproto.ID]model.AllocationID),
 		containerState:        containerState,
+		hostReservations:      hostReservations,
+		migrationDeadline:     as.MigrationDeadline,
 	}
 
 	return &result, nil
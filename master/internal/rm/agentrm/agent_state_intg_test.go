@@ -0,0 +1,66 @@
+//go:build integration
+// +build integration
+
+package agentrm
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/task/taskmodel"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+func TestAgentStateRestoresHostReservations(t *testing.T) {
+	pgDB := db.MustResolveTestPostgres(t)
+	db.MustMigrateTestPostgres(t, pgDB, "file://../../../static/migrations")
+
+	user := db.RequireMockUser(t, pgDB)
+	task := db.RequireMockTask(t, pgDB, &user.ID)
+	alloc := db.RequireMockAllocation(t, pgDB, task.TaskID)
+
+	system := actor.NewSystem(t.Name())
+	state := newFakeAgentState(t, system, t.Name(), 0, 0, 100, 0)
+	state.cpus, state.memory = 8, 16<<30
+
+	cr := containerResources{
+		req: &sproto.AllocateRequest{
+			AllocationID: alloc.AllocationID,
+			FittingRequirements: sproto.FittingRequirements{
+				CPUs:   2,
+				Memory: 4 << 30,
+			},
+		},
+		agent:       state,
+		containerID: cproto.NewID(),
+	}
+	rs := taskmodel.NewResourcesState(cr, -1)
+	assert.NilError(t, rs.Persist())
+	assert.NilError(t, cr.persist())
+	state.containerState[cr.containerID] = &cproto.Container{ID: cr.containerID}
+	state.reserveHostResources(cr.containerID, 2, 4<<30)
+	assert.NilError(t, state.persist())
+
+	var snapshot agentSnapshot
+	assert.NilError(t, db.Bun().NewSelect().Model(&snapshot).
+		Where("agent_id = ?", state.agentID()).
+		Scan(context.Background()))
+	restored, err := newAgentStateFromSnapshot(snapshot)
+	assert.NilError(t, err)
+
+	// The agent reports its capacity again when it reconnects.
+	restored.cpus, restored.memory = state.cpus, state.memory
+	cpus, memory := restored.availableHostResources()
+	assert.Equal(t, cpus, 6.0)
+	assert.Equal(t, memory, int64(12<<30))
+
+	restored.deallocateContainer(cr.containerID)
+	cpus, memory = restored.availableHostResources()
+	assert.Equal(t, cpus, 8.0)
+	assert.Equal(t, memory, int64(16<<30))
+}
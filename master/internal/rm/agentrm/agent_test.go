@@ -0,0 +1,80 @@
+package agentrm
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestAgentReadyForMaintenance(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	a := &agent{agentState: newFakeAgentState(t, system, "agent", 4, 0, 100, 0)}
+	a.agentState.containerAllocation["c1"] = "a1"
+	a.agentState.containerAllocation["c2"] = "a1"
+	a.agentState.containerAllocation["c3"] = "a2"
+
+	aIDs := a.allocationIDs()
+	sort.Slice(aIDs, func(i, j int) bool { return aIDs[i] < aIDs[j] })
+	assert.DeepEqual(t, aIDs, []model.AllocationID{"a1", "a2"})
+
+	assert.Assert(t, !a.readyForMaintenance())
+	a.agentState.draining = true
+	assert.Assert(t, !a.readyForMaintenance())
+
+	for cID := range a.agentState.containerAllocation {
+		delete(a.agentState.containerAllocation, cID)
+	}
+	assert.Assert(t, a.readyForMaintenance())
+
+	// A disconnected agent is drained while it awaits reconnection, but isn't under maintenance.
+	a.awaitingReconnect = true
+	assert.Assert(t, !a.readyForMaintenance())
+}
+
+func TestAgentSnapshotKeepsSlotHealth(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	state := newFakeAgentState(t, system, "agent", 0, 0, 100, 0)
+	for i := 0; i < 2; i++ {
+		d := device.Device{ID: device.ID(i)}
+		state.slotStates[d.ID] = &slot{
+			device: d,
+			enabled: slotEnabled{
+				deviceAdded:  i == 0,
+				agentEnabled: true,
+				userEnabled:  true,
+				healthy:      i == 0,
+			},
+		}
+	}
+	state.slotStates[1].unhealthyReason = "xid 79"
+
+	restored, err := newAgentStateFromSnapshot(*state.snapshot())
+	assert.NilError(t, err)
+
+	assert.Assert(t, restored.slotStates[0].enabled.enabled())
+	assert.Assert(t, !restored.slotStates[1].enabled.enabled())
+	assert.Equal(t, restored.slotStates[1].unhealthyReason, "xid 79")
+
+	// Nothing can be scheduled on the unhealthy slot until the agent reports it healthy again.
+	_, ok := restored.Devices[device.Device{ID: 1}]
+	assert.Assert(t, !ok)
+	assert.Equal(t, restored.numEmptySlots(), 1)
+}
+
+func TestAgentSnapshotKeepsMigrationDeadline(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	state := newFakeAgentState(t, system, "agent", 0, 0, 100, 0)
+	deadline := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	state.migrationDeadline = &deadline
+
+	restored, err := newAgentStateFromSnapshot(*state.snapshot())
+	assert.NilError(t, err)
+	assert.Assert(t, restored.migrationDeadline != nil)
+	assert.Assert(t, restored.migrationDeadline.Equal(deadline))
+}
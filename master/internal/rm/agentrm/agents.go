@@ -174,6 +174,7 @@ func (a *agents) createAgentActor(
 		resourcePoolName:      resourcePool,
 		maxZeroSlotContainers: rpConfig.MaxZeroSlotContainers,
 		agentReconnectWait:    time.Duration(rpConfig.AgentReconnectWait),
+		prefetchImages:        rpConfig.PrefetchImages,
 		opts:                  opts,
 		agentState:            restoredAgentState,
 	})
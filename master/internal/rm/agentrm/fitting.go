@@ -120,7 +120,9 @@ func findDedicatedAgentFits(
 	// 2) Multi-agent tasks will receive all the slots on every agent they are scheduled on.
 	agentsByNumSlots := make(map[int][]*agentState)
 	for _, agent := range agentStates {
-		constraints := []HardConstraint{agentSlotUnusedSatisfied}
+		constraints := []HardConstraint{
+			agentSlotUnusedSatisfied, labelsSatisfied, hostResourcesSatisfied,
+		}
 		if isViable(req, agent, constraints...) {
 			agentsByNumSlots[agent.numEmptySlots()] = append(
 				agentsByNumSlots[agent.numEmptySlots()],
@@ -224,7 +226,10 @@ func findSharedAgentFit(
 ) *fittingState {
 	var candidates candidateList
 	for _, agent := range agents {
-		if !isViable(req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied) {
+		if !isViable(
+			req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied, labelsSatisfied,
+			hostResourcesSatisfied,
+		) {
 			continue
 		}
 
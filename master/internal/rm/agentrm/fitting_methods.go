@@ -23,6 +23,27 @@ func agentSlotUnusedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool
 	return agent.numUsedSlots() == 0
 }
 
+func labelsSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	for k, v := range req.FittingRequirements.RequiredLabels {
+		if l, ok := agent.labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+func hostResourcesSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	cpus, memory := agent.availableHostResources()
+	if req.FittingRequirements.CPUs > 0 && agent.cpus > 0 && req.FittingRequirements.CPUs > cpus {
+		return false
+	}
+	if req.FittingRequirements.Memory > 0 && agent.memory > 0 &&
+		req.FittingRequirements.Memory > memory {
+		return false
+	}
+	return true
+}
+
 // Soft Constraints
 
 // BestFit returns a float affinity score between 0 and 1 for the affinity between the task and
@@ -30,6 +51,10 @@ func agentSlotUnusedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool
 // offers the fewest slots. This method should be used when the cluster is dominated by multi-slot
 // applications.
 func BestFit(req *sproto.AllocateRequest, agent *agentState) float64 {
+	return preferredLabelsFit(req, agent, bestFit(req, agent))
+}
+
+func bestFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	switch {
 	case agent.numUsedSlots() != 0 || req.SlotsNeeded != 0:
 		return 1.0 / (1.0 + float64(agent.numEmptySlots()))
@@ -44,6 +69,10 @@ func BestFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 // the agent. This method attempts to allocate tasks to the agent that is least utilized. This
 // method should be used when the cluster is dominated by single-slot applications.
 func WorstFit(req *sproto.AllocateRequest, agent *agentState) float64 {
+	return preferredLabelsFit(req, agent, worstFit(req, agent))
+}
+
+func worstFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	switch {
 	case agent.numUsedSlots() != 0 || req.SlotsNeeded != 0:
 		return float64(agent.numEmptySlots()) / float64(agent.numSlots())
@@ -54,6 +83,23 @@ func WorstFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	}
 }
 
+// preferredLabelsFit scales the fit score of the agent by how many of the task's preferred labels
+// it has, so that agents with more of them score higher than agents with fewer regardless of fit.
+func preferredLabelsFit(req *sproto.AllocateRequest, agent *agentState, fit float64) float64 {
+	preferred := req.FittingRequirements.PreferredLabels
+	if len(preferred) == 0 {
+		return fit
+	}
+
+	matched := 0
+	for k, v := range preferred {
+		if l, ok := agent.labels[k]; ok && l == v {
+			matched++
+		}
+	}
+	return (float64(matched) + fit) / float64(len(preferred)+1)
+}
+
 // MakeFitFunction returns the corresponding fitting function.
 func MakeFitFunction(fittingPolicy string) func(
 	*sproto.AllocateRequest, *agentState) float64 {
@@ -472,6 +472,84 @@ func TestFindDedicatedAgentFits(t *testing.T) {
 	}
 }
 
+func TestFindFitsLabels(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	a100 := newFakeAgentState(t, system, "a100", 4, 0, 100, 0)
+	a100.labels = map[string]string{"gpu": "a100"}
+	h100 := newFakeAgentState(t, system, "h100", 4, 2, 100, 0)
+	h100.labels = map[string]string{"gpu": "h100", "nvlink": "true"}
+	h100Idle := newFakeAgentState(t, system, "h100-idle", 4, 0, 100, 0)
+	h100Idle.labels = map[string]string{"gpu": "h100"}
+	agents, _ := byHandler(a100, h100, h100Idle)
+
+	// Required labels exclude agents without them, even if they fit better.
+	req := &sproto.AllocateRequest{
+		AllocationID: "task1", SlotsNeeded: 1,
+		FittingRequirements: sproto.FittingRequirements{
+			RequiredLabels: map[string]string{"gpu": "a100"},
+		},
+	}
+	fits := findFits(req, agents, BestFit, false)
+	assert.Equal(t, len(fits), 1)
+	assert.Equal(t, fits[0].Agent, a100)
+
+	req.FittingRequirements.RequiredLabels = map[string]string{"gpu": "v100"}
+	assert.Equal(t, len(findFits(req, agents, BestFit, false)), 0)
+
+	// Preferred labels outweigh the fitting method.
+	req.FittingRequirements.RequiredLabels = map[string]string{"gpu": "h100"}
+	req.FittingRequirements.PreferredLabels = map[string]string{"nvlink": "true"}
+	fits = findFits(req, agents, WorstFit, false)
+	assert.Equal(t, len(fits), 1)
+	assert.Equal(t, fits[0].Agent, h100)
+
+	// Multi-agent fits are also restricted to agents with the required labels.
+	req.SlotsNeeded = 8
+	req.FittingRequirements.RequiredLabels = nil
+	req.FittingRequirements.PreferredLabels = nil
+	assert.Equal(t, len(findFits(req, agents, BestFit, false)), 2)
+	req.FittingRequirements.RequiredLabels = map[string]string{"gpu": "h100"}
+	assert.Equal(t, len(findFits(req, agents, BestFit, false)), 0)
+}
+
+func TestFindFitsHostResources(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	small := newFakeAgentState(t, system, "small", 4, 0, 100, 0)
+	small.cpus, small.memory = 8, 16<<30
+	large := newFakeAgentState(t, system, "large", 4, 0, 100, 0)
+	large.cpus, large.memory = 64, 256<<30
+	unknown := newFakeAgentState(t, system, "unknown", 4, 0, 100, 0)
+	unknown.labels = map[string]string{"host": "unknown"}
+	agents, _ := byHandler(small, large, unknown)
+
+	req := &sproto.AllocateRequest{
+		AllocationID: "task1", SlotsNeeded: 1,
+		FittingRequirements: sproto.FittingRequirements{CPUs: 16, Memory: 32 << 30},
+	}
+	fits := findFits(req, agents, BestFit, false)
+	assert.Equal(t, len(fits), 1)
+	assert.Assert(t, fits[0].Agent != small)
+
+	// Reservations count against the host's capacity.
+	delete(agents, unknown.Handler)
+	large.reserveHostResources("other", 56, 0)
+	assert.Equal(t, len(findFits(req, agents, BestFit, false)), 0)
+	large.deallocateContainer("other")
+	large.reserveHostResources("other", 0, 240<<30)
+	assert.Equal(t, len(findFits(req, agents, BestFit, false)), 0)
+	large.deallocateContainer("other")
+	fits = findFits(req, agents, BestFit, false)
+	assert.Equal(t, len(fits), 1)
+	assert.Equal(t, fits[0].Agent, large)
+
+	// Agents that don't report their capacity fit any request.
+	agents, _ = byHandler(small, unknown)
+	req.FittingRequirements.RequiredLabels = map[string]string{"host": "unknown"}
+	fits = findFits(req, agents, BestFit, false)
+	assert.Equal(t, len(fits), 1)
+	assert.Equal(t, fits[0].Agent, unknown)
+}
+
 func byHandler(
 	handlers ...*agentState,
 ) (map[*actor.Ref]*agentState, []*agentState) {
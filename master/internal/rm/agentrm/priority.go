@@ -158,7 +158,7 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 					log.Debugf(
 						"Not preempting tasks for task %s as it will be able to launch "+
 							"once already scheduled preemptions complete", prioritizedAllocation.Name)
-					addTaskToAgents(fits)
+					addTaskToAgents(prioritizedAllocation, fits)
 					continue
 				}
 
@@ -240,7 +240,7 @@ func (p priorityScheduler) trySchedulingTaskViaPreemption(
 				fittingMethod,
 				p.allowHeterogeneousFits,
 			); len(fits) > 0 {
-				addTaskToAgents(fits)
+				addTaskToAgents(allocationRequest, fits)
 				return true, localAgentsState, preemptedTasks
 			}
 		}
@@ -266,7 +266,7 @@ func (p priorityScheduler) trySchedulingPendingTasksInPriority(
 			unSuccessfulAllocations = append(unSuccessfulAllocations, allocationRequest)
 			continue
 		}
-		addTaskToAgents(fits)
+		addTaskToAgents(allocationRequest, fits)
 		successfulAllocations = append(successfulAllocations, allocationRequest)
 	}
 
@@ -317,11 +317,14 @@ func deepCopyAgents(agents map[*actor.Ref]*agentState) map[*actor.Ref]*agentStat
 	return copiedAgents
 }
 
-func addTaskToAgents(fits []*fittingState) {
+func addTaskToAgents(req *sproto.AllocateRequest, fits []*fittingState) {
 	for _, fit := range fits {
-		if _, err := fit.Agent.allocateFreeDevices(fit.Slots, cproto.NewID()); err != nil {
+		cid := cproto.NewID()
+		if _, err := fit.Agent.allocateFreeDevices(fit.Slots, cid); err != nil {
 			panic(errors.Wrap(err, "can't add task to agents"))
 		}
+		fit.Agent.reserveHostResources(
+			cid, req.FittingRequirements.CPUs, req.FittingRequirements.Memory)
 	}
 }
 
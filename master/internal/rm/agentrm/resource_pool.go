@@ -64,6 +64,12 @@ type getResourceSummary struct{}
 // schedulerTick periodically triggers the scheduler to act.
 type schedulerTick struct{}
 
+// updateResourcePoolConfig replaces the pool's config with a reloaded one. The manager only
+// sends it when the reload did not change the pool's scheduler type.
+type updateResourcePoolConfig struct {
+	config config.ResourcePoolConfig
+}
+
 // actionCoolDown is the rate limit for scheduler action.
 const actionCoolDown = 500 * time.Millisecond
 
@@ -256,6 +262,8 @@ func (rp *resourcePool) allocateResources(ctx *actor.Context, req *sproto.Alloca
 		rr := ctx.Ask(fit.Agent.Handler, allocateFreeDevices{
 			slots:       fit.Slots,
 			containerID: containerID,
+			cpus:        req.FittingRequirements.CPUs,
+			memory:      req.FittingRequirements.Memory,
 		})
 		var resp actor.Message
 		if err := rr.Error(); err != nil {
@@ -453,6 +461,12 @@ func (rp *resourcePool) Receive(ctx *actor.Context) error {
 		sproto.DeleteJob:
 		return rp.receiveJobQueueMsg(ctx)
 
+	case updateResourcePoolConfig:
+		ctx.Log().Infof("reloading configuration of resource pool %s", msg.config.PoolName)
+		rp.config = &msg.config
+		rp.scheduler = MakeScheduler(rp.config.Scheduler)
+		rp.fittingMethod = MakeFitFunction(rp.config.Scheduler.FittingPolicy)
+
 	case sproto.GetAllocationSummary:
 		reschedule = false
 		if resp := rp.taskList.TaskSummary(
@@ -507,6 +521,7 @@ func (rp *resourcePool) Receive(ctx *actor.Context) error {
 		ctx.Respond(aproto.GetRPResponse{
 			AgentReconnectWait:    rp.config.AgentReconnectWait,
 			MaxZeroSlotContainers: rp.config.MaxAuxContainersPerAgent,
+			PrefetchImages:        rp.config.PrefetchImages,
 		})
 
 	case schedulerTick:
@@ -68,7 +68,8 @@ func (c containerResources) Start(
 				Devices:     c.devices,
 				Description: spec.Description,
 			},
-			Spec: spec.ToDockerSpec(),
+			Spec:         spec.ToDockerSpec(),
+			TraceContext: rri.TraceContext,
 		},
 		LogContext: logCtx,
 	}).Error()
@@ -94,6 +95,8 @@ func (c containerResources) persist() error {
 		ResourceID: summary.ResourcesID,
 		ID:         c.containerID,
 		AgentID:    agentID,
+		CPUs:       c.req.FittingRequirements.CPUs,
+		Memory:     c.req.FittingRequirements.Memory,
 	}
 	_, err := db.Bun().NewInsert().Model(&snapshot).Exec(context.TODO())
 	return err
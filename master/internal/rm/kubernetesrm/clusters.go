@@ -0,0 +1,204 @@
+package kubernetesrm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	k8sClient "k8s.io/client-go/kubernetes"
+	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+// defaultClusterName is the name of the cluster that the resource manager config points at.
+const defaultClusterName = ""
+
+// clusterNode identifies a node across clusters, since node names are only unique within one.
+type clusterNode struct {
+	cluster string
+	name    string
+}
+
+// agentID is the ID a node is shown with as an agent. Nodes of the default cluster are shown by
+// name, and nodes of other clusters get a suffix derived from their cluster's name.
+func (n clusterNode) agentID() string {
+	if n.cluster == defaultClusterName {
+		return n.name
+	}
+	sum := sha256.Sum256([]byte(n.cluster))
+	return n.name + "@" + hex.EncodeToString(sum[:4])
+}
+
+// kubernetesCluster is a Kubernetes cluster that the pods actor launches pods into. Each cluster
+// has its own clients, informers and request queue.
+type kubernetesCluster struct {
+	name string
+	// clusterConfig is nil for the default cluster.
+	clusterConfig       *config.KubernetesClusterConfig
+	namespaceToPoolName map[string]string
+
+	clientSet            k8sClient.Interface
+	podInterfaces        map[string]typedV1.PodInterface
+	configMapInterfaces  map[string]typedV1.ConfigMapInterface
+	claimInterfaces      map[string]typedV1.PersistentVolumeClaimInterface
+	resourceRequestQueue *requestQueue
+
+	// gangs is nil unless an external gang scheduler is configured.
+	gangs *gangManager
+}
+
+// newKubernetesClusters groups resource pools by the cluster they launch pods into. The default
+// cluster is always present, since the master service and system pods live there.
+func newKubernetesClusters(
+	resourcePoolConfigs []config.ResourcePoolConfig,
+) (map[string]*kubernetesCluster, map[string]*kubernetesCluster) {
+	clusters := map[string]*kubernetesCluster{
+		defaultClusterName: {
+			name:                defaultClusterName,
+			namespaceToPoolName: make(map[string]string),
+		},
+	}
+	poolToCluster := make(map[string]*kubernetesCluster, len(resourcePoolConfigs))
+	for _, rp := range resourcePoolConfigs {
+		name := defaultClusterName
+		if rp.KubernetesCluster != nil {
+			name = rp.KubernetesCluster.Name()
+		}
+
+		c, ok := clusters[name]
+		if !ok {
+			c = &kubernetesCluster{
+				name:                name,
+				clusterConfig:       rp.KubernetesCluster,
+				namespaceToPoolName: make(map[string]string),
+			}
+			clusters[name] = c
+		}
+		c.namespaceToPoolName[rp.KubernetesNamespace] = rp.PoolName
+		poolToCluster[rp.PoolName] = c
+	}
+	return clusters, poolToCluster
+}
+
+// readKubeconfig builds a rest.Config for a context of a kubeconfig file.
+func readKubeconfig(path, context string) (*rest.Config, error) {
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: path},
+		&clientcmd.ConfigOverrides{CurrentContext: context},
+	).ClientConfig()
+}
+
+func (c *kubernetesCluster) restConfig(credsDir string) (*rest.Config, error) {
+	if c.clusterConfig == nil {
+		return readClientConfig(credsDir)
+	}
+	return readKubeconfig(c.clusterConfig.KubeconfigPath, c.clusterConfig.Context)
+}
+
+// startClientSet initializes the clients of the cluster. The namespaces of the cluster and
+// extraNamespaces get pod and config map interfaces.
+func (c *kubernetesCluster) startClientSet(
+	credsDir string, gangScheduler gangScheduler, extraNamespaces ...string,
+) error {
+	restConfig, err := c.restConfig(credsDir)
+	if err != nil {
+		return errors.Wrapf(err, "error building kubernetes config for cluster %q", c.name)
+	}
+
+	c.clientSet, err = k8sClient.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to initialize kubernetes clientSet for cluster %q", c.name)
+	}
+
+	c.podInterfaces = make(map[string]typedV1.PodInterface)
+	c.configMapInterfaces = make(map[string]typedV1.ConfigMapInterface)
+	c.claimInterfaces = make(map[string]typedV1.PersistentVolumeClaimInterface)
+	for _, ns := range append(c.namespaces(), extraNamespaces...) {
+		c.podInterfaces[ns] = c.clientSet.CoreV1().Pods(ns)
+		c.configMapInterfaces[ns] = c.clientSet.CoreV1().ConfigMaps(ns)
+		c.claimInterfaces[ns] = c.clientSet.CoreV1().PersistentVolumeClaims(ns)
+	}
+
+	if gangScheduler != nil {
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return errors.Wrapf(err,
+				"failed to initialize kubernetes dynamic client for cluster %q", c.name)
+		}
+		c.gangs = &gangManager{scheduler: gangScheduler, client: dynamicClient}
+	}
+	return nil
+}
+
+func (c *kubernetesCluster) namespaces() []string {
+	namespaces := make([]string, 0, len(c.namespaceToPoolName))
+	for ns := range c.namespaceToPoolName {
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// masterAddress returns the address pods in this cluster use to reach the master.
+func (c *kubernetesCluster) masterAddress(ip string, port int32) (string, int32) {
+	if c.clusterConfig == nil {
+		return ip, port
+	}
+	if c.clusterConfig.MasterIP != "" {
+		ip = c.clusterConfig.MasterIP
+	}
+	if c.clusterConfig.MasterPort != 0 {
+		port = c.clusterConfig.MasterPort
+	}
+	return ip, port
+}
+
+func (c *kubernetesCluster) deleteKubernetesResources(
+	pods *k8sV1.PodList, configMaps *k8sV1.ConfigMapList,
+) {
+	for _, pod := range pods.Items {
+		c.resourceRequestQueue.deleteKubernetesResources(pod.Namespace, pod.Name, "")
+	}
+
+	for _, configMap := range configMaps.Items {
+		c.resourceRequestQueue.deleteKubernetesResources(configMap.Namespace, "", configMap.Name)
+	}
+}
+
+func (c *kubernetesCluster) listPodsInAllNamespaces(
+	ctx context.Context, opts metaV1.ListOptions,
+) (*k8sV1.PodList, error) {
+	res := &k8sV1.PodList{}
+	for n, i := range c.podInterfaces {
+		pods, err := i.List(ctx, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing pods for namespace %s", n)
+		}
+
+		res.Items = append(res.Items, pods.Items...)
+	}
+
+	return res, nil
+}
+
+func (c *kubernetesCluster) listConfigMapsInAllNamespaces(
+	ctx context.Context, opts metaV1.ListOptions,
+) (*k8sV1.ConfigMapList, error) {
+	res := &k8sV1.ConfigMapList{}
+	for n, i := range c.configMapInterfaces {
+		cms, err := i.List(ctx, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing config maps for namespace %s", n)
+		}
+
+		res.Items = append(res.Items, cms.Items...)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,151 @@
+package kubernetesrm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: a
+  cluster:
+    server: https://a.example.com
+- name: b
+  cluster:
+    server: https://b.example.com
+users:
+- name: u
+  user:
+    token: t
+contexts:
+- name: a
+  context:
+    cluster: a
+    user: u
+- name: b
+  context:
+    cluster: b
+    user: u
+current-context: a
+`
+
+func TestNewKubernetesClusters(t *testing.T) {
+	east := &config.KubernetesClusterConfig{KubeconfigPath: "/kubeconfig", Context: "east"}
+	clusters, poolToCluster := newKubernetesClusters([]config.ResourcePoolConfig{
+		{PoolName: "default", KubernetesNamespace: "det"},
+		{PoolName: "east-a", KubernetesNamespace: "team-a", KubernetesCluster: east},
+		{PoolName: "east-b", KubernetesNamespace: "team-b", KubernetesCluster: &config.KubernetesClusterConfig{
+			KubeconfigPath: "/kubeconfig", Context: "east",
+		}},
+	})
+
+	require.Len(t, clusters, 2)
+	require.Equal(t, map[string]string{"det": "default"},
+		clusters[defaultClusterName].namespaceToPoolName)
+	require.Equal(t, map[string]string{"team-a": "east-a", "team-b": "east-b"},
+		clusters[east.Name()].namespaceToPoolName)
+
+	require.Equal(t, clusters[defaultClusterName], poolToCluster["default"])
+	require.Equal(t, clusters[east.Name()], poolToCluster["east-a"])
+	require.Equal(t, clusters[east.Name()], poolToCluster["east-b"])
+
+	p := &pods{clusters: clusters, poolToCluster: poolToCluster}
+	require.Equal(t, clusters[east.Name()], p.clusterForPool("east-a"))
+	require.Equal(t, p.defaultCluster(), p.clusterForPool("unknown"))
+
+	// The default cluster exists even if every pool targets another cluster.
+	clusters, _ = newKubernetesClusters([]config.ResourcePoolConfig{
+		{PoolName: "east-a", KubernetesNamespace: "team-a", KubernetesCluster: east},
+	})
+	require.Len(t, clusters, 2)
+	require.Empty(t, clusters[defaultClusterName].namespaceToPoolName)
+}
+
+func TestReadKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(testKubeconfig), 0o600))
+
+	c, err := readKubeconfig(path, "")
+	require.NoError(t, err)
+	require.Equal(t, "https://a.example.com", c.Host)
+
+	c, err = readKubeconfig(path, "b")
+	require.NoError(t, err)
+	require.Equal(t, "https://b.example.com", c.Host)
+
+	_, err = readKubeconfig(path, "missing")
+	require.Error(t, err)
+}
+
+func TestMasterAddress(t *testing.T) {
+	ip, port := (&kubernetesCluster{}).masterAddress("10.0.0.1", 8080)
+	require.Equal(t, "10.0.0.1", ip)
+	require.Equal(t, int32(8080), port)
+
+	c := &kubernetesCluster{clusterConfig: &config.KubernetesClusterConfig{
+		KubeconfigPath: "/kubeconfig", MasterIP: "det.example.com",
+	}}
+	ip, port = c.masterAddress("10.0.0.1", 8080)
+	require.Equal(t, "det.example.com", ip)
+	require.Equal(t, int32(8080), port)
+}
+
+func TestNodesWithTheSameNameInTwoClusters(t *testing.T) {
+	east := &config.KubernetesClusterConfig{KubeconfigPath: "/kubeconfig", Context: "east"}
+	resourcePoolConfigs := []config.ResourcePoolConfig{
+		{PoolName: "default", KubernetesNamespace: "det"},
+		{PoolName: "east", KubernetesNamespace: "det", KubernetesCluster: east},
+	}
+	clusters, poolToCluster := newKubernetesClusters(resourcePoolConfigs)
+	p := &pods{
+		clusters:            clusters,
+		poolToCluster:       poolToCluster,
+		resourcePoolConfigs: resourcePoolConfigs,
+		currentNodes:        make(map[clusterNode]*k8sV1.Node),
+		syslog:              logrus.WithField("test", t.Name()),
+	}
+
+	node := func() *k8sV1.Node {
+		return &k8sV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "node-1"}}
+	}
+	p.nodeStatusCallback(clusters[defaultClusterName], watch.Event{Type: watch.Added, Object: node()})
+	p.nodeStatusCallback(clusters[east.Name()], watch.Event{Type: watch.Added, Object: node()})
+	require.Len(t, p.currentNodes, 2)
+
+	defaultNode := clusterNode{cluster: defaultClusterName, name: "node-1"}
+	eastNode := clusterNode{cluster: east.Name(), name: "node-1"}
+	require.Equal(t, "node-1", defaultNode.agentID())
+	require.NotEqual(t, defaultNode.agentID(), eastNode.agentID())
+
+	c, n, err := p.clusterForNode(defaultNode.agentID())
+	require.NoError(t, err)
+	require.Equal(t, clusters[defaultClusterName], c)
+	require.Equal(t, defaultNode, n)
+	c, n, err = p.clusterForNode(eastNode.agentID())
+	require.NoError(t, err)
+	require.Equal(t, clusters[east.Name()], c)
+	require.Equal(t, eastNode, n)
+
+	// Each pool only gets the node of its own cluster.
+	poolsToNodes, nodesToPools := p.getNodeResourcePoolMapping(nil)
+	require.Equal(t, []clusterNode{defaultNode}, poolsToNodes["default"])
+	require.Equal(t, []clusterNode{eastNode}, poolsToNodes["east"])
+	require.Equal(t, []string{"default"}, nodesToPools[defaultNode.agentID()])
+	require.Equal(t, []string{"east"}, nodesToPools[eastNode.agentID()])
+
+	// Deleting the node of one cluster leaves the other cluster's node alone.
+	p.nodeStatusCallback(clusters[east.Name()], watch.Event{Type: watch.Deleted, Object: node()})
+	require.Equal(t, map[clusterNode]*k8sV1.Node{defaultNode: node()}, p.currentNodes)
+	_, _, err = p.clusterForNode(eastNode.agentID())
+	require.Error(t, err)
+}
@@ -0,0 +1,266 @@
+package kubernetesrm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	k8sV1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	kueueQueueNameLabel           = "kueue.x-k8s.io/queue-name"
+	kueuePodGroupNameLabel        = "kueue.x-k8s.io/pod-group-name"
+	kueuePodGroupTotalCountAnnot  = "kueue.x-k8s.io/pod-group-total-count"
+	kueueAdmittedCondition        = "Admitted"
+	volcanoSchedulerName          = "volcano"
+	volcanoGroupNameAnnotation    = "scheduling.k8s.io/group-name"
+	volcanoDefaultQueue           = "default"
+	volcanoPodGroupPhaseInqueue   = "Inqueue"
+	volcanoPodGroupPhaseRunning   = "Running"
+	maxKubernetesObjectNameLength = 63
+	gangNamePrefix                = "det-"
+)
+
+var (
+	kueueWorkloadResource = schema.GroupVersionResource{
+		Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "workloads",
+	}
+	volcanoPodGroupResource = schema.GroupVersionResource{
+		Group: "scheduling.volcano.sh", Version: "v1beta1", Resource: "podgroups",
+	}
+
+	invalidObjectNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// gang is the set of pods of one allocation that must be admitted together.
+type gang struct {
+	name         string
+	namespace    string
+	allocationID model.AllocationID
+	size         int
+	podResources k8sV1.ResourceList
+}
+
+func newGang(
+	allocationID model.AllocationID, namespace string, size int, podResources k8sV1.ResourceList,
+) gang {
+	return gang{
+		name:         gangName(allocationID),
+		namespace:    namespace,
+		allocationID: allocationID,
+		size:         size,
+		podResources: podResources,
+	}
+}
+
+// gangName derives a valid Kubernetes object name from an allocation ID.
+func gangName(allocationID model.AllocationID) string {
+	return kubernetesObjectName(gangNamePrefix + string(allocationID))
+}
+
+// kubernetesObjectName joins parts into a valid Kubernetes object name. Names that are too long
+// are truncated and suffixed with a hash of the parts, so that they stay unique.
+func kubernetesObjectName(parts ...string) string {
+	joined := strings.Join(parts, "-")
+	name := strings.TrimRight(
+		invalidObjectNameChars.ReplaceAllString(strings.ToLower(joined), "-"), "-")
+	if len(name) <= maxKubernetesObjectNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(joined))
+	suffix := hex.EncodeToString(sum[:4])
+	name = strings.TrimRight(name[:maxKubernetesObjectNameLength-len(suffix)-1], "-")
+	return name + "-" + suffix
+}
+
+// gangScheduler is a gang-scheduling backend. Each backend represents a gang as a custom
+// resource that either Determined creates before the gang's pods and deletes once they are gone,
+// or the backend manages itself.
+type gangScheduler interface {
+	// resource is the custom resource that represents a gang.
+	resource() schema.GroupVersionResource
+	// object builds the custom resource that represents a gang, or returns nil if the backend
+	// creates the resource itself from the pods of the gang.
+	object(g gang) *unstructured.Unstructured
+	// configurePod binds a pod spec to its gang.
+	configurePod(pod *k8sV1.Pod, g gang)
+	// admitted reports whether the backend has admitted the gang described by obj.
+	admitted(obj *unstructured.Unstructured) bool
+}
+
+func newGangScheduler(c *config.GangSchedulerConfig) (gangScheduler, error) {
+	switch c.Type {
+	case config.KueueGangScheduler:
+		return &kueueGangScheduler{queue: c.Queue}, nil
+	case config.VolcanoGangScheduler:
+		queue := c.Queue
+		if queue == "" {
+			queue = volcanoDefaultQueue
+		}
+		return &volcanoGangScheduler{queue: queue}, nil
+	default:
+		return nil, errors.Errorf("unknown gang scheduler type %q", c.Type)
+	}
+}
+
+// kueueGangScheduler submits gangs to a Kueue LocalQueue as Workloads.
+type kueueGangScheduler struct {
+	queue string
+}
+
+func (k *kueueGangScheduler) resource() schema.GroupVersionResource {
+	return kueueWorkloadResource
+}
+
+// object returns nil since Kueue creates the Workload of a pod group itself, named after the
+// group, from the labels of the group's pods. The Workload is owned by the pods, so Kubernetes
+// garbage collects it once they are gone.
+func (k *kueueGangScheduler) object(g gang) *unstructured.Unstructured {
+	return nil
+}
+
+func (k *kueueGangScheduler) configurePod(pod *k8sV1.Pod, g gang) {
+	if pod.ObjectMeta.Annotations == nil {
+		pod.ObjectMeta.Annotations = make(map[string]string)
+	}
+	pod.ObjectMeta.Labels[kueueQueueNameLabel] = k.queue
+	pod.ObjectMeta.Labels[kueuePodGroupNameLabel] = g.name
+	pod.ObjectMeta.Annotations[kueuePodGroupTotalCountAnnot] = strconv.Itoa(g.size)
+}
+
+func (k *kueueGangScheduler) admitted(obj *unstructured.Unstructured) bool {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == kueueAdmittedCondition &&
+			condition["status"] == string(metaV1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
+// volcanoGangScheduler submits gangs to a Volcano Queue as PodGroups.
+type volcanoGangScheduler struct {
+	queue string
+}
+
+func (v *volcanoGangScheduler) resource() schema.GroupVersionResource {
+	return volcanoPodGroupResource
+}
+
+func (v *volcanoGangScheduler) object(g gang) *unstructured.Unstructured {
+	obj := newGangObject(volcanoPodGroupResource, "PodGroup", g)
+	obj.Object["spec"] = map[string]interface{}{
+		"minMember":    int64(g.size),
+		"queue":        v.queue,
+		"minResources": resourceListToUnstructured(g.podResources, g.size),
+	}
+	return obj
+}
+
+func (v *volcanoGangScheduler) configurePod(pod *k8sV1.Pod, g gang) {
+	if pod.ObjectMeta.Annotations == nil {
+		pod.ObjectMeta.Annotations = make(map[string]string)
+	}
+	pod.Spec.SchedulerName = volcanoSchedulerName
+	pod.ObjectMeta.Annotations[volcanoGroupNameAnnotation] = g.name
+}
+
+func (v *volcanoGangScheduler) admitted(obj *unstructured.Unstructured) bool {
+	phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+	if err != nil {
+		return false
+	}
+	return phase == volcanoPodGroupPhaseInqueue || phase == volcanoPodGroupPhaseRunning
+}
+
+func newGangObject(gvr schema.GroupVersionResource, kind string, g gang) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	obj.SetKind(kind)
+	obj.SetName(g.name)
+	obj.SetNamespace(g.namespace)
+	obj.SetLabels(map[string]string{determinedLabel: string(g.allocationID)})
+	return obj
+}
+
+func resourceListToUnstructured(resources k8sV1.ResourceList, count int) map[string]interface{} {
+	out := make(map[string]interface{}, len(resources))
+	for name, quantity := range resources {
+		total := quantity.DeepCopy()
+		for i := 1; i < count; i++ {
+			total.Add(quantity)
+		}
+		out[string(name)] = total.String()
+	}
+	return out
+}
+
+// gangManager creates, inspects and deletes the custom resources of a gang-scheduling backend.
+type gangManager struct {
+	scheduler gangScheduler
+	client    dynamic.Interface
+}
+
+func (m *gangManager) resourceInterface(namespace string) dynamic.ResourceInterface {
+	return m.client.Resource(m.scheduler.resource()).Namespace(namespace)
+}
+
+// create creates the custom resource for a gang. It is not an error for it to already exist,
+// which happens when pods are restored after a master restart.
+func (m *gangManager) create(ctx context.Context, g gang) error {
+	obj := m.scheduler.object(g)
+	if obj == nil {
+		return nil
+	}
+	_, err := m.resourceInterface(g.namespace).Create(ctx, obj, metaV1.CreateOptions{})
+	if err != nil && !k8error.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "creating %s %s", m.scheduler.resource().Resource, g.name)
+	}
+	return nil
+}
+
+// admitted reports whether the backend has admitted the named gang. A gang whose custom resource
+// does not exist yet is not admitted.
+func (m *gangManager) admitted(ctx context.Context, namespace, name string) (bool, error) {
+	obj, err := m.resourceInterface(namespace).Get(ctx, name, metaV1.GetOptions{})
+	if k8error.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrapf(err, "getting %s %s", m.scheduler.resource().Resource, name)
+	}
+	return m.scheduler.admitted(obj), nil
+}
+
+// delete deletes the custom resource for a gang, ignoring gangs that are already gone and gangs
+// whose resource is managed by the backend.
+func (m *gangManager) delete(ctx context.Context, g gang) error {
+	if m.scheduler.object(g) == nil {
+		return nil
+	}
+	err := m.resourceInterface(g.namespace).Delete(ctx, g.name, metaV1.DeleteOptions{})
+	if err != nil && !k8error.IsNotFound(err) {
+		return errors.Wrapf(err, "deleting %s %s", m.scheduler.resource().Resource, g.name)
+	}
+	return nil
+}
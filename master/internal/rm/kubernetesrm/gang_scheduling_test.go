@@ -0,0 +1,195 @@
+package kubernetesrm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func newTestGangManager(t *testing.T, c config.GangSchedulerConfig) *gangManager {
+	scheduler, err := newGangScheduler(&c)
+	require.NoError(t, err)
+	return &gangManager{
+		scheduler: scheduler,
+		client:    dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()),
+	}
+}
+
+func testGang() gang {
+	return newGang("1.Some_Trial.1", "default", 2, k8sV1.ResourceList{
+		ResourceTypeNvidia: *resource.NewQuantity(4, resource.DecimalSI),
+	})
+}
+
+func TestGangName(t *testing.T) {
+	require.Equal(t, "det-1-some-trial-1", gangName("1.Some_Trial.1"))
+
+	long := gangName(model.AllocationID(string(make([]byte, 100))))
+	require.LessOrEqual(t, len(long), maxKubernetesObjectNameLength)
+}
+
+func TestGangSchedulerConfigurePod(t *testing.T) {
+	g := testGang()
+
+	kueue := newTestGangManager(t, config.GangSchedulerConfig{
+		Type: config.KueueGangScheduler, Queue: "team-a",
+	})
+	pod := &k8sV1.Pod{ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{}}}
+	kueue.scheduler.configurePod(pod, g)
+	require.Equal(t, "team-a", pod.Labels[kueueQueueNameLabel])
+	require.Equal(t, g.name, pod.Labels[kueuePodGroupNameLabel])
+	require.Equal(t, "2", pod.Annotations[kueuePodGroupTotalCountAnnot])
+
+	volcano := newTestGangManager(t, config.GangSchedulerConfig{Type: config.VolcanoGangScheduler})
+	pod = &k8sV1.Pod{ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{}}}
+	volcano.scheduler.configurePod(pod, g)
+	require.Equal(t, volcanoSchedulerName, pod.Spec.SchedulerName)
+	require.Equal(t, g.name, pod.Annotations[volcanoGroupNameAnnotation])
+}
+
+func TestGangManagerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	m := newTestGangManager(t, config.GangSchedulerConfig{Type: config.VolcanoGangScheduler})
+	g := testGang()
+
+	// A gang that hasn't been created yet isn't admitted.
+	admitted, err := m.admitted(ctx, g.namespace, g.name)
+	require.NoError(t, err)
+	require.False(t, admitted)
+
+	require.NoError(t, m.create(ctx, g))
+	// Creating a gang that already exists, e.g. on restore, is not an error.
+	require.NoError(t, m.create(ctx, g))
+
+	obj, err := m.resourceInterface(g.namespace).Get(ctx, g.name, metaV1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, string(g.allocationID), obj.GetLabels()[determinedLabel])
+	require.Equal(t, "PodGroup", obj.GetKind())
+	minMember, _, _ := unstructured.NestedInt64(obj.Object, "spec", "minMember")
+	require.Equal(t, int64(2), minMember)
+	queue, _, _ := unstructured.NestedString(obj.Object, "spec", "queue")
+	require.Equal(t, volcanoDefaultQueue, queue)
+	gpus, _, _ := unstructured.NestedString(obj.Object, "spec", "minResources", ResourceTypeNvidia)
+	require.Equal(t, "8", gpus)
+
+	admitted, err = m.admitted(ctx, g.namespace, g.name)
+	require.NoError(t, err)
+	require.False(t, admitted)
+
+	require.NoError(t, unstructured.SetNestedField(
+		obj.Object, volcanoPodGroupPhaseInqueue, "status", "phase"))
+	_, err = m.resourceInterface(g.namespace).Update(ctx, obj, metaV1.UpdateOptions{})
+	require.NoError(t, err)
+	admitted, err = m.admitted(ctx, g.namespace, g.name)
+	require.NoError(t, err)
+	require.True(t, admitted)
+
+	require.NoError(t, m.delete(ctx, g))
+	// Deleting a gang that is already gone is not an error.
+	require.NoError(t, m.delete(ctx, g))
+	_, err = m.resourceInterface(g.namespace).Get(ctx, g.name, metaV1.GetOptions{})
+	require.True(t, k8error.IsNotFound(err))
+}
+
+// kueueWorkload builds the Workload that Kueue creates for the pods of a gang.
+func kueueWorkload(g gang, admitted bool) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(kueueWorkloadResource.GroupVersion().String())
+	obj.SetKind("Workload")
+	obj.SetName(g.name)
+	obj.SetNamespace(g.namespace)
+	if admitted {
+		_ = unstructured.SetNestedSlice(obj.Object, []interface{}{
+			map[string]interface{}{"type": kueueAdmittedCondition, "status": "True"},
+		}, "status", "conditions")
+	}
+	return obj
+}
+
+func TestKueueGangManager(t *testing.T) {
+	ctx := context.Background()
+	m := newTestGangManager(t, config.GangSchedulerConfig{
+		Type: config.KueueGangScheduler, Queue: "team-a",
+	})
+	g := testGang()
+
+	// Kueue creates the Workload from the pods' labels, so Determined must not create its own.
+	require.NoError(t, m.create(ctx, g))
+	_, err := m.resourceInterface(g.namespace).Get(ctx, g.name, metaV1.GetOptions{})
+	require.True(t, k8error.IsNotFound(err))
+
+	_, err = m.resourceInterface(g.namespace).Create(
+		ctx, kueueWorkload(g, true), metaV1.CreateOptions{})
+	require.NoError(t, err)
+	admitted, err := m.admitted(ctx, g.namespace, g.name)
+	require.NoError(t, err)
+	require.True(t, admitted)
+
+	// Kueue owns the Workload, so Determined leaves it for garbage collection.
+	require.NoError(t, m.delete(ctx, g))
+	_, err = m.resourceInterface(g.namespace).Get(ctx, g.name, metaV1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func TestGangStatusCallback(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	updates := make(chan sproto.UpdatePodStatus, 10)
+	cluster, _ := system.ActorOf(actor.Addr("cluster"), actor.ActorFunc(
+		func(ctx *actor.Context) error {
+			if msg, ok := ctx.Message().(sproto.UpdatePodStatus); ok {
+				updates <- msg
+			}
+			return nil
+		}))
+
+	m := newTestGangManager(t, config.GangSchedulerConfig{Type: config.VolcanoGangScheduler})
+	g := testGang()
+	podHandler := &pod{allocationID: g.allocationID}
+	p := &pods{
+		cluster: cluster,
+		allocationIDToGang: map[model.AllocationID]*gangState{
+			g.allocationID: {gang: g, gangs: m, pods: 1},
+		},
+		podHandlerToMetadata: map[*pod]podMetadata{
+			podHandler: {podName: "pod", containerID: "container"},
+		},
+		containerIDToSchedulingState: map[string]sproto.SchedulingState{
+			"container": sproto.SchedulingStateQueued,
+		},
+		syslog: logrus.WithField("test", t.Name()),
+	}
+
+	obj := m.scheduler.object(g)
+	p.gangStatusCallback(system, watch.Event{Type: watch.Modified, Object: obj})
+	require.False(t, p.gangAdmitted(g.allocationID))
+
+	require.NoError(t, unstructured.SetNestedField(
+		obj.Object, volcanoPodGroupPhaseInqueue, "status", "phase"))
+	p.gangStatusCallback(system, watch.Event{Type: watch.Modified, Object: obj})
+	require.True(t, p.gangAdmitted(g.allocationID))
+	require.Equal(t, sproto.SchedulingStateScheduled, p.containerIDToSchedulingState["container"])
+
+	select {
+	case update := <-updates:
+		require.Equal(t, "container", update.ContainerID)
+		require.Equal(t, sproto.SchedulingStateScheduled, update.State)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a pod status update after gang admission")
+	}
+}
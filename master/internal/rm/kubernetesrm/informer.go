@@ -7,6 +7,7 @@ import (
 	"github.com/sirupsen/logrus"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	watchtools "k8s.io/client-go/tools/watch"
@@ -134,6 +135,46 @@ func newNodeInformer(
 	}, nil
 }
 
+// newGangInformer watches all the gang objects of a namespace, since a backend that creates them
+// itself, like Kueue, doesn't carry Determined's labels over to them.
+func newGangInformer(
+	ctx context.Context,
+	resourceInterface dynamic.ResourceInterface,
+	namespace string,
+	cb informerCallback,
+) (*informer, error) {
+	gangs, err := resourceInterface.List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	rw, err := watchtools.NewRetryWatcher(gangs.GetResourceVersion(), &cache.ListWatch{
+		WatchFunc: func(options metaV1.ListOptions) (watch.Interface, error) {
+			return resourceInterface.Watch(ctx, options)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Log when gangs are first added to the informer (at start-up).
+	syslog := logrus.WithFields(logrus.Fields{
+		"component": "gang-informer",
+		"namespace": namespace,
+	})
+	for i := range gangs.Items {
+		syslog.Debugf("initial inform added gang: %s", gangs.Items[i].GetName())
+		cb(watch.Event{Object: &gangs.Items[i], Type: watch.Added})
+	}
+
+	return &informer{
+		cb:         cb,
+		name:       "gang",
+		syslog:     syslog,
+		resultChan: rw.ResultChan(),
+	}, nil
+}
+
 func (i *informer) run(ctx context.Context) {
 	i.syslog.Debugf("%s informer is starting", i.name)
 	for {
@@ -243,13 +243,10 @@ func newKubernetesResourceManager(
 func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 	switch msg := ctx.Message().(type) {
 	case actor.PreStart:
-		poolNamespaces := make(map[string]string)
 		for i := range k.poolsConfig {
 			if k.poolsConfig[i].KubernetesNamespace == "" {
 				k.poolsConfig[i].KubernetesNamespace = k.config.Namespace
 			}
-
-			poolNamespaces[k.poolsConfig[i].KubernetesNamespace] = k.poolsConfig[i].PoolName
 		}
 
 		k.podsActor = Initialize(
@@ -257,11 +254,11 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 			k.echoRef,
 			ctx.Self(),
 			k.config.Namespace,
-			poolNamespaces,
 			k.config.MasterServiceName,
 			k.masterTLSConfig,
 			k.loggingConfig,
 			k.config.DefaultScheduler,
+			k.config.GangScheduler,
 			k.config.SlotType,
 			config.PodSlotResourceRequests{CPU: k.config.SlotResourceRequests.CPU},
 			k.poolsConfig,
@@ -423,6 +420,10 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 	case taskContainerDefaults:
 		ctx.Respond(k.getTaskContainerDefaults(msg))
 
+	case sproto.UpdateResourcePoolConfigs:
+		k.poolsConfig = msg.ResourcePools
+		ctx.Tell(k.podsActor, msg)
+
 	case tasklist.GroupActorStopped:
 		k.forwardToAllPools(ctx, msg)
 
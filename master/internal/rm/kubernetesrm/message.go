@@ -15,6 +15,7 @@ type (
 		Spec         tasks.TaskSpec
 		Slots        int
 		Rank         int
+		NumPods      int
 		ResourcePool string
 		Namespace    string
 
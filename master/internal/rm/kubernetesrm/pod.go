@@ -57,7 +57,7 @@ type pod struct {
 
 	clusterID    string
 	allocationID model.AllocationID
-	clientSet    *k8sClient.Clientset
+	clientSet    k8sClient.Interface
 	namespace    string
 	masterIP     string
 	masterPort   int32
@@ -74,11 +74,15 @@ type pod struct {
 	scheduler            string
 	slotType             device.Type
 	slotResourceRequests config.PodSlotResourceRequests
+	// gang and gangScheduler are set when an external gang scheduler admits this pod.
+	gang          *gang
+	gangScheduler gangScheduler
 
 	pod           *k8sV1.Pod
 	podName       string
 	configMap     *k8sV1.ConfigMap
 	configMapName string
+	claims        []*k8sV1.PersistentVolumeClaim
 	// TODO: Drop this manufactured container obj all together.
 	container        cproto.Container
 	ports            []int
@@ -100,7 +104,7 @@ type podNodeInfo struct {
 func newPod(
 	msg StartTaskPod,
 	clusterID string,
-	clientSet *k8sClient.Clientset,
+	clientSet k8sClient.Interface,
 	namespace string,
 	masterIP string,
 	masterPort int32,
@@ -341,7 +345,7 @@ func (p *pod) createPodSpecAndSubmit() error {
 		return err
 	}
 
-	p.resourceRequestQueue.createKubernetesResources(p.pod, p.configMap)
+	p.resourceRequestQueue.createKubernetesResources(p.pod, p.configMap, p.claims)
 	return nil
 }
 
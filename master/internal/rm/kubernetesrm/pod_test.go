@@ -108,6 +108,7 @@ func createPodWithMockQueue(t *testing.T, k8sRequestQueue *requestQueue) (
 		k8sRequestQueue = startRequestQueue(
 			map[string]typedV1.PodInterface{"default": podInterface},
 			map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+			nil,
 			failures,
 		)
 	}
@@ -708,6 +709,7 @@ func TestKillTaskPod(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 	ref, _, _ := createPodWithMockQueue(t, k8sRequestQueue)
@@ -734,6 +736,7 @@ func TestResourceCreationCancelled(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -784,6 +787,7 @@ func TestResourceDeletionFailed(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
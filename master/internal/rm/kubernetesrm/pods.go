@@ -16,15 +16,14 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/exp/maps"
+	"github.com/uptrace/bun"
 	k8sV1 "k8s.io/api/core/v1"
 	k8error "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
-	k8sClient "k8s.io/client-go/kubernetes"
-	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 
 	"github.com/determined-ai/determined/master/internal/config"
@@ -32,6 +31,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/rm/rmevents"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/actor/actors"
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
@@ -55,6 +55,14 @@ type podMetadata struct {
 	containerID string
 }
 
+// gangState tracks the gang of an allocation scheduled by an external gang scheduler.
+type gangState struct {
+	gang     gang
+	gangs    *gangManager
+	pods     int
+	admitted bool
+}
+
 // High lever overview of the actors within the kubernetes package:
 //
 //	pods
@@ -64,41 +72,45 @@ type podMetadata struct {
 //	  +- events: sends updates about kubernetes events.
 //	  +- requestQueue: queues requests to create / delete kubernetes resources.
 //	     +- requestProcessingWorkers: processes request to create / delete kubernetes resources.
+//
+// The informers and the request queue are started once per Kubernetes cluster.
 type pods struct {
 	mu sync.RWMutex
 	wg waitgroupx.Group
 
 	cluster               *actor.Ref
 	namespace             string
-	namespaceToPoolName   map[string]string
 	masterServiceName     string
 	scheduler             string
+	gangSchedulerConfig   *config.GangSchedulerConfig
 	slotType              device.Type
 	slotResourceRequests  config.PodSlotResourceRequests
 	resourcePoolConfigs   []config.ResourcePoolConfig
 	baseContainerDefaults *model.TaskContainerDefaultsConfig
 	credsDir              string
 
-	clientSet        *k8sClient.Clientset
 	masterIP         string
 	masterPort       int32
 	masterTLSConfig  model.TLSClientConfig
 	loggingTLSConfig model.TLSClientConfig
 	loggingConfig    model.LoggingConfig
 
-	resourceRequestQueue         *requestQueue
 	podNameToPodHandler          map[string]*pod
 	podNameToResourcePool        map[string]string
 	containerIDToPodName         map[string]string
 	containerIDToSchedulingState map[string]sproto.SchedulingState
 	podNameToContainerID         map[string]string
 	podHandlerToMetadata         map[*pod]podMetadata
-	nodeToSystemResourceRequests map[string]int64
+	nodeToSystemResourceRequests map[clusterNode]int64
+	allocationIDToGang           map[model.AllocationID]*gangState
+
+	currentNodes map[clusterNode]*k8sV1.Node
 
-	currentNodes map[string]*k8sV1.Node
+	clusters      map[string]*kubernetesCluster
+	poolToCluster map[string]*kubernetesCluster
 
-	podInterfaces       map[string]typedV1.PodInterface
-	configMapInterfaces map[string]typedV1.ConfigMapInterface
+	// gangScheduler is nil unless an external gang scheduler is configured.
+	gangScheduler gangScheduler
 
 	summarizeCacheLock sync.RWMutex
 	summarizeCache     summarizeResult
@@ -124,6 +136,7 @@ type SummarizeResources struct {
 }
 
 type reattachAllocationPods struct {
+	resourcePool string
 	numPods      int
 	allocationID model.AllocationID
 	slots        int
@@ -139,17 +152,23 @@ type refreshPodStates struct {
 	allocationID model.AllocationID
 }
 
+// claimCollectionInterval is how often the claims of finished tasks and of deleted or archived
+// workspaces are deleted.
+const claimCollectionInterval = 10 * time.Minute
+
+type collectClaims struct{}
+
 // Initialize creates a new global pods actor.
 func Initialize(
 	s *actor.System,
 	e *echo.Echo,
 	c *actor.Ref,
 	namespace string,
-	namespaceToPoolName map[string]string,
 	masterServiceName string,
 	masterTLSConfig model.TLSClientConfig,
 	loggingConfig model.LoggingConfig,
 	scheduler string,
+	gangSchedulerConfig *config.GangSchedulerConfig,
 	slotType device.Type,
 	slotResourceRequests config.PodSlotResourceRequests,
 	resourcePoolConfigs []config.ResourcePoolConfig,
@@ -162,15 +181,16 @@ func Initialize(
 	if loggingConfig.ElasticLoggingConfig != nil {
 		loggingTLSConfig = loggingConfig.ElasticLoggingConfig.Security.TLS
 	}
+	clusters, poolToCluster := newKubernetesClusters(resourcePoolConfigs)
 	p := &pods{
 		wg: waitgroupx.WithContext(context.Background()),
 
 		cluster:                      c,
 		namespace:                    namespace,
-		namespaceToPoolName:          namespaceToPoolName,
 		masterServiceName:            masterServiceName,
 		masterTLSConfig:              masterTLSConfig,
 		scheduler:                    scheduler,
+		gangSchedulerConfig:          gangSchedulerConfig,
 		loggingTLSConfig:             loggingTLSConfig,
 		loggingConfig:                loggingConfig,
 		podNameToPodHandler:          make(map[string]*pod),
@@ -186,10 +206,11 @@ func Initialize(
 		credsDir:                     credsDir,
 		masterIP:                     masterIP,
 		masterPort:                   masterPort,
-		currentNodes:                 make(map[string]*k8sV1.Node),
-		nodeToSystemResourceRequests: make(map[string]int64),
-		podInterfaces:                make(map[string]typedV1.PodInterface),
-		configMapInterfaces:          make(map[string]typedV1.ConfigMapInterface),
+		currentNodes:                 make(map[clusterNode]*k8sV1.Node),
+		clusters:                     clusters,
+		poolToCluster:                poolToCluster,
+		nodeToSystemResourceRequests: make(map[clusterNode]int64),
+		allocationIDToGang:           make(map[model.AllocationID]*gangState),
 		syslog:                       logrus.WithField("pod-name", namespace),
 	}
 
@@ -222,6 +243,13 @@ func Initialize(
 		panic(err)
 	}
 
+	if p.gangScheduler != nil {
+		err = p.startGangInformers(s)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	return podsActor
 }
 
@@ -244,8 +272,14 @@ func (p *pods) Receive(ctx *actor.Context) error {
 		if err := p.deleteDoomedKubernetesResources(ctx); err != nil {
 			return err
 		}
+		ctx.Tell(ctx.Self(), collectClaims{})
 	case actor.PostStop:
 
+	case collectClaims:
+		p.wg.Go(p.deleteFinishedTaskClaims)
+		p.wg.Go(p.deleteWorkspaceClaims)
+		actors.NotifyAfter(ctx, claimCollectionInterval, collectClaims{})
+
 	case StartTaskPod:
 		if err := p.receiveStartTaskPod(ctx, msg); err != nil {
 			return err
@@ -288,13 +322,17 @@ func (p *pods) Receive(ctx *actor.Context) error {
 		ctx.Respond(resp)
 
 	case *apiv1.DisableAgentRequest:
-		resp, err := p.disableNode(ctx, msg.AgentId, msg.Drain)
+		// Migrating tasks off of nodes isn't supported, so it only drains them.
+		resp, err := p.disableNode(ctx, msg.AgentId, msg.Drain || msg.Migrate)
 		if err != nil {
 			ctx.Respond(err)
 			return nil
 		}
 		ctx.Respond(resp)
 
+	case sproto.UpdateResourcePoolConfigs:
+		p.resourcePoolConfigs = msg.ResourcePools
+
 	default:
 		ctx.Log().Errorf("unexpected message %T", msg)
 		return actor.ErrUnexpectedMessage(ctx)
@@ -344,31 +382,63 @@ func readClientConfig(credsDir string) (*rest.Config, error) {
 }
 
 func (p *pods) startClientSet(ctx *actor.Context) error {
-	config, err := readClientConfig(p.credsDir)
-	if err != nil {
-		return errors.Wrap(err, "error building kubernetes config")
-	}
-
-	p.clientSet, err = k8sClient.NewForConfig(config)
-	if err != nil {
-		return errors.Wrap(err, "failed to initialize kubernetes clientSet")
+	if p.gangSchedulerConfig != nil {
+		scheduler, err := newGangScheduler(p.gangSchedulerConfig)
+		if err != nil {
+			return err
+		}
+		p.gangScheduler = scheduler
+		ctx.Log().Infof("gang scheduling with %s enabled", p.gangSchedulerConfig.Type)
 	}
 
-	for _, ns := range append(maps.Keys(p.namespaceToPoolName), p.namespace) {
-		p.podInterfaces[ns] = p.clientSet.CoreV1().Pods(ns)
-		p.configMapInterfaces[ns] = p.clientSet.CoreV1().ConfigMaps(ns)
+	for _, c := range p.clusters {
+		var extraNamespaces []string
+		if c.name == defaultClusterName {
+			extraNamespaces = append(extraNamespaces, p.namespace)
+		}
+		if err := c.startClientSet(p.credsDir, p.gangScheduler, extraNamespaces...); err != nil {
+			return err
+		}
+		if c.name != defaultClusterName {
+			ctx.Log().Infof("kubernetes clientSet initialized for cluster %s", c.name)
+		}
 	}
 
 	ctx.Log().Infof("kubernetes clientSet initialized")
 	return nil
 }
 
+// defaultCluster returns the cluster that the resource manager config points at.
+func (p *pods) defaultCluster() *kubernetesCluster {
+	return p.clusters[defaultClusterName]
+}
+
+// clusterForPool returns the cluster that a resource pool launches pods into.
+func (p *pods) clusterForPool(resourcePool string) *kubernetesCluster {
+	if c, ok := p.poolToCluster[resourcePool]; ok {
+		return c
+	}
+	return p.defaultCluster()
+}
+
+// clusterForNode returns the node shown as the given agent and the cluster it belongs to.
+func (p *pods) clusterForNode(agentID string) (*kubernetesCluster, clusterNode, error) {
+	for n := range p.currentNodes {
+		if n.agentID() == agentID {
+			if c, ok := p.clusters[n.cluster]; ok {
+				return c, n, nil
+			}
+		}
+	}
+	return nil, clusterNode{}, fmt.Errorf("node %s not found", agentID)
+}
+
 func (p *pods) getMasterIPAndPort(ctx *actor.Context) error {
 	if p.masterIP != "" && p.masterPort != 0 {
 		// Master ip and port were manually configured (probably for development purposes).
 		return nil
 	}
-	masterService, err := p.clientSet.CoreV1().Services(p.namespace).Get(
+	masterService, err := p.defaultCluster().clientSet.CoreV1().Services(p.namespace).Get(
 		context.TODO(), p.masterServiceName, metaV1.GetOptions{})
 	if err != nil {
 		return errors.Wrap(err, "failed to get master service")
@@ -381,7 +451,7 @@ func (p *pods) getMasterIPAndPort(ctx *actor.Context) error {
 }
 
 func (p *pods) getSystemResourceRequests(ctx *actor.Context) error {
-	systemPods, err := p.podInterfaces[p.namespace].List(
+	systemPods, err := p.defaultCluster().podInterfaces[p.namespace].List(
 		context.TODO(), metaV1.ListOptions{LabelSelector: determinedSystemLabel})
 	if err != nil {
 		return errors.Wrap(err, "failed to get system pods")
@@ -389,8 +459,9 @@ func (p *pods) getSystemResourceRequests(ctx *actor.Context) error {
 
 	for _, systemPod := range systemPods.Items {
 		for _, container := range systemPod.Spec.Containers {
-			p.nodeToSystemResourceRequests[systemPod.Spec.NodeName] += container.Resources.Requests.Cpu().
-				MilliValue()
+			// System pods only run in the default cluster.
+			n := clusterNode{cluster: defaultClusterName, name: systemPod.Spec.NodeName}
+			p.nodeToSystemResourceRequests[n] += container.Resources.Requests.Cpu().MilliValue()
 		}
 	}
 	return nil
@@ -401,18 +472,19 @@ func (p *pods) reattachAllocationPods(ctx *actor.Context, msg reattachAllocation
 		LabelSelector: fmt.Sprintf("%s=%s", determinedLabel, msg.allocationID),
 	}
 
-	pods, err := p.listPodsInAllNamespaces(context.TODO(), listOptions)
+	cluster := p.clusterForPool(msg.resourcePool)
+	pods, err := cluster.listPodsInAllNamespaces(context.TODO(), listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing pods checking if they can be restored")
 	}
 
-	configMaps, err := p.listConfigMapsInAllNamespaces(context.TODO(), listOptions)
+	configMaps, err := cluster.listConfigMapsInAllNamespaces(context.TODO(), listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing config maps checking if they can be restored")
 	}
 	existingConfigMaps := make(set.Set[string])
 	for _, cm := range configMaps.Items {
-		if _, ok := p.namespaceToPoolName[cm.Namespace]; !ok {
+		if _, ok := cluster.namespaceToPoolName[cm.Namespace]; !ok {
 			continue
 		}
 		existingConfigMaps.Insert(cm.Name)
@@ -423,7 +495,7 @@ func (p *pods) reattachAllocationPods(ctx *actor.Context, msg reattachAllocation
 	var ports [][]int
 	var resourcePool string
 	for _, pod := range pods.Items {
-		if _, ok := p.namespaceToPoolName[pod.Namespace]; !ok {
+		if _, ok := cluster.namespaceToPoolName[pod.Namespace]; !ok {
 			continue
 		}
 
@@ -434,7 +506,7 @@ func (p *pods) reattachAllocationPods(ctx *actor.Context, msg reattachAllocation
 				switch env.Name {
 				case "DET_CONTAINER_ID":
 					if !existingConfigMaps.Contains(pod.Name) {
-						p.deleteKubernetesResources(ctx, pods, configMaps)
+						cluster.deleteKubernetesResources(pods, configMaps)
 						ctx.Respond(fmt.Errorf("pod missing config map %s", pod.Name))
 						return nil
 					}
@@ -462,23 +534,23 @@ func (p *pods) reattachAllocationPods(ctx *actor.Context, msg reattachAllocation
 	}
 
 	if len(k8sPods) != msg.numPods {
-		p.deleteKubernetesResources(ctx, pods, configMaps)
+		cluster.deleteKubernetesResources(pods, configMaps)
 		ctx.Respond(fmt.Errorf("not enough pods found for allocation expected %d got %d instead",
 			msg.numPods, len(k8sPods)))
 		return nil
 	}
 
-	if err := p.dontReattachQueuedPreAgentDisabledPods(ctx, pods, configMaps); err != nil {
+	if err := p.dontReattachQueuedPreAgentDisabledPods(cluster, pods, configMaps); err != nil {
 		ctx.Respond(err)
 		return nil
 	}
 
 	var restoreResponses []reattachPodResponse
 	for i, containerID := range containerIDs {
-		resp, err := p.reattachPod(ctx, msg.allocationID, resourcePool, containerID,
+		resp, err := p.reattachPod(ctx, cluster, msg.allocationID, resourcePool, containerID,
 			k8sPods[i], ports[i], msg.slots, msg.logContext)
 		if err != nil {
-			p.deleteKubernetesResources(ctx, pods, configMaps)
+			cluster.deleteKubernetesResources(pods, configMaps)
 			ctx.Respond(errors.Wrapf(err,
 				"error restoring pod with containerID %s", containerID))
 			return nil
@@ -491,7 +563,7 @@ func (p *pods) reattachAllocationPods(ctx *actor.Context, msg reattachAllocation
 }
 
 func (p *pods) dontReattachQueuedPreAgentDisabledPods(
-	ctx *actor.Context, pods *k8sV1.PodList, configMaps *k8sV1.ConfigMapList,
+	cluster *kubernetesCluster, pods *k8sV1.PodList, configMaps *k8sV1.ConfigMapList,
 ) error {
 	// This is needed to label pods created before Determined supported k8s agent enable disable.
 	// We will not reattach pods that are queued and don't have the affinity that respects
@@ -505,7 +577,7 @@ func (p *pods) dontReattachQueuedPreAgentDisabledPods(
 			addNodeDisabledAffinityToPodSpec(&pod, clusterIDNodeLabel())
 
 			if !reflect.DeepEqual(pod.Spec, before.Spec) {
-				p.deleteKubernetesResources(ctx, pods, configMaps)
+				cluster.deleteKubernetesResources(pods, configMaps)
 				return fmt.Errorf(
 					"unable to restore pod %s since it was queued and does not have "+
 						"Determined's affinity to prevent scheduling on disabled nodes. "+
@@ -521,6 +593,7 @@ func (p *pods) dontReattachQueuedPreAgentDisabledPods(
 
 func (p *pods) reattachPod(
 	ctx *actor.Context,
+	cluster *kubernetesCluster,
 	allocationID model.AllocationID,
 	resourcePool string,
 	containerID string,
@@ -539,19 +612,20 @@ func (p *pods) reattachPod(
 		LogContext:   logContext,
 	}
 
+	masterIP, masterPort := cluster.masterAddress(p.masterIP, p.masterPort)
 	newPodHandler := newPod(
 		startMsg,
 		startMsg.Spec.ClusterID,
-		p.clientSet,
+		cluster.clientSet,
 		pod.Namespace,
-		p.masterIP,
-		p.masterPort,
+		masterIP,
+		masterPort,
 		p.masterTLSConfig,
 		p.loggingTLSConfig,
 		p.loggingConfig,
-		p.podInterfaces[pod.Namespace],
-		p.configMapInterfaces[pod.Namespace],
-		p.resourceRequestQueue,
+		cluster.podInterfaces[pod.Namespace],
+		cluster.configMapInterfaces[pod.Namespace],
+		cluster.resourceRequestQueue,
 		p.slotType,
 		p.slotResourceRequests,
 		p.scheduler,
@@ -585,11 +659,32 @@ func (p *pods) reattachPod(
 		return reattachPodResponse{}, fmt.Errorf("reattaching pod: %w", err)
 	}
 
+	if cluster.gangs != nil {
+		// The gang object outlives a master restart, so only its bookkeeping is restored here.
+		gs, ok := p.allocationIDToGang[allocationID]
+		if !ok {
+			gs = &gangState{gang: newGang(allocationID, pod.Namespace, 0, nil), gangs: cluster.gangs}
+			// The gang informer may have already seen the gang before it was registered, so its
+			// admission is looked up rather than waiting on an event that may not come.
+			admitted, err := cluster.gangs.admitted(context.TODO(), pod.Namespace, gs.gang.name)
+			if err != nil {
+				ctx.Log().WithError(err).Warnf("failed to get admission of gang %s", gs.gang.name)
+			}
+			gs.admitted = admitted
+			p.allocationIDToGang[allocationID] = gs
+		}
+		gs.gang.size++
+		gs.pods++
+	}
+
 	p.podNameToPodHandler[pod.Name] = newPodHandler
 	p.podNameToResourcePool[pod.Name] = resourcePool
 	p.containerIDToPodName[containerID] = pod.Name
 	p.podNameToContainerID[pod.Name] = containerID
 	p.containerIDToSchedulingState[containerID] = sproto.SchedulingStateQueued
+	if p.gangAdmitted(allocationID) {
+		p.containerIDToSchedulingState[containerID] = sproto.SchedulingStateScheduled
+	}
 	p.podHandlerToMetadata[newPodHandler] = podMetadata{
 		podName:     pod.Name,
 		containerID: containerID,
@@ -603,35 +698,25 @@ func (p *pods) refreshPodStates(ctx *actor.Context, allocationID model.Allocatio
 		return fmt.Errorf("invalid call: allocationID missing")
 	}
 
-	pods, err := p.listPodsInAllNamespaces(context.TODO(), metaV1.ListOptions{
-		LabelSelector: fmt.Sprintf("%s=%s", determinedLabel, allocationID),
-	})
-	if err != nil {
-		return errors.Wrap(err, "error listing pods checking if they can be restored")
-	}
+	for _, cluster := range p.clusters {
+		pods, err := cluster.listPodsInAllNamespaces(context.TODO(), metaV1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", determinedLabel, allocationID),
+		})
+		if err != nil {
+			return errors.Wrap(err, "error listing pods checking if they can be restored")
+		}
 
-	for _, pod := range pods.Items {
-		if _, ok := p.namespaceToPoolName[pod.Namespace]; !ok {
-			continue
+		for _, pod := range pods.Items {
+			if _, ok := cluster.namespaceToPoolName[pod.Namespace]; !ok {
+				continue
+			}
+			pod := pod
+			p.podStatusCallback(ctx.Self().System(), watch.Event{Object: &pod})
 		}
-		pod := pod
-		p.podStatusCallback(ctx.Self().System(), watch.Event{Object: &pod})
 	}
 	return nil
 }
 
-func (p *pods) deleteKubernetesResources(
-	ctx *actor.Context, pods *k8sV1.PodList, configMaps *k8sV1.ConfigMapList,
-) {
-	for _, pod := range pods.Items {
-		p.resourceRequestQueue.deleteKubernetesResources(pod.Namespace, pod.Name, "")
-	}
-
-	for _, configMap := range configMaps.Items {
-		p.resourceRequestQueue.deleteKubernetesResources(configMap.Namespace, "", configMap.Name)
-	}
-}
-
 func (p *pods) deleteDoomedKubernetesResources(ctx *actor.Context) error {
 	var openAllocations []model.Allocation
 	if err := db.Bun().NewSelect().Model(&openAllocations).
@@ -644,15 +729,26 @@ func (p *pods) deleteDoomedKubernetesResources(ctx *actor.Context) error {
 		openAllocationIDs.Insert(alloc.AllocationID)
 	}
 
+	for _, cluster := range p.clusters {
+		if err := p.deleteDoomedClusterResources(ctx, cluster, openAllocationIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *pods) deleteDoomedClusterResources(
+	ctx *actor.Context, cluster *kubernetesCluster, openAllocationIDs set.Set[model.AllocationID],
+) error {
 	listOptions := metaV1.ListOptions{LabelSelector: determinedLabel}
-	pods, err := p.listPodsInAllNamespaces(context.TODO(), listOptions)
+	pods, err := cluster.listPodsInAllNamespaces(context.TODO(), listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing existing pods")
 	}
 	toKillPods := &k8sV1.PodList{}
 	savedPodNames := make(set.Set[string])
 	for _, pod := range pods.Items {
-		if _, ok := p.namespaceToPoolName[pod.Namespace]; !ok {
+		if _, ok := cluster.namespaceToPoolName[pod.Namespace]; !ok {
 			continue
 		}
 
@@ -683,13 +779,13 @@ func (p *pods) deleteDoomedKubernetesResources(ctx *actor.Context) error {
 		savedPodNames.Insert(pod.Name)
 	}
 
-	configMaps, err := p.listConfigMapsInAllNamespaces(context.TODO(), listOptions)
+	configMaps, err := cluster.listConfigMapsInAllNamespaces(context.TODO(), listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing existing config maps")
 	}
 	toKillConfigMaps := &k8sV1.ConfigMapList{}
 	for _, cm := range configMaps.Items {
-		if _, ok := p.namespaceToPoolName[cm.Namespace]; !ok {
+		if _, ok := cluster.namespaceToPoolName[cm.Namespace]; !ok {
 			continue
 		}
 
@@ -702,93 +798,239 @@ func (p *pods) deleteDoomedKubernetesResources(ctx *actor.Context) error {
 		toKillConfigMaps.Items = append(toKillConfigMaps.Items, cm)
 	}
 
-	p.deleteKubernetesResources(ctx, toKillPods, toKillConfigMaps)
+	cluster.deleteKubernetesResources(toKillPods, toKillConfigMaps)
 	return nil
 }
 
-func (p *pods) startPodInformer(s *actor.System) error {
-	for namespace := range p.namespaceToPoolName {
-		i, err := newPodInformer(
-			context.TODO(),
-			determinedLabel,
-			"pod",
-			namespace,
-			p.podInterfaces[namespace],
-			func(event watch.Event) {
-				p.mu.Lock()
-				defer p.mu.Unlock()
-				p.podStatusCallback(s, event)
-			},
-		)
-		if err != nil {
-			return err
+// labeledClaim is a persistent volume claim and the cluster it lives in.
+type labeledClaim struct {
+	cluster *kubernetesCluster
+	claim   k8sV1.PersistentVolumeClaim
+}
+
+// listLabeledClaims lists the persistent volume claims that carry the given label in every
+// cluster, keyed by the value of the label.
+func (p *pods) listLabeledClaims(ctx context.Context, label string) map[string][]labeledClaim {
+	claims := make(map[string][]labeledClaim)
+	for _, cluster := range p.clusters {
+		for namespace := range cluster.namespaceToPoolName {
+			list, err := cluster.clientSet.CoreV1().PersistentVolumeClaims(namespace).List(
+				ctx, metaV1.ListOptions{LabelSelector: label})
+			if err != nil {
+				p.syslog.WithError(err).Warnf(
+					"unable to list persistent volume claims in %s", namespace)
+				continue
+			}
+			for _, claim := range list.Items {
+				value := claim.Labels[label]
+				claims[value] = append(claims[value], labeledClaim{cluster, claim})
+			}
 		}
+	}
+	return claims
+}
 
-		go i.run(context.TODO())
+// deleteLabeledClaims deletes the given claims, keyed by the value of the label that listed them;
+// owner describes what the value names in the logs.
+func (p *pods) deleteLabeledClaims(
+	ctx context.Context, claims map[string][]labeledClaim, owner string,
+) {
+	for value, cs := range claims {
+		for _, c := range cs {
+			p.syslog.Infof("deleting persistent volume claim '%s' of %s '%s'",
+				c.claim.Name, owner, value)
+			err := c.cluster.clientSet.CoreV1().PersistentVolumeClaims(c.claim.Namespace).Delete(
+				ctx, c.claim.Name, metaV1.DeleteOptions{})
+			if err != nil && !k8error.IsNotFound(err) {
+				p.syslog.WithError(err).Warnf(
+					"failed to delete persistent volume claim %s", c.claim.Name)
+			}
+		}
 	}
-	return nil
 }
 
-func (p *pods) startNodeInformer() error {
-	i, err := newNodeInformer(
-		context.TODO(),
-		p.clientSet.CoreV1().Nodes(),
-		func(event watch.Event) {
-			p.mu.Lock()
-			defer p.mu.Unlock()
-			p.nodeStatusCallback(event)
-		})
-	if err != nil {
-		return err
+// deleteFinishedTaskClaims deletes the task-scoped persistent volume claims of tasks that have
+// finished or been deleted. Claims outlive the allocations of their task, since a later allocation
+// of the task mounts them again, so they are collected here rather than when a pod is cleaned up.
+func (p *pods) deleteFinishedTaskClaims(ctx context.Context) {
+	claimsByTask := p.listLabeledClaims(ctx, determinedTaskLabel)
+	if len(claimsByTask) == 0 {
+		return
+	}
+
+	taskIDs := make([]model.TaskID, 0, len(claimsByTask))
+	for taskID := range claimsByTask {
+		taskIDs = append(taskIDs, model.TaskID(taskID))
+	}
+	var openTaskIDs []model.TaskID
+	if err := db.Bun().NewSelect().Table("tasks").Column("task_id").
+		Where("task_id IN (?)", bun.In(taskIDs)).
+		Where("end_time IS NULL").
+		Scan(ctx, &openTaskIDs); err != nil {
+		p.syslog.WithError(err).Warn("unable to query open tasks of persistent volume claims")
+		return
+	}
+
+	for _, taskID := range openTaskIDs {
+		delete(claimsByTask, string(taskID))
+	}
+	p.deleteLabeledClaims(ctx, claimsByTask, "finished task")
+}
+
+// deleteWorkspaceClaims deletes the workspace-scoped persistent volume claims of workspaces that
+// have been deleted or archived. Unarchiving a workspace doesn't bring the data back; its tasks
+// start from new, empty claims.
+func (p *pods) deleteWorkspaceClaims(ctx context.Context) {
+	claimsByWorkspace := p.listLabeledClaims(ctx, determinedWorkspaceLabel)
+	if len(claimsByWorkspace) == 0 {
+		return
+	}
+
+	workspaceIDs := make([]int, 0, len(claimsByWorkspace))
+	for value := range claimsByWorkspace {
+		workspaceID, err := strconv.Atoi(value)
+		if err != nil {
+			// Claims we didn't label ourselves are left alone.
+			delete(claimsByWorkspace, value)
+			continue
+		}
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+	if len(workspaceIDs) == 0 {
+		return
+	}
+	var activeWorkspaceIDs []int
+	if err := db.Bun().NewSelect().Model((*model.Workspace)(nil)).Column("id").
+		Where("id IN (?)", bun.In(workspaceIDs)).
+		Where("NOT archived").
+		Scan(ctx, &activeWorkspaceIDs); err != nil {
+		p.syslog.WithError(err).Warn("unable to query workspaces of persistent volume claims")
+		return
+	}
+
+	for _, workspaceID := range activeWorkspaceIDs {
+		delete(claimsByWorkspace, strconv.Itoa(workspaceID))
 	}
+	p.deleteLabeledClaims(ctx, claimsByWorkspace, "workspace")
+}
+
+func (p *pods) startPodInformer(s *actor.System) error {
+	for _, c := range p.clusters {
+		for namespace := range c.namespaceToPoolName {
+			i, err := newPodInformer(
+				context.TODO(),
+				determinedLabel,
+				"pod",
+				namespace,
+				c.podInterfaces[namespace],
+				func(event watch.Event) {
+					p.mu.Lock()
+					defer p.mu.Unlock()
+					p.podStatusCallback(s, event)
+				},
+			)
+			if err != nil {
+				return err
+			}
 
-	go i.run(context.TODO())
+			go i.run(context.TODO())
+		}
+	}
 	return nil
 }
 
-func (p *pods) startEventListeners(s *actor.System) error {
-	for namespace := range p.namespaceToPoolName {
-		l, err := newEventInformer(
+func (p *pods) startNodeInformer() error {
+	for _, c := range p.clusters {
+		c := c
+		i, err := newNodeInformer(
 			context.TODO(),
-			p.clientSet.CoreV1().Events(namespace),
-			namespace,
+			c.clientSet.CoreV1().Nodes(),
 			func(event watch.Event) {
 				p.mu.Lock()
 				defer p.mu.Unlock()
-				p.eventStatusCallback(s, event)
+				p.nodeStatusCallback(c, event)
 			})
 		if err != nil {
 			return err
 		}
-		go l.run(context.TODO())
+
+		go i.run(context.TODO())
+	}
+	return nil
+}
+
+func (p *pods) startEventListeners(s *actor.System) error {
+	for _, c := range p.clusters {
+		for namespace := range c.namespaceToPoolName {
+			l, err := newEventInformer(
+				context.TODO(),
+				c.clientSet.CoreV1().Events(namespace),
+				namespace,
+				func(event watch.Event) {
+					p.mu.Lock()
+					defer p.mu.Unlock()
+					p.eventStatusCallback(s, event)
+				})
+			if err != nil {
+				return err
+			}
+			go l.run(context.TODO())
+		}
 	}
 	return nil
 }
 
 func (p *pods) startPreemptionListeners(s *actor.System) error {
-	for namespace := range p.namespaceToPoolName {
-		l, err := newPodInformer(
-			context.TODO(),
-			determinedPreemptionLabel,
-			"preemption",
-			namespace,
-			p.clientSet.CoreV1().Pods(namespace),
-			func(event watch.Event) {
-				p.mu.Lock()
-				defer p.mu.Unlock()
-				p.preemptionCallback(s, event)
-			})
-		if err != nil {
-			return err
+	for _, c := range p.clusters {
+		for namespace := range c.namespaceToPoolName {
+			l, err := newPodInformer(
+				context.TODO(),
+				determinedPreemptionLabel,
+				"preemption",
+				namespace,
+				c.clientSet.CoreV1().Pods(namespace),
+				func(event watch.Event) {
+					p.mu.Lock()
+					defer p.mu.Unlock()
+					p.preemptionCallback(s, event)
+				})
+			if err != nil {
+				return err
+			}
+			go l.run(context.TODO())
+		}
+	}
+	return nil
+}
+
+func (p *pods) startGangInformers(s *actor.System) error {
+	for _, c := range p.clusters {
+		for namespace := range c.namespaceToPoolName {
+			i, err := newGangInformer(
+				context.TODO(),
+				c.gangs.resourceInterface(namespace),
+				namespace,
+				func(event watch.Event) {
+					p.mu.Lock()
+					defer p.mu.Unlock()
+					p.gangStatusCallback(s, event)
+				})
+			if err != nil {
+				return errors.Wrapf(err, "starting %s informer, is %s installed?",
+					p.gangScheduler.resource().Resource, p.gangSchedulerConfig.Type)
+			}
+			go i.run(context.TODO())
 		}
-		go l.run(context.TODO())
 	}
 	return nil
 }
 
 func (p *pods) startResourceRequestQueue(ctx *actor.Context) {
 	failures := make(chan resourcesRequestFailure, 16)
-	p.resourceRequestQueue = startRequestQueue(p.podInterfaces, p.configMapInterfaces, failures)
+	for _, c := range p.clusters {
+		c.resourceRequestQueue = startRequestQueue(
+			c.podInterfaces, c.configMapInterfaces, c.claimInterfaces, failures)
+	}
 	p.wg.Go(func(ctx context.Context) {
 		for {
 			select {
@@ -830,19 +1072,21 @@ func (p *pods) handleResourceRequestFailure(msg resourcesRequestFailure) {
 }
 
 func (p *pods) receiveStartTaskPod(ctx *actor.Context, msg StartTaskPod) error {
+	cluster := p.clusterForPool(msg.ResourcePool)
+	masterIP, masterPort := cluster.masterAddress(p.masterIP, p.masterPort)
 	newPodHandler := newPod(
 		msg,
 		msg.Spec.ClusterID,
-		p.clientSet,
+		cluster.clientSet,
 		msg.Namespace,
-		p.masterIP,
-		p.masterPort,
+		masterIP,
+		masterPort,
 		p.masterTLSConfig,
 		p.loggingTLSConfig,
 		p.loggingConfig,
-		p.podInterfaces[msg.Namespace],
-		p.configMapInterfaces[msg.Namespace],
-		p.resourceRequestQueue,
+		cluster.podInterfaces[msg.Namespace],
+		cluster.configMapInterfaces[msg.Namespace],
+		cluster.resourceRequestQueue,
 		p.slotType,
 		p.slotResourceRequests,
 		p.scheduler,
@@ -853,6 +1097,16 @@ func (p *pods) receiveStartTaskPod(ctx *actor.Context, msg StartTaskPod) error {
 			"attempting to register same pod name: %s multiple times", newPodHandler.podName)
 	}
 
+	if cluster.gangs != nil {
+		gs, err := p.getOrCreateGang(
+			cluster.gangs, msg, newPodHandler.configureResourcesRequirements().Requests)
+		if err != nil {
+			return err
+		}
+		newPodHandler.gang = &gs.gang
+		newPodHandler.gangScheduler = p.gangScheduler
+	}
+
 	err := newPodHandler.start()
 	if err != nil {
 		return fmt.Errorf("creating pod: %w", err)
@@ -904,7 +1158,7 @@ func (p *pods) podStatusCallback(s *actor.System, event watch.Event) {
 	if containerID, ok := p.podNameToContainerID[pod.Name]; ok {
 		if state, ok := p.containerIDToSchedulingState[containerID]; ok {
 			currState := sproto.SchedulingStateQueued
-			if pod.Status.Phase == "Running" {
+			if pod.Status.Phase == "Running" || p.gangAdmitted(podHandler.allocationID) {
 				currState = sproto.SchedulingStateScheduled
 			}
 			if currState != state {
@@ -918,6 +1172,90 @@ func (p *pods) podStatusCallback(s *actor.System, event watch.Event) {
 	}
 }
 
+// getOrCreateGang returns the gang for the allocation of a pod that is starting, creating the
+// gang in the external gang scheduler for the first pod of the allocation.
+func (p *pods) getOrCreateGang(
+	gangs *gangManager, msg StartTaskPod, podResources k8sV1.ResourceList,
+) (*gangState, error) {
+	if gs, ok := p.allocationIDToGang[msg.AllocationID]; ok {
+		gs.pods++
+		return gs, nil
+	}
+
+	numPods := msg.NumPods
+	if numPods < 1 {
+		numPods = 1
+	}
+	g := newGang(msg.AllocationID, msg.Namespace, numPods, podResources)
+	if err := gangs.create(context.TODO(), g); err != nil {
+		return nil, err
+	}
+
+	gs := &gangState{gang: g, gangs: gangs, pods: 1}
+	p.allocationIDToGang[msg.AllocationID] = gs
+	return gs, nil
+}
+
+func (p *pods) gangAdmitted(allocationID model.AllocationID) bool {
+	gs, ok := p.allocationIDToGang[allocationID]
+	return ok && gs.admitted
+}
+
+func (p *pods) gangByName(namespace, name string) (model.AllocationID, *gangState) {
+	for allocationID, gs := range p.allocationIDToGang {
+		if gs.gang.namespace == namespace && gs.gang.name == name {
+			return allocationID, gs
+		}
+	}
+	return "", nil
+}
+
+// gangStatusCallback reflects the admission of a gang by the external gang scheduler into the
+// scheduling state of its pods, so that the job queue shows the allocation as scheduled while
+// its pods are still being placed.
+func (p *pods) gangStatusCallback(s *actor.System, event watch.Event) {
+	obj, ok := event.Object.(*unstructured.Unstructured)
+	if !ok {
+		p.syslog.Warnf("error converting event of type %T to a gang: %+v", event, event)
+		return
+	}
+
+	allocationID, gs := p.gangByName(obj.GetNamespace(), obj.GetName())
+	if gs == nil {
+		p.syslog.Debugf("received gang status update for un-registered gang %s", obj.GetName())
+		return
+	}
+
+	admitted := event.Type != watch.Deleted && gs.gangs.scheduler.admitted(obj)
+	if admitted == gs.admitted {
+		return
+	}
+	gs.admitted = admitted
+	p.syslog.Infof("gang %s admitted: %t", gs.gang.name, admitted)
+
+	for podHandler, metadata := range p.podHandlerToMetadata {
+		if podHandler.allocationID != allocationID {
+			continue
+		}
+		state, ok := p.containerIDToSchedulingState[metadata.containerID]
+		if !ok {
+			continue
+		}
+
+		currState := sproto.SchedulingStateQueued
+		if admitted || (podHandler.pod != nil && podHandler.pod.Status.Phase == "Running") {
+			currState = sproto.SchedulingStateScheduled
+		}
+		if currState != state {
+			p.containerIDToSchedulingState[metadata.containerID] = currState
+			s.Tell(p.cluster, sproto.UpdatePodStatus{
+				ContainerID: metadata.containerID,
+				State:       currState,
+			})
+		}
+	}
+}
+
 var clusterID string
 
 func setClusterID(s string) {
@@ -937,7 +1275,7 @@ const (
 )
 
 func (p *pods) enableNode(
-	ctx *actor.Context, nodeName string,
+	ctx *actor.Context, agentID string,
 ) (*apiv1.EnableAgentResponse, error) {
 	patch := []byte(fmt.Sprintf(`{
 		"metadata": {
@@ -947,7 +1285,12 @@ func (p *pods) enableNode(
 		}
 	}`, clusterIDNodeLabel()))
 
-	_, err := p.clientSet.CoreV1().Nodes().
+	c, node, err := p.clusterForNode(agentID)
+	if err != nil {
+		return nil, err
+	}
+	nodeName := node.name
+	_, err = c.clientSet.CoreV1().Nodes().
 		Patch(context.TODO(), nodeName, types.StrategicMergePatchType, patch, metaV1.PatchOptions{})
 	if k8error.IsForbidden(err) {
 		return nil, fmt.Errorf("the Determined master Kubernetes service account " +
@@ -961,7 +1304,7 @@ func (p *pods) enableNode(
 	}
 	p.syslog.Infof("node %s enabled by an user", nodeName)
 
-	n, ok := p.summarizeClusterByNodes(ctx)[nodeName]
+	n, ok := p.summarizeClusterByNodes(ctx)[agentID]
 	if !ok {
 		return nil, fmt.Errorf("node %s enabled without error, error getting node summary", nodeName)
 	}
@@ -980,7 +1323,7 @@ func (p *pods) enableNode(
 }
 
 func (p *pods) disableNode(
-	ctx *actor.Context, nodeName string, shouldDrain bool,
+	ctx *actor.Context, agentID string, shouldDrain bool,
 ) (*apiv1.DisableAgentResponse, error) {
 	labelValue := noExecuteNodeLabelValue
 	if shouldDrain {
@@ -995,7 +1338,12 @@ func (p *pods) disableNode(
 		return nil, fmt.Errorf("marshaling JSON patch %v: %s", patchStruct, err)
 	}
 
-	_, err = p.clientSet.CoreV1().Nodes().
+	c, node, err := p.clusterForNode(agentID)
+	if err != nil {
+		return nil, err
+	}
+	nodeName := node.name
+	_, err = c.clientSet.CoreV1().Nodes().
 		Patch(context.TODO(), nodeName, types.StrategicMergePatchType, patch, metaV1.PatchOptions{})
 	if k8error.IsForbidden(err) {
 		return nil, fmt.Errorf("the Determined master Kubernetes service account " +
@@ -1010,13 +1358,13 @@ func (p *pods) disableNode(
 	p.syslog.Infof("node %s disabled by an user", nodeName)
 
 	if !shouldDrain { // See note in spec.go about how we could remove killing all pods here.
-		if err := p.releaseAllocationsOnDisabledNode(ctx, nodeName); err != nil {
+		if err := p.releaseAllocationsOnDisabledNode(ctx, c, nodeName); err != nil {
 			return nil, fmt.Errorf(
 				"node disabled without error, error killing existing pod on node: %w", err)
 		}
 	}
 
-	n, ok := p.summarizeClusterByNodes(ctx)[nodeName]
+	n, ok := p.summarizeClusterByNodes(ctx)[agentID]
 	if !ok {
 		return nil, fmt.Errorf("node %s disabled without error, error getting node summary", nodeName)
 	}
@@ -1034,12 +1382,14 @@ func (p *pods) disableNode(
 	}, nil
 }
 
-func (p *pods) releaseAllocationsOnDisabledNode(ctx *actor.Context, nodeName string) error {
+func (p *pods) releaseAllocationsOnDisabledNode(
+	ctx *actor.Context, c *kubernetesCluster, nodeName string,
+) error {
 	listOptions := metaV1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s", determinedLabel),
 		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
 	}
-	pods, err := p.listPodsInAllNamespaces(context.TODO(), listOptions)
+	pods, err := c.listPodsInAllNamespaces(context.TODO(), listOptions)
 	if err != nil {
 		return fmt.Errorf("listing pods on node %s: %w", nodeName, err)
 	}
@@ -1069,7 +1419,7 @@ func (p *pods) releaseAllocationsOnDisabledNode(ctx *actor.Context, nodeName str
 	return nil
 }
 
-func (p *pods) nodeStatusCallback(event watch.Event) {
+func (p *pods) nodeStatusCallback(c *kubernetesCluster, event watch.Event) {
 	node, ok := event.Object.(*k8sV1.Node)
 	if !ok {
 		p.syslog.Warnf("error converting event of type %T to *k8sV1.Node: %+v", event, event)
@@ -1079,13 +1429,14 @@ func (p *pods) nodeStatusCallback(event watch.Event) {
 	p.syslog.Debugf(`informer got new node event for node '%s': %s %s`,
 		node.Name, event.Type, node.Status.Phase)
 
+	n := clusterNode{cluster: c.name, name: node.Name}
 	switch event.Type {
 	case watch.Added:
-		p.currentNodes[node.Name] = node
+		p.currentNodes[n] = node
 	case watch.Modified:
-		p.currentNodes[node.Name] = node
+		p.currentNodes[n] = node
 	case watch.Deleted:
-		delete(p.currentNodes, node.Name)
+		delete(p.currentNodes, n)
 	default:
 	}
 }
@@ -1212,10 +1563,22 @@ func (p *pods) cleanUpPodHandler(podHandler *pod) error {
 	delete(p.containerIDToSchedulingState, podInfo.containerID)
 	delete(p.podHandlerToMetadata, podHandler)
 
+	if gs, ok := p.allocationIDToGang[podHandler.allocationID]; ok {
+		gs.pods--
+		if gs.pods <= 0 {
+			delete(p.allocationIDToGang, podHandler.allocationID)
+			p.wg.Go(func(ctx context.Context) {
+				if err := gs.gangs.delete(ctx, gs.gang); err != nil {
+					p.syslog.WithError(err).Warnf("failed to delete gang %s", gs.gang.name)
+				}
+			})
+		}
+	}
+
 	// launch this work async, since we hold the lock and it does API calls.
 	p.wg.Go(func(ctx context.Context) {
 		name := fmt.Sprintf("%s-priorityclass", podInfo.containerID)
-		err := p.clientSet.
+		err := podHandler.clientSet.
 			SchedulingV1().
 			PriorityClasses().
 			Delete(ctx, name, metaV1.DeleteOptions{})
@@ -1276,7 +1639,7 @@ func (p *pods) summarize(ctx *actor.Context) (map[string]model.AgentSummary, err
 
 // Get the mapping of many-to-many relationship between nodes and resource pools.
 func (p *pods) getNodeResourcePoolMapping(nodeSummaries map[string]model.AgentSummary) (
-	map[string][]*k8sV1.Node, map[string][]string,
+	map[string][]clusterNode, map[string][]string,
 ) {
 	poolTaskContainerDefaults := extractTCDs(p.resourcePoolConfigs)
 
@@ -1288,13 +1651,18 @@ func (p *pods) getNodeResourcePoolMapping(nodeSummaries map[string]model.AgentSu
 		Operator: k8sV1.TolerationOpEqual,
 	}}
 	cpuTolerations, gpuTolerations := extractTolerations(p.baseContainerDefaults)
-	poolsToNodes := make(map[string][]*k8sV1.Node, len(p.namespaceToPoolName))
-	nodesToPools := make(map[string][]string, len(p.namespaceToPoolName))
+	poolsToNodes := make(map[string][]clusterNode, len(p.poolToCluster))
+	nodesToPools := make(map[string][]string, len(p.poolToCluster))
 
-	for _, node := range p.currentNodes {
-		_, slotType := extractSlotInfo(nodeSummaries[node.Name])
+	for n, node := range p.currentNodes {
+		_, slotType := extractSlotInfo(nodeSummaries[n.agentID()])
 
 		for poolName, tcd := range poolTaskContainerDefaults {
+			// A pool only has the nodes of the cluster it launches pods into.
+			if n.cluster != p.clusterForPool(poolName).name {
+				continue
+			}
+
 			var poolTolerations []k8sV1.Toleration
 
 			// If they're using the default RP config, use the default tolerations.
@@ -1320,8 +1688,8 @@ func (p *pods) getNodeResourcePoolMapping(nodeSummaries map[string]model.AgentSu
 
 			// If all of a node's taints are tolerated by a pool, that node belongs to the pool.
 			if allTaintsTolerated(node.Spec.Taints, poolTolerations) {
-				poolsToNodes[poolName] = append(poolsToNodes[poolName], node)
-				nodesToPools[node.Name] = append(nodesToPools[node.Name], poolName)
+				poolsToNodes[poolName] = append(poolsToNodes[poolName], n)
+				nodesToPools[n.agentID()] = append(nodesToPools[n.agentID()], poolName)
 			}
 		}
 	}
@@ -1337,7 +1705,7 @@ func (p *pods) computeSummary(ctx *actor.Context) (map[string]model.AgentSummary
 
 	// Build the set of summaries for each resource pool
 	containers := p.containersPerResourcePool()
-	summaries := make(map[string]model.AgentSummary, len(p.namespaceToPoolName))
+	summaries := make(map[string]model.AgentSummary, len(p.poolToCluster))
 	for poolName, nodes := range poolsToNodes {
 		slots := model.SlotsSummary{}
 		numContainersInPool := containers[poolName]
@@ -1347,10 +1715,10 @@ func (p *pods) computeSummary(ctx *actor.Context) (map[string]model.AgentSummary
 		pseudoContainersAdded := 0
 
 		for _, node := range nodes {
-			numSlots, slotType := extractSlotInfo(nodeSummaries[node.Name])
+			numSlots, slotType := extractSlotInfo(nodeSummaries[node.agentID()])
 
 			for j := 0; j < numSlots; j++ {
-				id := fmt.Sprintf("%s/%s/%s/%d", poolName, node.Name, string(slotType), j)
+				id := fmt.Sprintf("%s/%s/%s/%d", poolName, node.agentID(), string(slotType), j)
 
 				var container *cproto.Container
 				if pseudoContainersAdded < numContainersInPool {
@@ -1383,25 +1751,23 @@ func (p *pods) computeSummary(ctx *actor.Context) (map[string]model.AgentSummary
 }
 
 func (p *pods) summarizeClusterByNodes(ctx *actor.Context) map[string]model.AgentSummary {
-	var results []podNodeInfo
-	for _, p := range p.podNameToPodHandler {
-		results = append(results, p.getPodNodeInfo())
-	}
-
 	// Separate pods by nodes.
-	podByNode := make(map[string][]podNodeInfo, len(results))
-	for _, info := range results {
+	podByNode := make(map[clusterNode][]podNodeInfo, len(p.podNameToPodHandler))
+	for podName, podHandler := range p.podNameToPodHandler {
+		info := podHandler.getPodNodeInfo()
 		if len(info.nodeName) == 0 {
 			// If a pod doesn't have a nodeName it means it has not yet
 			// been allocated to a node.
 			continue
 		}
-		podByNode[info.nodeName] = append(podByNode[info.nodeName], info)
+		c := p.clusterForPool(p.podNameToResourcePool[podName])
+		n := clusterNode{cluster: c.name, name: info.nodeName}
+		podByNode[n] = append(podByNode[n], info)
 	}
 
 	nodeToTasks, taskSlots := p.getNonDetSlots(p.slotType)
 	summary := make(map[string]model.AgentSummary, len(p.currentNodes))
-	for _, node := range p.currentNodes {
+	for n, node := range p.currentNodes {
 		disabledLabel, isDisabled := node.Labels[clusterIDNodeLabel()]
 		isDraining := isDisabled && disabledLabel == noScheduleNodeLabelValue
 
@@ -1410,7 +1776,7 @@ func (p *pods) summarizeClusterByNodes(ctx *actor.Context) map[string]model.Agen
 		switch p.slotType {
 		case device.CPU:
 			resources := node.Status.Allocatable["cpu"]
-			milliCPUs := resources.MilliValue() - p.nodeToSystemResourceRequests[node.Name]
+			milliCPUs := resources.MilliValue() - p.nodeToSystemResourceRequests[n]
 			numSlots = int64(float32(milliCPUs) / (1000. * p.slotResourceRequests.CPU))
 			deviceType = device.CPU
 		case device.ROCM:
@@ -1429,7 +1795,7 @@ func (p *pods) summarizeClusterByNodes(ctx *actor.Context) map[string]model.Agen
 
 		slotsSummary := make(model.SlotsSummary)
 		curSlot := 0
-		for _, podInfo := range podByNode[node.Name] {
+		for _, podInfo := range podByNode[n] {
 			for i := 0; i < podInfo.numSlots; i++ {
 				if curSlot >= int(numSlots) {
 					ctx.Log().Warnf("too many pods mapping to node %s", node.Name)
@@ -1447,7 +1813,7 @@ func (p *pods) summarizeClusterByNodes(ctx *actor.Context) map[string]model.Agen
 			}
 		}
 
-		for _, taskName := range nodeToTasks[node.Name] {
+		for _, taskName := range nodeToTasks[n] {
 			for i := int64(0); i < taskSlots[taskName]; i++ {
 				if curSlot >= int(numSlots) {
 					ctx.Log().Warnf("too many pods mapping to node %s", node.Name)
@@ -1484,11 +1850,11 @@ func (p *pods) summarizeClusterByNodes(ctx *actor.Context) map[string]model.Agen
 			addrs = append(addrs, addr.Address)
 		}
 
-		summary[node.Name] = model.AgentSummary{
-			ID:             node.Name,
+		summary[n.agentID()] = model.AgentSummary{
+			ID:             n.agentID(),
 			RegisteredTime: node.ObjectMeta.CreationTimestamp.Time,
 			Slots:          slotsSummary,
-			NumContainers:  len(podByNode[node.Name]) + len(nodeToTasks[node.Name]),
+			NumContainers:  len(podByNode[n]) + len(nodeToTasks[n]),
 			ResourcePool:   []string{""},
 			Addresses:      addrs,
 			Draining:       isDraining,
@@ -1499,49 +1865,57 @@ func (p *pods) summarizeClusterByNodes(ctx *actor.Context) map[string]model.Agen
 	return summary
 }
 
-func (p *pods) getNonDetPods() []k8sV1.Pod {
-	var nonDetPods []k8sV1.Pod
-	pList, err := p.clientSet.CoreV1().Pods("default").List(context.TODO(), metaV1.ListOptions{})
-	if err != nil {
-		return nonDetPods
-	}
-	for _, p := range pList.Items {
-		if _, ok := p.Labels["determined"]; !ok {
-			if p.Spec.NodeName != "" {
-				nonDetPods = append(nonDetPods, p)
+// getNonDetPods returns the pods not launched by Determined, by the node they run on.
+func (p *pods) getNonDetPods() map[clusterNode][]k8sV1.Pod {
+	nonDetPods := make(map[clusterNode][]k8sV1.Pod)
+	for _, c := range p.clusters {
+		pList, err := c.clientSet.CoreV1().Pods("default").List(context.TODO(), metaV1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		for _, p := range pList.Items {
+			if _, ok := p.Labels["determined"]; !ok {
+				if p.Spec.NodeName != "" {
+					n := clusterNode{cluster: c.name, name: p.Spec.NodeName}
+					nonDetPods[n] = append(nonDetPods[n], p)
+				}
 			}
 		}
 	}
 	return nonDetPods
 }
 
-func (p *pods) getNonDetSlots(deviceType device.Type) (map[string][]string, map[string]int64) {
-	nodeToTasks := make(map[string][]string, len(p.currentNodes))
+func (p *pods) getNonDetSlots(
+	deviceType device.Type,
+) (map[clusterNode][]string, map[string]int64) {
+	nodeToTasks := make(map[clusterNode][]string, len(p.currentNodes))
 	taskSlots := make(map[string]int64)
 
 	nonDetPods := p.getNonDetPods()
 	if len(nonDetPods) == 0 {
 		return nodeToTasks, taskSlots
 	}
-	for _, node := range p.currentNodes {
-		nodeToTasks[node.Name] = []string{}
+	for n := range p.currentNodes {
+		nodeToTasks[n] = []string{}
 	}
 
-	for _, pod := range nonDetPods {
-		if _, ok := nodeToTasks[pod.Spec.NodeName]; !ok {
+	for n, nodePods := range nonDetPods {
+		if _, ok := nodeToTasks[n]; !ok {
 			continue
 		}
-		reqs := int64(0)
-		for _, c := range pod.Spec.Containers {
-			if deviceType == device.CPU {
-				reqs += p.getCPUReqs(c)
-			} else if deviceType == device.CUDA {
-				reqs += c.Resources.Requests.Name(ResourceTypeNvidia, resource.DecimalSI).Value()
+		for _, pod := range nodePods {
+			reqs := int64(0)
+			for _, c := range pod.Spec.Containers {
+				if deviceType == device.CPU {
+					reqs += p.getCPUReqs(c)
+				} else if deviceType == device.CUDA {
+					reqs += c.Resources.Requests.Name(ResourceTypeNvidia, resource.DecimalSI).Value()
+				}
+			}
+			if reqs > 0 {
+				nodeToTasks[n] = append(nodeToTasks[n], pod.Name)
+				taskSlots[pod.Name] = reqs
 			}
-		}
-		if reqs > 0 {
-			nodeToTasks[pod.Spec.NodeName] = append(nodeToTasks[pod.Spec.NodeName], pod.Name)
-			taskSlots[pod.Name] = reqs
 		}
 	}
 	return nodeToTasks, taskSlots
@@ -1554,7 +1928,7 @@ func (p *pods) getCPUReqs(c k8sV1.Container) int64 {
 }
 
 func (p *pods) containersPerResourcePool() map[string]int {
-	counts := make(map[string]int, len(p.namespaceToPoolName))
+	counts := make(map[string]int, len(p.poolToCluster))
 	for _, pool := range p.podNameToResourcePool {
 		counts[pool]++
 	}
@@ -1574,38 +1948,6 @@ func numSlots(slots model.SlotsSummary) int {
 	return slotCountsByType[device.CPU]
 }
 
-func (p *pods) listPodsInAllNamespaces(
-	ctx context.Context, opts metaV1.ListOptions,
-) (*k8sV1.PodList, error) {
-	res := &k8sV1.PodList{}
-	for n, i := range p.podInterfaces {
-		pods, err := i.List(ctx, opts)
-		if err != nil {
-			return nil, errors.Wrapf(err, "error listing pods for namespace %s", n)
-		}
-
-		res.Items = append(res.Items, pods.Items...)
-	}
-
-	return res, nil
-}
-
-func (p *pods) listConfigMapsInAllNamespaces(
-	ctx context.Context, opts metaV1.ListOptions,
-) (*k8sV1.ConfigMapList, error) {
-	res := &k8sV1.ConfigMapList{}
-	for n, i := range p.configMapInterfaces {
-		cms, err := i.List(ctx, opts)
-		if err != nil {
-			return nil, errors.Wrapf(err, "error listing config maps for namespace %s", n)
-		}
-
-		res.Items = append(res.Items, cms.Items...)
-	}
-
-	return res, nil
-}
-
 func extractTCDs(resourcePoolConfigs []config.ResourcePoolConfig,
 ) map[string]*model.TaskContainerDefaultsConfig {
 	result := map[string]*model.TaskContainerDefaultsConfig{}
@@ -21,6 +21,7 @@ type (
 	createKubernetesResources struct {
 		podSpec       *k8sV1.Pod
 		configMapSpec *k8sV1.ConfigMap
+		claimSpecs    []*k8sV1.PersistentVolumeClaim
 	}
 
 	deleteKubernetesResources struct {
@@ -71,11 +72,11 @@ type queuedResourceRequest struct {
 // There are two reasons a queue system is required as opposed to allowing the pod routines
 // to create and delete Kubernetes resources asynchronously themselves:
 //
-//  1. Each pod creation first requires the creation of a configMap, however creating the two
-//     is not an atomic operation. If there is a large number of concurrent creation requests
-//     (e.g., a large HP search experiment) the kubernetes API server ends up processing the
-//     creation of all the configMaps before starting to create pods, which adds significant
-//     latency to the creation of pods.
+//  1. Each pod creation first requires the creation of its persistent volume claims and a
+//     configMap, however creating these is not an atomic operation. If there is a large number
+//     of concurrent creation requests (e.g., a large HP search experiment) the kubernetes API
+//     server ends up processing the creation of all the configMaps before starting to create
+//     pods, which adds significant latency to the creation of pods.
 //
 //  2. If all creation and deletion requests are submitted asynchronously, it is possible the
 //     Kubernetes API server will temporarily become saturated, and be slower to respond to other
@@ -103,6 +104,7 @@ type queuedResourceRequest struct {
 type requestQueue struct {
 	podInterfaces       map[string]typedV1.PodInterface
 	configMapInterfaces map[string]typedV1.ConfigMapInterface
+	claimInterfaces     map[string]typedV1.PersistentVolumeClaimInterface
 	failures            chan<- resourcesRequestFailure
 
 	mu         sync.Mutex
@@ -122,11 +124,13 @@ type requestID string
 func startRequestQueue(
 	podInterfaces map[string]typedV1.PodInterface,
 	configMapInterfaces map[string]typedV1.ConfigMapInterface,
+	claimInterfaces map[string]typedV1.PersistentVolumeClaimInterface,
 	failures chan<- resourcesRequestFailure,
 ) *requestQueue {
 	r := &requestQueue{
 		podInterfaces:       podInterfaces,
 		configMapInterfaces: configMapInterfaces,
+		claimInterfaces:     claimInterfaces,
 		failures:            failures,
 
 		workerChan: make(chan interface{}),
@@ -148,6 +152,7 @@ func (r *requestQueue) startWorkers() {
 		startRequestProcessingWorker(
 			r.podInterfaces,
 			r.configMapInterfaces,
+			r.claimInterfaces,
 			strconv.Itoa(i),
 			r.workerChan,
 			r.workerReady,
@@ -179,11 +184,12 @@ func keyForDelete(msg deleteKubernetesResources) requestID {
 func (r *requestQueue) createKubernetesResources(
 	podSpec *k8sV1.Pod,
 	configMapSpec *k8sV1.ConfigMap,
+	claimSpecs []*k8sV1.PersistentVolumeClaim,
 ) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	msg := createKubernetesResources{podSpec, configMapSpec}
+	msg := createKubernetesResources{podSpec, configMapSpec, claimSpecs}
 	ref := keyForCreate(msg)
 
 	if _, requestAlreadyExists := r.pendingResourceCreations[ref]; requestAlreadyExists {
@@ -91,7 +91,7 @@ func (m *mockPod) create() {
 		Name:      m.name,
 		Namespace: "default",
 	}}
-	m.requestQueue.createKubernetesResources(&podSpec, &cmSpec)
+	m.requestQueue.createKubernetesResources(&podSpec, &cmSpec, nil)
 }
 
 func (m *mockPod) delete() {
@@ -139,6 +139,7 @@ func TestRequestQueueCreatingManyPod(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -163,6 +164,7 @@ func TestRequestQueueCreatingAndDeletingManyPod(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -189,6 +191,7 @@ func TestRequestQueueCreatingThenDeletingManyPods(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -222,6 +225,7 @@ func TestRequestQueueCreatingAndDeletingManyPodWithDelay(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -251,6 +255,7 @@ func TestRequestQueueCreationCancelled(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -290,6 +295,7 @@ func TestRequestQueueCreationFailed(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -326,6 +332,7 @@ func TestRequestQueueDeletionFailed(t *testing.T) {
 	k8sRequestQueue := startRequestQueue(
 		map[string]typedV1.PodInterface{"default": podInterface},
 		map[string]typedV1.ConfigMapInterface{"default": configMapInterface},
+		nil,
 		failures,
 	)
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	k8error "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
@@ -13,6 +14,7 @@ import (
 type requestProcessingWorker struct {
 	podInterfaces       map[string]typedV1.PodInterface
 	configMapInterfaces map[string]typedV1.ConfigMapInterface
+	claimInterfaces     map[string]typedV1.PersistentVolumeClaimInterface
 	failures            chan<- resourcesRequestFailure
 	syslog              *logrus.Entry
 }
@@ -22,6 +24,7 @@ type readyCallbackFunc func(createRef requestID)
 func startRequestProcessingWorker(
 	podInterfaces map[string]typedV1.PodInterface,
 	configMapInterfaces map[string]typedV1.ConfigMapInterface,
+	claimInterfaces map[string]typedV1.PersistentVolumeClaimInterface,
 	id string,
 	in <-chan interface{},
 	ready readyCallbackFunc,
@@ -31,6 +34,7 @@ func startRequestProcessingWorker(
 	r := &requestProcessingWorker{
 		podInterfaces:       podInterfaces,
 		configMapInterfaces: configMapInterfaces,
+		claimInterfaces:     claimInterfaces,
 		failures:            failures,
 		syslog:              syslog,
 	}
@@ -59,6 +63,24 @@ func (r *requestProcessingWorker) receive(in <-chan interface{}, ready readyCall
 func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 	msg createKubernetesResources,
 ) {
+	// Claims that already exist, because another pod or an earlier allocation of the task or
+	// workspace created them, are reused.
+	for _, claim := range msg.claimSpecs {
+		r.syslog.Debugf("creating persistent volume claim with spec %v", claim)
+		_, err := r.claimInterfaces[claim.Namespace].Create(
+			context.TODO(), claim, metaV1.CreateOptions{})
+		switch {
+		case k8error.IsAlreadyExists(err):
+			r.syslog.Debugf("reusing persistent volume claim %s", claim.Name)
+		case err != nil:
+			r.syslog.WithError(err).Errorf("error creating persistent volume claim %s", claim.Name)
+			r.failures <- resourceCreationFailed{podName: msg.podSpec.Name, err: err}
+			return
+		default:
+			r.syslog.Infof("created persistent volume claim %s", claim.Name)
+		}
+	}
+
 	r.syslog.Debugf("creating configMap with spec %v", msg.configMapSpec)
 	configMap, err := r.configMapInterfaces[msg.podSpec.Namespace].Create(
 		context.TODO(), msg.configMapSpec, metaV1.CreateOptions{})
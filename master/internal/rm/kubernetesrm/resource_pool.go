@@ -545,6 +545,7 @@ func (k *kubernetesResourcePool) createResources(
 			podsActor:       k.podsActor,
 			containerID:     cproto.NewID(),
 			slots:           slotsPerPod,
+			numPods:         numPods,
 			group:           k.groups[req.Group],
 			initialPosition: k.queuePositions[k.allocationIDToJobID[req.AllocationID]],
 			namespace:       k.poolConfig.KubernetesNamespace,
@@ -557,6 +558,7 @@ func (k *kubernetesResourcePool) restoreResources(
 	ctx *actor.Context, req *sproto.AllocateRequest, slotsPerPod, numPods int,
 ) ([]*k8sPodResources, error) {
 	resp := ctx.Ask(k.podsActor, reattachAllocationPods{
+		resourcePool: k.poolConfig.PoolName,
 		allocationID: req.AllocationID,
 		numPods:      numPods,
 		slots:        slotsPerPod,
@@ -574,6 +576,7 @@ func (k *kubernetesResourcePool) restoreResources(
 			podsActor:       k.podsActor,
 			containerID:     cproto.ID(restoreResponse.containerID),
 			slots:           slotsPerPod,
+			numPods:         numPods,
 			group:           k.groups[req.Group],
 			initialPosition: k.queuePositions[k.allocationIDToJobID[req.AllocationID]],
 			namespace:       k.poolConfig.KubernetesNamespace,
@@ -659,6 +662,7 @@ type k8sPodResources struct {
 	group           *tasklist.Group
 	containerID     cproto.ID
 	slots           int
+	numPods         int
 	initialPosition decimal.Decimal
 	namespace       string
 
@@ -705,6 +709,8 @@ func (p k8sPodResources) Start(
 		Spec:         spec,
 		Slots:        p.slots,
 		Rank:         rri.AgentRank,
+		NumPods:      p.numPods,
+		ResourcePool: p.req.ResourcePool,
 		Namespace:    p.namespace,
 		LogContext:   logCtx,
 	}).Error()
@@ -6,6 +6,7 @@ import (
 	"math"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,7 @@ import (
 	petName "github.com/dustinkirkland/golang-petname"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
 
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/cproto"
@@ -36,6 +38,9 @@ const (
 
 	gcTask  = "gc"
 	cmdTask = "cmd"
+
+	// preferredLabelWeight is the weight of the node affinity term of each preferred label.
+	preferredLabelWeight = 100
 )
 
 func (p *pod) configureResourcesRequirements() k8sV1.ResourceRequirements {
@@ -253,6 +258,47 @@ func addNodeDisabledAffinityToPodSpec(pod *k8sV1.Pod, clusterID string) {
 	// so we can skip the step in k8s disable where we kill everything in non drain.
 }
 
+// addLabelSelectorsToPodSpec restricts the pod to nodes with all of the required labels, through
+// its node selector, and makes it prefer nodes with more of the preferred labels, through its node
+// affinity.
+func addLabelSelectorsToPodSpec(pod *k8sV1.Pod, selectors expconf.LabelSelectorsConfig) {
+	if len(selectors.Required()) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = make(map[string]string)
+	}
+	for k, v := range selectors.Required() {
+		pod.Spec.NodeSelector[k] = v
+	}
+
+	if len(selectors.Preferred()) == 0 {
+		return
+	}
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &k8sV1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &k8sV1.NodeAffinity{}
+	}
+	nodeAffinity := pod.Spec.Affinity.NodeAffinity
+
+	keys := maps.Keys(selectors.Preferred())
+	sort.Strings(keys)
+	for _, k := range keys {
+		nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			k8sV1.PreferredSchedulingTerm{
+				Weight: preferredLabelWeight,
+				Preference: k8sV1.NodeSelectorTerm{
+					MatchExpressions: []k8sV1.NodeSelectorRequirement{{
+						Key:      k,
+						Operator: k8sV1.NodeSelectorOpIn,
+						Values:   []string{selectors.Preferred()[k]},
+					}},
+				},
+			},
+		)
+	}
+}
+
 func (p *pod) configureCoscheduler(newPod *k8sV1.Pod, scheduler string) {
 	if newPod.Spec.SchedulerName != scheduler {
 		return
@@ -326,8 +372,12 @@ func (p *pod) configurePodSpec(
 	podSpec.ObjectMeta.Labels[determinedLabel] = p.submissionInfo.taskSpec.AllocationID
 
 	p.modifyPodSpec(podSpec, scheduler)
+	if p.gang != nil {
+		p.gangScheduler.configurePod(podSpec, *p.gang)
+	}
 
 	addNodeDisabledAffinityToPodSpec(podSpec, clusterIDNodeLabel())
+	addLabelSelectorsToPodSpec(podSpec, p.submissionInfo.taskSpec.ResourcesConfig.LabelSelectors())
 
 	nonDeterminedContainers := make([]k8sV1.Container, 0)
 	for idx, container := range podSpec.Spec.Containers {
@@ -385,6 +435,15 @@ func (p *pod) createPodSpec(scheduler string) error {
 
 	env := spec.Environment
 
+	claims, claimVolumeMounts, claimVolumes, err := configurePersistentVolumeClaims(
+		model.TaskID(spec.TaskID), spec.WorkspaceID, p.namespace, env.PersistentVolumeClaims())
+	if err != nil {
+		return err
+	}
+	p.claims = claims
+	volumeMounts = append(volumeMounts, claimVolumeMounts...)
+	volumes = append(volumes, claimVolumes...)
+
 	// This array containerPorts is set on the container spec.
 	// This field on the container spec is for "primarily informational"
 	// reasons and to allow us to read these ports in reattaching pods.
@@ -134,6 +134,34 @@ func TestAddNodeDisabledAffinityToPodSpec(t *testing.T) {
 		NodeSelectorTerms, nodeSelectorTerm)
 }
 
+func TestAddLabelSelectorsToPodSpec(t *testing.T) {
+	p := &k8sV1.Pod{Spec: k8sV1.PodSpec{NodeSelector: map[string]string{"zone": "a"}}}
+	addLabelSelectorsToPodSpec(p, expconf.LabelSelectorsConfig{
+		RawRequired:  map[string]string{"gpu": "h100"},
+		RawPreferred: map[string]string{"storage": "nvme", "nvlink": "true"},
+	})
+	require.Equal(t, map[string]string{"zone": "a", "gpu": "h100"}, p.Spec.NodeSelector)
+	require.Equal(t, []k8sV1.PreferredSchedulingTerm{
+		{
+			Weight: preferredLabelWeight,
+			Preference: k8sV1.NodeSelectorTerm{MatchExpressions: []k8sV1.NodeSelectorRequirement{
+				{Key: "nvlink", Operator: k8sV1.NodeSelectorOpIn, Values: []string{"true"}},
+			}},
+		},
+		{
+			Weight: preferredLabelWeight,
+			Preference: k8sV1.NodeSelectorTerm{MatchExpressions: []k8sV1.NodeSelectorRequirement{
+				{Key: "storage", Operator: k8sV1.NodeSelectorOpIn, Values: []string{"nvme"}},
+			}},
+		},
+	}, p.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+
+	p = &k8sV1.Pod{}
+	addLabelSelectorsToPodSpec(p, expconf.LabelSelectorsConfig{})
+	require.Nil(t, p.Spec.NodeSelector)
+	require.Nil(t, p.Spec.Affinity)
+}
+
 func TestLaterEnvironmentVariablesGetSet(t *testing.T) {
 	dontBe := k8sV1.EnvVar{Name: "var", Value: "dontbe"}
 	shouldBe := k8sV1.EnvVar{Name: "var", Value: "shouldbe"}
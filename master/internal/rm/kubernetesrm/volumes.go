@@ -3,14 +3,29 @@ package kubernetesrm
 import (
 	"fmt"
 	"path"
+	"strconv"
 
 	"github.com/determined-ai/determined/master/pkg/etc"
 
 	"github.com/docker/docker/api/types/mount"
+	"github.com/pkg/errors"
 
 	k8sV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+const (
+	taskClaimScope             = "task"
+	workspaceClaimScope        = "workspace"
+	determinedWorkspaceLabel   = "determined-workspace"
+	determinedTaskLabel        = "determined-task"
+	taskClaimVolumePrefix      = "det-task-pvc-"
+	workspaceClaimVolumePrefix = "det-workspace-pvc-"
 )
 
 func configureMountPropagation(b *mount.BindOptions) *k8sV1.MountPropagationMode {
@@ -154,3 +169,77 @@ func configureAdditionalFilesVolumes(
 
 	return initContainerVolumeMounts, mainContainerVolumeMounts, volumes
 }
+
+// configurePersistentVolumeClaims builds the persistent volume claims requested by a task and the
+// volumes that mount them. Task-scoped claims are named after and labeled with the task, so every
+// allocation of the task mounts the same volume, and are deleted once the task ends. Workspace-
+// scoped claims are named after and labeled with the workspace, so every task in the workspace
+// mounts the same volume, and are deleted once the workspace is deleted or archived.
+func configurePersistentVolumeClaims(
+	taskID model.TaskID,
+	workspaceID int,
+	namespace string,
+	claims expconf.PersistentVolumeClaimsConfig,
+) ([]*k8sV1.PersistentVolumeClaim, []k8sV1.VolumeMount, []k8sV1.Volume, error) {
+	pvcs := make([]*k8sV1.PersistentVolumeClaim, 0, len(claims))
+	volumeMounts := make([]k8sV1.VolumeMount, 0, len(claims))
+	volumes := make([]k8sV1.Volume, 0, len(claims))
+
+	for _, c := range claims {
+		size, err := resource.ParseQuantity(c.Size())
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err,
+				"invalid size %q for persistent volume claim %s", c.Size(), c.Name())
+		}
+
+		var name, volumeName string
+		var labels map[string]string
+		switch c.Scope() {
+		case taskClaimScope:
+			name = kubernetesObjectName("det", c.Name(), string(taskID))
+			volumeName = kubernetesObjectName(taskClaimVolumePrefix + c.Name())
+			labels = map[string]string{determinedTaskLabel: string(taskID)}
+		case workspaceClaimScope:
+			if workspaceID == 0 {
+				return nil, nil, nil, errors.Errorf(
+					"persistent volume claim %s is workspace scoped, but the task has no workspace",
+					c.Name())
+			}
+			name = kubernetesObjectName("det-ws", strconv.Itoa(workspaceID), c.Name())
+			volumeName = kubernetesObjectName(workspaceClaimVolumePrefix + c.Name())
+			labels = map[string]string{determinedWorkspaceLabel: strconv.Itoa(workspaceID)}
+		default:
+			return nil, nil, nil, errors.Errorf(
+				"unknown scope %q for persistent volume claim %s", c.Scope(), c.Name())
+		}
+
+		pvcs = append(pvcs, &k8sV1.PersistentVolumeClaim{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Spec: k8sV1.PersistentVolumeClaimSpec{
+				AccessModes: []k8sV1.PersistentVolumeAccessMode{
+					k8sV1.PersistentVolumeAccessMode(c.AccessMode()),
+				},
+				StorageClassName: c.StorageClass(),
+				Resources: k8sV1.ResourceRequirements{
+					Requests: k8sV1.ResourceList{k8sV1.ResourceStorage: size},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, k8sV1.VolumeMount{
+			Name:      volumeName,
+			MountPath: c.MountPath(),
+		})
+		volumes = append(volumes, k8sV1.Volume{
+			Name: volumeName,
+			VolumeSource: k8sV1.VolumeSource{
+				PersistentVolumeClaim: &k8sV1.PersistentVolumeClaimVolumeSource{ClaimName: name},
+			},
+		})
+	}
+
+	return pvcs, volumeMounts, volumes, nil
+}
@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package kubernetesrm
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestWorkspaceClaimsDeletedWithWorkspace(t *testing.T) {
+	ctx := context.Background()
+	pgDB := db.MustResolveTestPostgres(t)
+	db.MustMigrateTestPostgres(t, pgDB, "file://../../../static/migrations")
+	user := db.RequireMockUser(t, pgDB)
+
+	var workspaceIDs []int
+	for _, archived := range []bool{false, true} {
+		w := &model.Workspace{Name: uuid.NewString(), UserID: user.ID, Archived: archived}
+		_, err := db.Bun().NewInsert().Model(w).Exec(ctx)
+		require.NoError(t, err)
+		workspaceIDs = append(workspaceIDs, w.ID)
+	}
+	active, archived := workspaceIDs[0], workspaceIDs[1]
+	deleted := archived + 1000
+
+	clientSet := fake.NewSimpleClientset()
+	claims := expconf.PersistentVolumeClaimsConfig{testClaim("data", workspaceClaimScope)}
+	for _, workspaceID := range []int{active, archived, deleted} {
+		pvcs, _, _, err := configurePersistentVolumeClaims("1.Trial.2", workspaceID, "team-a", claims)
+		require.NoError(t, err)
+		_, err = clientSet.CoreV1().PersistentVolumeClaims("team-a").Create(
+			ctx, pvcs[0], metaV1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	p := &pods{
+		clusters: map[string]*kubernetesCluster{
+			defaultClusterName: {
+				clientSet:           clientSet,
+				namespaceToPoolName: map[string]string{"team-a": "default"},
+			},
+		},
+		syslog: logrus.WithField("test", t.Name()),
+	}
+	p.deleteWorkspaceClaims(ctx)
+
+	list, err := clientSet.CoreV1().PersistentVolumeClaims("team-a").List(
+		ctx, metaV1.ListOptions{LabelSelector: determinedWorkspaceLabel})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, strconv.Itoa(active), list.Items[0].Labels[determinedWorkspaceLabel])
+}
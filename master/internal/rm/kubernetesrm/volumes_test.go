@@ -0,0 +1,172 @@
+package kubernetesrm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	k8sTesting "k8s.io/client-go/testing"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func testClaim(name, scope string) expconf.PersistentVolumeClaim {
+	return expconf.PersistentVolumeClaim{
+		RawName:       name,
+		RawMountPath:  "/" + name,
+		RawSize:       "10Gi",
+		RawAccessMode: ptrs.Ptr("ReadWriteMany"),
+		RawScope:      ptrs.Ptr(scope),
+	}
+}
+
+func TestConfigurePersistentVolumeClaims(t *testing.T) {
+	claims := expconf.PersistentVolumeClaimsConfig{
+		testClaim("scratch", taskClaimScope),
+		testClaim("data", workspaceClaimScope),
+	}
+	claims[1].RawStorageClass = ptrs.Ptr("fast")
+
+	pvcs, mounts, volumes, err := configurePersistentVolumeClaims("1.Trial.2", 7, "team-a", claims)
+	require.NoError(t, err)
+	require.Len(t, pvcs, 2)
+	require.Len(t, mounts, 2)
+	require.Len(t, volumes, 2)
+
+	task := pvcs[0]
+	require.Equal(t, "det-scratch-1-trial-2", task.Name)
+	require.Equal(t, "team-a", task.Namespace)
+	require.Equal(t, "1.Trial.2", task.Labels[determinedTaskLabel])
+	require.Equal(t, []k8sV1.PersistentVolumeAccessMode{k8sV1.ReadWriteMany},
+		task.Spec.AccessModes)
+	require.Nil(t, task.Spec.StorageClassName)
+	size := task.Spec.Resources.Requests[k8sV1.ResourceStorage]
+	require.Equal(t, "10Gi", size.String())
+
+	workspace := pvcs[1]
+	require.Equal(t, "det-ws-7-data", workspace.Name)
+	require.Equal(t, "7", workspace.Labels[determinedWorkspaceLabel])
+	require.Empty(t, workspace.Labels[determinedTaskLabel])
+	require.Equal(t, "fast", *workspace.Spec.StorageClassName)
+
+	require.Equal(t, "/scratch", mounts[0].MountPath)
+	require.Equal(t, volumes[0].Name, mounts[0].Name)
+	require.Equal(t, task.Name, volumes[0].PersistentVolumeClaim.ClaimName)
+}
+
+func testClaimWorker(clientSet *fake.Clientset) (
+	*requestProcessingWorker, <-chan resourcesRequestFailure,
+) {
+	failures := make(chan resourcesRequestFailure, 16)
+	return &requestProcessingWorker{
+		podInterfaces: map[string]typedV1.PodInterface{
+			"team-a": clientSet.CoreV1().Pods("team-a"),
+		},
+		configMapInterfaces: map[string]typedV1.ConfigMapInterface{
+			"team-a": clientSet.CoreV1().ConfigMaps("team-a"),
+		},
+		claimInterfaces: map[string]typedV1.PersistentVolumeClaimInterface{
+			"team-a": clientSet.CoreV1().PersistentVolumeClaims("team-a"),
+		},
+		failures: failures,
+		syslog:   logrus.WithField("test", "claims"),
+	}, failures
+}
+
+func testCreateResources(
+	name string, claims []*k8sV1.PersistentVolumeClaim,
+) createKubernetesResources {
+	meta := metaV1.ObjectMeta{Name: name, Namespace: "team-a"}
+	return createKubernetesResources{
+		podSpec:       &k8sV1.Pod{ObjectMeta: meta},
+		configMapSpec: &k8sV1.ConfigMap{ObjectMeta: meta},
+		claimSpecs:    claims,
+	}
+}
+
+func TestTaskClaimReusedAcrossAllocations(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+	worker, failures := testClaimWorker(clientSet)
+	claims := expconf.PersistentVolumeClaimsConfig{testClaim("scratch", taskClaimScope)}
+
+	// Every allocation of a task, e.g. after the trial is paused and resumed, builds its claims
+	// from the task ID, so the second allocation mounts the claim the first one created.
+	var names []string
+	for i := 0; i < 2; i++ {
+		pvcs, _, volumes, err := configurePersistentVolumeClaims("1.Trial.2", 7, "team-a", claims)
+		require.NoError(t, err)
+		worker.receiveCreateKubernetesResources(testCreateResources(fmt.Sprintf("pod-%d", i), pvcs))
+		names = append(names, volumes[0].PersistentVolumeClaim.ClaimName)
+	}
+	require.Equal(t, names[0], names[1])
+	require.Empty(t, failures)
+
+	list, err := clientSet.CoreV1().PersistentVolumeClaims("team-a").List(
+		context.Background(), metaV1.ListOptions{LabelSelector: determinedTaskLabel})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, names[0], list.Items[0].Name)
+}
+
+func TestClaimCreationFailureFailsPod(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+	clientSet.PrependReactor("create", "persistentvolumeclaims",
+		func(k8sTesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("quota exceeded")
+		})
+	worker, failures := testClaimWorker(clientSet)
+	claims := expconf.PersistentVolumeClaimsConfig{testClaim("scratch", taskClaimScope)}
+
+	pvcs, _, _, err := configurePersistentVolumeClaims("1.Trial.2", 7, "team-a", claims)
+	require.NoError(t, err)
+	worker.receiveCreateKubernetesResources(testCreateResources("pod-0", pvcs))
+
+	require.Len(t, failures, 1)
+	failure := (<-failures).(resourceCreationFailed)
+	require.Equal(t, "pod-0", failure.podName)
+	require.ErrorContains(t, failure.err, "quota exceeded")
+
+	// The pod isn't created without its claims.
+	pods, err := clientSet.CoreV1().Pods("team-a").List(context.Background(), metaV1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, pods.Items)
+}
+
+func TestTaskClaimNameKeepsLongTaskIDsUnique(t *testing.T) {
+	claims := expconf.PersistentVolumeClaimsConfig{testClaim("scratch", taskClaimScope)}
+	prefix := strings.Repeat("a", maxKubernetesObjectNameLength)
+
+	first, _, _, err := configurePersistentVolumeClaims(
+		model.TaskID(prefix+"-1"), 7, "default", claims)
+	require.NoError(t, err)
+	second, _, _, err := configurePersistentVolumeClaims(
+		model.TaskID(prefix+"-2"), 7, "default", claims)
+	require.NoError(t, err)
+
+	require.LessOrEqual(t, len(first[0].Name), maxKubernetesObjectNameLength)
+	require.LessOrEqual(t, len(second[0].Name), maxKubernetesObjectNameLength)
+	require.NotEqual(t, first[0].Name, second[0].Name)
+}
+
+func TestConfigurePersistentVolumeClaimsErrors(t *testing.T) {
+	_, _, _, err := configurePersistentVolumeClaims("1.Trial.2", 0, "default",
+		expconf.PersistentVolumeClaimsConfig{testClaim("data", workspaceClaimScope)})
+	require.ErrorContains(t, err, "has no workspace")
+
+	claim := testClaim("scratch", taskClaimScope)
+	claim.RawSize = "lots"
+	_, _, _, err = configurePersistentVolumeClaims("1.Trial.2", 7, "default",
+		expconf.PersistentVolumeClaimsConfig{claim})
+	require.ErrorContains(t, err, "invalid size")
+}
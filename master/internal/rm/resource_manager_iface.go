@@ -70,6 +70,7 @@ type ResourceManager interface {
 		resourcePoolName string,
 		fallbackConfig model.TaskContainerDefaultsConfig,
 	) (model.TaskContainerDefaultsConfig, error)
+	UpdateResourcePoolConfigs(actor.Messenger, sproto.UpdateResourcePoolConfigs) error
 
 	// Job queue
 	GetJobQ(actor.Messenger, sproto.GetJobQ) (map[model.JobID]*sproto.RMJobInfo, error)
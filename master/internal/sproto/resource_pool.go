@@ -1,5 +1,7 @@
 package sproto
 
+import "github.com/determined-ai/determined/master/internal/config"
+
 type (
 	// CapacityCheck checks the potential available slots in a resource pool.
 	CapacityCheck struct {
@@ -10,4 +12,10 @@ type (
 		SlotsAvailable   int
 		CapacityExceeded bool
 	}
+	// UpdateResourcePoolConfigs replaces the live-reloadable settings of a resource manager and
+	// its pools after the master configuration is reloaded.
+	UpdateResourcePoolConfigs struct {
+		DefaultScheduler *config.SchedulerConfig
+		ResourcePools    []config.ResourcePoolConfig
+	}
 )
@@ -258,6 +258,9 @@ const (
 	// RestoreError denotes a failure to restore a running allocation on master blip.
 	RestoreError FailureType = "RM failed to restore the allocation"
 
+	// ResourcesOOMKilled denotes that the container was killed because it ran out of memory.
+	ResourcesOOMKilled FailureType = "resources were killed because they ran out of memory"
+
 	// UnknownError denotes an internal error that did not map to a know failure type.
 	UnknownError = "unknown agent failure: %s"
 )
@@ -281,6 +284,8 @@ func (f FailureType) Proto() taskv1.FailureType {
 		return taskv1.FailureType_FAILURE_TYPE_AGENT_ERROR
 	case RestoreError:
 		return taskv1.FailureType_FAILURE_TYPE_RESTORE_ERROR
+	case ResourcesOOMKilled:
+		return taskv1.FailureType_FAILURE_TYPE_RESOURCES_OOM_KILLED
 	case UnknownError:
 		return taskv1.FailureType_FAILURE_TYPE_UNKNOWN_ERROR
 	default:
@@ -308,6 +313,8 @@ func FromContainerFailureType(t aproto.FailureType) FailureType {
 		return AgentError
 	case aproto.RestoreError:
 		return RestoreError
+	case aproto.ContainerOOMKilled:
+		return ResourcesOOMKilled
 	default:
 		return FailureType(fmt.Sprintf(UnknownError, t))
 	}
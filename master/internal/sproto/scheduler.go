@@ -4,4 +4,12 @@ package sproto
 type FittingRequirements struct {
 	// SingleAgent specifies that the task must be located within a single agent.
 	SingleAgent bool
+	// RequiredLabels are the labels every agent the task is placed on must have.
+	RequiredLabels map[string]string
+	// PreferredLabels are the labels the task prefers the agents it is placed on to have.
+	PreferredLabels map[string]string
+	// CPUs and Memory, in bytes, are reserved for each of the task's containers on the agents it is
+	// placed on; 0 reserves none.
+	CPUs   float64
+	Memory int64
 }
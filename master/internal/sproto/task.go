@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -51,6 +52,8 @@ type (
 
 		// Logging context of the allocation actor.
 		LogContext logger.Context
+		// SpanContext is the trace span the spans of the allocation's lifecycle are children of.
+		SpanContext trace.SpanContext
 	}
 
 	// IdleTimeoutConfig configures how idle timeouts should behave.
@@ -277,6 +280,8 @@ type (
 		Token        string
 		AgentRank    int
 		IsMultiAgent bool
+		// TraceContext propagates the allocation's trace to where the resources are started.
+		TraceContext map[string]string
 	}
 )
 
@@ -16,6 +16,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/portregistry"
 	"github.com/determined-ai/determined/master/internal/prom"
@@ -144,6 +145,8 @@ type allocation struct {
 	logCtx          detLogger.Context
 	restored        bool
 	portsRegistered bool
+
+	trace *allocationTrace
 }
 
 // newAllocation returns a new allocation, which tracks allocation state in a fairly generic way.
@@ -180,6 +183,7 @@ func newAllocation(
 		resources: resourcesList{},
 
 		logCtx: req.LogContext,
+		trace:  newAllocationTrace(req),
 	}
 
 	rmEvents, err := a.requestResources()
@@ -347,6 +351,7 @@ func (a *allocation) SetReady(_ context.Context) error {
 	a.sendTaskLog(&model.TaskLog{Log: fmt.Sprintf("Service of %s is available", a.req.Name)})
 	a.setMostProgressedModelState(model.AllocationStateRunning)
 	a.model.IsReady = ptrs.Ptr(true)
+	a.trace.endReady()
 	if err := a.db.UpdateAllocationState(a.model); err != nil {
 		a.crash(err)
 		return err
@@ -399,6 +404,7 @@ func (a *allocation) WatchRendezvous(rID sproto.ResourcesID) (RendezvousWatcher,
 
 	if a.rendezvous == nil {
 		a.rendezvous = newRendezvous(a.model.AllocationID, a.resources, rendezvousTimeoutDuration)
+		a.trace.startRendezvous()
 		a.wg.Go(func(ctx context.Context) {
 			t := time.NewTimer(rendezvousTimeoutDuration)
 			defer t.Stop()
@@ -411,7 +417,11 @@ func (a *allocation) WatchRendezvous(rID sproto.ResourcesID) (RendezvousWatcher,
 		})
 	}
 
-	return a.rendezvous.watch(rID)
+	w, err := a.rendezvous.watch(rID)
+	if a.rendezvous.ready() {
+		a.trace.endRendezvous()
+	}
+	return w, err
 }
 
 // UnwatchRendezvous removes a rendezvous watcher.
@@ -633,6 +643,13 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 			return fmt.Errorf("updating allocation db")
 		}
 
+		if err := db.AddAllocationCost(
+			context.TODO(), a.model.AllocationID,
+			config.GetMasterConfig().SlotHourCost(a.req.ResourcePool), spec.WorkspaceID, spec.Labels,
+		); err != nil {
+			a.syslog.WithError(err).Warn("failed to record allocation cost")
+		}
+
 		for portName, port := range a.model.Ports {
 			spec.Environment.RawPorts[portName] = port
 			spec.ExtraEnvVars[portName] = strconv.Itoa(port)
@@ -643,6 +660,7 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 				Token:        token,
 				AgentRank:    a.resources[cID].Rank,
 				IsMultiAgent: len(a.resources) > 1,
+				TraceContext: a.trace.traceContext(),
 			}); err != nil {
 				return fmt.Errorf("starting resources (%v): %w", r, err)
 			}
@@ -686,6 +704,9 @@ func (a *allocation) resourcesStateChanged(msg *sproto.ResourcesStateChanged) {
 		if a.model.StartTime == nil {
 			a.markResourcesStarted()
 		}
+		if a.model.IsReady == nil || !*a.model.IsReady {
+			a.trace.startReady()
+		}
 
 		a.resources[msg.ResourcesID].Started = msg.ResourcesStarted
 		if err := a.resources[msg.ResourcesID].Persist(); err != nil {
@@ -696,6 +717,7 @@ func (a *allocation) resourcesStateChanged(msg *sproto.ResourcesStateChanged) {
 		if a.rendezvous != nil && a.rendezvous.try() {
 			a.syslog.
 				Info("all containers are connected successfully (task container state changed)")
+			a.trace.endRendezvous()
 		}
 		if len(a.req.ProxyPorts) > 0 && msg.ResourcesStarted.Addresses != nil &&
 			a.resources[msg.ResourcesID].Rank == 0 {
@@ -1058,6 +1080,7 @@ func (a *allocation) terminated(reason string) {
 	}
 	exit := &AllocationExited{FinalState: a.state()}
 	a.exited = exit
+	defer func() { a.trace.end(exit.Err) }()
 	exitReason := fmt.Sprintf("allocation terminated after %s", reason)
 
 	defer a.rm.Release(a.system, sproto.ResourcesReleased{AllocationID: a.req.AllocationID})
@@ -1108,7 +1131,7 @@ func (a *allocation) terminated(reason string) {
 		switch err := a.exitErr.(type) {
 		case sproto.ResourcesFailure:
 			switch err.FailureType {
-			case sproto.ResourcesFailed, sproto.TaskError:
+			case sproto.ResourcesFailed, sproto.ResourcesOOMKilled, sproto.TaskError:
 				if a.killedDaemonsGracefully {
 					exitReason = fmt.Sprint("allocation terminated daemon processes as part of normal exit")
 					a.syslog.Info(exitReason)
@@ -1257,6 +1280,9 @@ func (a *allocation) state() AllocationState {
 }
 
 func (a *allocation) setModelState(v model.AllocationState) {
+	if a.model.State == nil || *a.model.State != v {
+		a.trace.setState(v)
+	}
 	a.model.State = &v
 }
 
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	opentelemetry "github.com/determined-ai/determined/master/pkg/opentelemetry"
+)
+
+var tracer = otel.Tracer("determined-master/task")
+
+// allocationTrace records the lifecycle of an allocation as a span, with a child span for each
+// allocation state, for waiting on the task to report ready, and for rendezvous. When tracing is
+// not configured, all of these are no-ops.
+type allocationTrace struct {
+	ctx   context.Context
+	attrs []attribute.KeyValue
+
+	root       trace.Span
+	state      trace.Span
+	ready      trace.Span
+	rendezvous trace.Span
+}
+
+func newAllocationTrace(req sproto.AllocateRequest) *allocationTrace {
+	attrs := []attribute.KeyValue{
+		opentelemetry.AllocationIDKey.String(string(req.AllocationID)),
+		attribute.String("determined.task_id", string(req.TaskID)),
+		attribute.String("determined.resource_pool", req.ResourcePool),
+	}
+	ctx := trace.ContextWithSpanContext(context.Background(), req.SpanContext)
+	ctx, root := tracer.Start(ctx, "allocation",
+		trace.WithTimestamp(req.RequestTime), trace.WithAttributes(attrs...))
+	return &allocationTrace{ctx: ctx, attrs: attrs, root: root}
+}
+
+// setState ends the span of the previous allocation state and starts one for the new state. The
+// RM assigning resources to the allocation ends the span of the pending state.
+func (t *allocationTrace) setState(state model.AllocationState) {
+	endSpan(&t.state)
+	if state == model.AllocationStateTerminated {
+		return
+	}
+	_, t.state = tracer.Start(t.ctx, "allocation."+strings.ToLower(string(state)),
+		trace.WithAttributes(t.attrs...))
+}
+
+// startReady starts the span covering the wait for the task to report it is ready.
+func (t *allocationTrace) startReady() {
+	if t.ready == nil {
+		_, t.ready = tracer.Start(t.ctx, "allocation.ready", trace.WithAttributes(t.attrs...))
+	}
+}
+
+func (t *allocationTrace) endReady() {
+	endSpan(&t.ready)
+}
+
+// startRendezvous starts the span covering the rendezvous of the allocation's resources.
+func (t *allocationTrace) startRendezvous() {
+	if t.rendezvous == nil {
+		_, t.rendezvous = tracer.Start(t.ctx, "allocation.rendezvous",
+			trace.WithAttributes(t.attrs...))
+	}
+}
+
+func (t *allocationTrace) endRendezvous() {
+	endSpan(&t.rendezvous)
+}
+
+// traceContext returns the allocation's trace context, to be propagated to its resources.
+func (t *allocationTrace) traceContext() map[string]string {
+	return opentelemetry.InjectTraceContext(t.ctx)
+}
+
+// end ends all the spans of the allocation, marking it as failed if err is not nil.
+func (t *allocationTrace) end(err error) {
+	endSpan(&t.rendezvous)
+	endSpan(&t.ready)
+	endSpan(&t.state)
+	if err != nil {
+		t.root.RecordError(err)
+		t.root.SetStatus(codes.Error, err.Error())
+	}
+	t.root.End()
+}
+
+func endSpan(span *trace.Span) {
+	if *span != nil {
+		(*span).End()
+		*span = nil
+	}
+}
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	opentelemetry "github.com/determined-ai/determined/master/pkg/opentelemetry"
+)
+
+func TestAllocationTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	_, parent := otel.Tracer("test").Start(context.Background(), "trial.maybeAllocateTask")
+	parent.End()
+
+	at := newAllocationTrace(sproto.AllocateRequest{
+		AllocationID: "task.1",
+		TaskID:       "task",
+		RequestTime:  time.Now(),
+		ResourcePool: "default",
+		SpanContext:  parent.SpanContext(),
+	})
+	at.setState(model.AllocationStatePending)
+	at.setState(model.AllocationStateAssigned)
+
+	// Resources started elsewhere continue the allocation's trace.
+	remote := trace.SpanContextFromContext(
+		opentelemetry.ExtractTraceContext(context.Background(), at.traceContext()))
+	require.Equal(t, parent.SpanContext().TraceID(), remote.TraceID())
+
+	at.setState(model.AllocationStateRunning)
+	at.startReady()
+	at.startRendezvous()
+	at.endRendezvous()
+	at.setState(model.AllocationStateTerminated)
+	at.end(errors.New("container failed"))
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	for _, name := range []string{
+		"allocation", "allocation.pending", "allocation.assigned", "allocation.running",
+		"allocation.ready", "allocation.rendezvous",
+	} {
+		s, ok := spans[name]
+		require.True(t, ok, "missing span %s", name)
+		require.Equal(t, parent.SpanContext().TraceID(), s.SpanContext().TraceID())
+		require.Contains(t, s.Attributes(), opentelemetry.AllocationIDKey.String("task.1"))
+	}
+	require.Equal(t, parent.SpanContext().SpanID(), spans["allocation"].Parent().SpanID())
+	require.Equal(t, spans["allocation"].SpanContext().SpanID(),
+		spans["allocation.pending"].Parent().SpanID())
+	require.Equal(t, spans["allocation"].SpanContext().SpanID(), remote.SpanID())
+	require.Equal(t, codes.Error, spans["allocation"].Status().Code)
+}
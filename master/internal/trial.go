@@ -11,6 +11,8 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/prom"
@@ -339,6 +341,14 @@ func (t *trial) maybeAllocateTask() error {
 	name := fmt.Sprintf("Trial %d (Experiment %d)", t.id, t.experimentID)
 	t.syslog.Info("decided to allocate trial")
 
+	_, span := tracer.Start(context.Background(), "trial.maybeAllocateTask",
+		trace.WithAttributes(
+			attribute.Int("determined.experiment_id", t.experimentID),
+			attribute.Int("determined.trial_id", t.id),
+		))
+	defer span.End()
+
+	cpus, memory := t.config.Resources().HostResourceRequests()
 	restoredAllocation, err := t.maybeRestoreAllocation()
 	if err != nil {
 		t.syslog.WithError(err).Warn("failed to restore trial allocation")
@@ -360,11 +370,16 @@ func (t *trial) maybeAllocateTask() error {
 			SlotsNeeded:       t.config.Resources().SlotsPerTrial(),
 			ResourcePool:      t.config.Resources().ResourcePool(),
 			FittingRequirements: sproto.FittingRequirements{
-				SingleAgent: false,
+				SingleAgent:     false,
+				RequiredLabels:  t.config.Resources().LabelSelectors().Required(),
+				PreferredLabels: t.config.Resources().LabelSelectors().Preferred(),
+				CPUs:            cpus,
+				Memory:          memory,
 			},
 
 			Preemptible: true,
 			Restore:     true,
+			SpanContext: span.SpanContext(),
 			ProxyPorts: sproto.NewProxyPortConfig(
 				tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
 		}
@@ -404,11 +419,16 @@ func (t *trial) maybeAllocateTask() error {
 		SlotsNeeded:  t.config.Resources().SlotsPerTrial(),
 		ResourcePool: t.config.Resources().ResourcePool(),
 		FittingRequirements: sproto.FittingRequirements{
-			SingleAgent: false,
+			SingleAgent:     false,
+			RequiredLabels:  t.config.Resources().LabelSelectors().Required(),
+			PreferredLabels: t.config.Resources().LabelSelectors().Preferred(),
+			CPUs:            cpus,
+			Memory:          memory,
 		},
 
 		Preemptible: true,
 		ProxyPorts:  sproto.NewProxyPortConfig(tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
+		SpanContext: span.SpanContext(),
 	}
 
 	t.syslog.
@@ -416,6 +436,7 @@ func (t *trial) maybeAllocateTask() error {
 		Debugf("starting new trial allocation")
 
 	prom.AssociateJobExperiment(t.jobID, strconv.Itoa(t.experimentID), t.config.Labels())
+	prom.AssociateTrial(t.id, t.experimentID, t.config.Workspace(), t.config.Project())
 	err = task.DefaultService.StartAllocation(
 		t.logCtx, ar, t.db, t.rm, specifier, t.system,
 		t.AllocationExitedCallback,
@@ -487,6 +508,7 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 	t.allocationID = nil
 
 	prom.DisassociateJobExperiment(t.jobID, strconv.Itoa(t.experimentID), t.config.Labels())
+	prom.DisassociateTrial(t.id)
 
 	// Decide if this is permanent.
 	switch {
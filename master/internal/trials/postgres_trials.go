@@ -2,6 +2,7 @@ package trials
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -60,6 +61,7 @@ func MetricsTimeSeries(trialID int32, startTime time.Time,
 		return nil, fmt.Errorf("getting summary metrics for trial %d: %w", trialID, err)
 	}
 
+	histogramMetrics := map[string]bool{}
 	for _, metricName := range append(metricNames, "epoch") {
 		metricType := db.MetricTypeString
 		if curSummary, ok := summaryMetrics.Metrics[metricName].(map[string]any); ok {
@@ -74,6 +76,8 @@ func MetricsTimeSeries(trialID int32, startTime time.Time,
 			cast = "float8"
 		case db.MetricTypeBool:
 			cast = "boolean"
+		case db.MetricTypeHistogram:
+			histogramMetrics[metricName] = true
 		}
 		subq = subq.ColumnExpr("(metrics->?->>?)::? as ?",
 			model.TrialMetricsJSONPath(metricGroup == model.ValidationMetricGroup),
@@ -112,9 +116,25 @@ func MetricsTimeSeries(trialID int32, startTime time.Time,
 
 	for i := range results {
 		valuesMap := make(map[string]interface{})
+		var histograms map[string]model.Histogram
 		for mName, mVal := range results[i] {
-			if selectMetrics[mName] != "" {
-				valuesMap[selectMetrics[mName]] = mVal
+			name := selectMetrics[mName]
+			switch {
+			case name == "":
+			case histogramMetrics[name]:
+				h, err := parseHistogramMetric(mVal)
+				if err != nil {
+					return nil, errors.Wrapf(err, "parsing histogram metric %s", name)
+				}
+				if h == nil {
+					continue
+				}
+				if histograms == nil {
+					histograms = map[string]model.Histogram{}
+				}
+				histograms[name] = *h
+			default:
+				valuesMap[name] = mVal
 			}
 		}
 		var epoch *float64
@@ -136,8 +156,9 @@ func MetricsTimeSeries(trialID int32, startTime time.Time,
 			Batches: uint(results[i]["batches"].(int64)),
 			Time:    endTime,
 			Epoch:   epoch,
-			TrialID: int32(results[i]["trial_id"].(int64)),
-			Values:  valuesMap,
+			TrialID:    int32(results[i]["trial_id"].(int64)),
+			Values:     valuesMap,
+			Histograms: histograms,
 		}
 
 		metricMeasurements = append(metricMeasurements, metricM)
@@ -145,6 +166,29 @@ func MetricsTimeSeries(trialID int32, startTime time.Time,
 	return metricMeasurements, nil
 }
 
+// parseHistogramMetric parses a histogram metric selected as text, returning nil if the report
+// doesn't have the metric or it isn't a histogram.
+func parseHistogramMetric(v interface{}) (*model.Histogram, error) {
+	var text []byte
+	switch v := v.(type) {
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	default:
+		return nil, nil
+	}
+	var value any
+	if err := json.Unmarshal(text, &value); err != nil {
+		return nil, err
+	}
+	h, ok := model.HistogramFromJSON(value)
+	if !ok {
+		return nil, nil
+	}
+	return &h, nil
+}
+
 // CreateTrialSourceInfo creates a TrialSourceInfo object, which allows us to keep
 // track of the linkage between an inference/fine tuning trial and its checkpoint/model version.
 func CreateTrialSourceInfo(ctx context.Context, tsi *trialv1.TrialSourceInfo,
@@ -0,0 +1,344 @@
+package webhooks
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/webhookv1"
+)
+
+// MetricAlertRuleID is the type for MetricAlertRule IDs.
+type MetricAlertRuleID int
+
+// MetricAlertCondition is the type for the MetricAlertCondition enum.
+type MetricAlertCondition string
+
+const (
+	// MetricAlertConditionNaN fires when the latest value is NaN or infinite.
+	MetricAlertConditionNaN MetricAlertCondition = "NAN"
+	// MetricAlertConditionAbove fires when the latest value is above the threshold.
+	MetricAlertConditionAbove MetricAlertCondition = "ABOVE"
+	// MetricAlertConditionBelow fires when the latest value is below the threshold.
+	MetricAlertConditionBelow MetricAlertCondition = "BELOW"
+	// MetricAlertConditionNoImprovement fires when the last window reports didn't improve
+	// on the best value before them.
+	MetricAlertConditionNoImprovement MetricAlertCondition = "NO_IMPROVEMENT"
+	// MetricAlertConditionDropPercent fires when the latest value dropped by threshold
+	// percent from the mean of the previous window reports.
+	MetricAlertConditionDropPercent MetricAlertCondition = "DROP_PERCENT"
+)
+
+// MetricAlertAction is the type for the MetricAlertAction enum.
+type MetricAlertAction string
+
+const (
+	// MetricAlertActionWebhook sends an event to the rule's webhook.
+	MetricAlertActionWebhook MetricAlertAction = "WEBHOOK"
+	// MetricAlertActionNote appends a line to the trial's notes.
+	MetricAlertActionNote MetricAlertAction = "NOTE"
+	// MetricAlertActionPause pauses the trial.
+	MetricAlertActionPause MetricAlertAction = "PAUSE"
+	// MetricAlertActionKill kills the trial.
+	MetricAlertActionKill MetricAlertAction = "KILL"
+)
+
+// MetricAlertRules is a slice of MetricAlertRule objects.
+type MetricAlertRules []MetricAlertRule
+
+// Proto converts a slice of rules to its protobuf representation.
+func (rs MetricAlertRules) Proto() []*webhookv1.MetricAlertRule {
+	out := make([]*webhookv1.MetricAlertRule, len(rs))
+	for i, r := range rs {
+		out[i] = r.Proto()
+	}
+	return out
+}
+
+// MetricAlertRule corresponds to a row in the "metric_alert_rules" DB table.
+type MetricAlertRule struct {
+	bun.BaseModel `bun:"table:metric_alert_rules"`
+
+	ID              MetricAlertRuleID    `bun:"id,pk,autoincrement"`
+	ProjectID       *int                 `bun:"project_id"`
+	ExperimentID    *int                 `bun:"experiment_id"`
+	MetricName      string               `bun:"metric_name,notnull"`
+	MetricGroup     model.MetricGroup    `bun:"metric_group,notnull"`
+	Condition       MetricAlertCondition `bun:"condition,notnull"`
+	Action          MetricAlertAction    `bun:"action,notnull"`
+	Threshold       float64              `bun:"threshold,notnull"`
+	Window          int                  `bun:"window,notnull"`
+	SmallerIsBetter bool                 `bun:"smaller_is_better,notnull"`
+	WebhookID       *WebhookID           `bun:"webhook_id"`
+}
+
+// MetricAlertRuleFromProto returns a model MetricAlertRule from a proto definition.
+func MetricAlertRuleFromProto(r *webhookv1.MetricAlertRule) (*MetricAlertRule, error) {
+	condition := MetricAlertConditionFromProto(r.Condition)
+	if condition == "" {
+		return nil, fmt.Errorf("unknown metric alert condition %s", r.Condition)
+	}
+	action := MetricAlertActionFromProto(r.Action)
+	if action == "" {
+		return nil, fmt.Errorf("unknown metric alert action %s", r.Action)
+	}
+
+	rule := &MetricAlertRule{
+		MetricName:      r.MetricName,
+		MetricGroup:     model.MetricGroup(r.MetricGroup),
+		Condition:       condition,
+		Action:          action,
+		Threshold:       r.Threshold,
+		Window:          int(r.Window),
+		SmallerIsBetter: r.SmallerIsBetter,
+	}
+	if r.ProjectId != nil {
+		rule.ProjectID = ptrs.Ptr(int(*r.ProjectId))
+	}
+	if r.ExperimentId != nil {
+		rule.ExperimentID = ptrs.Ptr(int(*r.ExperimentId))
+	}
+	if r.WebhookId != nil {
+		rule.WebhookID = ptrs.Ptr(WebhookID(*r.WebhookId))
+	}
+	return rule, nil
+}
+
+// Validate checks that the rule is well formed.
+func (r *MetricAlertRule) Validate() error {
+	if (r.ProjectID == nil) == (r.ExperimentID == nil) {
+		return fmt.Errorf("exactly one of project_id or experiment_id must be set")
+	}
+	if r.MetricName == "" {
+		return fmt.Errorf("metric_name is required")
+	}
+	if err := r.MetricGroup.Validate(); err != nil {
+		return err
+	}
+	switch r.Condition {
+	case MetricAlertConditionNoImprovement, MetricAlertConditionDropPercent:
+		if r.Window < 1 {
+			return fmt.Errorf("window must be at least 1 for %s conditions", r.Condition)
+		}
+	}
+	if r.Condition == MetricAlertConditionDropPercent && (r.Threshold <= 0 || r.Threshold > 100) {
+		return fmt.Errorf("threshold must be a percentage in (0, 100] for %s conditions",
+			r.Condition)
+	}
+	if (r.Action == MetricAlertActionWebhook) != (r.WebhookID != nil) {
+		return fmt.Errorf("webhook_id must be set if and only if the action is %s",
+			MetricAlertActionWebhook)
+	}
+	return nil
+}
+
+// Proto converts a rule to its protobuf representation.
+func (r *MetricAlertRule) Proto() *webhookv1.MetricAlertRule {
+	out := &webhookv1.MetricAlertRule{
+		Id:              int32(r.ID),
+		MetricName:      r.MetricName,
+		MetricGroup:     string(r.MetricGroup),
+		Condition:       r.Condition.Proto(),
+		Action:          r.Action.Proto(),
+		Threshold:       r.Threshold,
+		Window:          int32(r.Window),
+		SmallerIsBetter: r.SmallerIsBetter,
+	}
+	if r.ProjectID != nil {
+		out.ProjectId = ptrs.Ptr(int32(*r.ProjectID))
+	}
+	if r.ExperimentID != nil {
+		out.ExperimentId = ptrs.Ptr(int32(*r.ExperimentID))
+	}
+	if r.WebhookID != nil {
+		out.WebhookId = ptrs.Ptr(int32(*r.WebhookID))
+	}
+	return out
+}
+
+// MetricAlertConditionFromProto returns a MetricAlertCondition from a proto, or an empty
+// condition if there is no mapping.
+func MetricAlertConditionFromProto(c webhookv1.MetricAlertCondition) MetricAlertCondition {
+	switch c {
+	case webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_NAN:
+		return MetricAlertConditionNaN
+	case webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_ABOVE:
+		return MetricAlertConditionAbove
+	case webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_BELOW:
+		return MetricAlertConditionBelow
+	case webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_NO_IMPROVEMENT:
+		return MetricAlertConditionNoImprovement
+	case webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_DROP_PERCENT:
+		return MetricAlertConditionDropPercent
+	default:
+		return ""
+	}
+}
+
+// MetricAlertActionFromProto returns a MetricAlertAction from a proto, or an empty action if
+// there is no mapping.
+func MetricAlertActionFromProto(a webhookv1.MetricAlertAction) MetricAlertAction {
+	switch a {
+	case webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_WEBHOOK:
+		return MetricAlertActionWebhook
+	case webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_NOTE:
+		return MetricAlertActionNote
+	case webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_PAUSE:
+		return MetricAlertActionPause
+	case webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_KILL:
+		return MetricAlertActionKill
+	default:
+		return ""
+	}
+}
+
+// Proto returns a proto from a MetricAlertCondition.
+func (c MetricAlertCondition) Proto() webhookv1.MetricAlertCondition {
+	switch c {
+	case MetricAlertConditionNaN:
+		return webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_NAN
+	case MetricAlertConditionAbove:
+		return webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_ABOVE
+	case MetricAlertConditionBelow:
+		return webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_BELOW
+	case MetricAlertConditionNoImprovement:
+		return webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_NO_IMPROVEMENT
+	case MetricAlertConditionDropPercent:
+		return webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_DROP_PERCENT
+	default:
+		return webhookv1.MetricAlertCondition_METRIC_ALERT_CONDITION_UNSPECIFIED
+	}
+}
+
+// Proto returns a proto from a MetricAlertAction.
+func (a MetricAlertAction) Proto() webhookv1.MetricAlertAction {
+	switch a {
+	case MetricAlertActionWebhook:
+		return webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_WEBHOOK
+	case MetricAlertActionNote:
+		return webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_NOTE
+	case MetricAlertActionPause:
+		return webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_PAUSE
+	case MetricAlertActionKill:
+		return webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_KILL
+	default:
+		return webhookv1.MetricAlertAction_METRIC_ALERT_ACTION_UNSPECIFIED
+	}
+}
+
+// lookback returns how many of the most recent reports the rule needs to evaluate.
+func (r *MetricAlertRule) lookback() int {
+	switch r.Condition {
+	case MetricAlertConditionNoImprovement:
+		return r.Window
+	case MetricAlertConditionDropPercent:
+		return r.Window + 1
+	default:
+		return 1
+	}
+}
+
+// better reports whether a is a better value of the rule's metric than b.
+func (r *MetricAlertRule) better(a, b float64) bool {
+	if r.SmallerIsBetter {
+		return a < b
+	}
+	return a > b
+}
+
+// evaluate checks the rule against the most recent values of its metric, newest first, and,
+// for NO_IMPROVEMENT conditions, the best value reported before them. It returns a description
+// of why the rule fired, or an empty string if it did not.
+func (r *MetricAlertRule) evaluate(recent []float64, priorBest *float64) string {
+	if len(recent) == 0 || len(recent) < r.lookback() {
+		return ""
+	}
+	latest := recent[0]
+	switch r.Condition {
+	case MetricAlertConditionNaN:
+		if math.IsNaN(latest) || math.IsInf(latest, 0) {
+			return fmt.Sprintf("%s is %v", r.MetricName, latest)
+		}
+	case MetricAlertConditionAbove:
+		if latest > r.Threshold {
+			return fmt.Sprintf("%s is %v, above %v", r.MetricName, latest, r.Threshold)
+		}
+	case MetricAlertConditionBelow:
+		if latest < r.Threshold {
+			return fmt.Sprintf("%s is %v, below %v", r.MetricName, latest, r.Threshold)
+		}
+	case MetricAlertConditionNoImprovement:
+		if priorBest == nil || math.IsNaN(*priorBest) {
+			return ""
+		}
+		for _, v := range recent {
+			if r.better(v, *priorBest) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s has not improved on %v in %d reports",
+			r.MetricName, *priorBest, r.Window)
+	case MetricAlertConditionDropPercent:
+		var sum float64
+		for _, v := range recent[1:] {
+			sum += v
+		}
+		mean := sum / float64(len(recent)-1)
+		if mean > 0 && latest <= mean*(1-r.Threshold/100) {
+			return fmt.Sprintf("%s dropped to %v, %.0f%% below its mean of %v over %d reports",
+				r.MetricName, latest, 100*(1-latest/mean), mean, r.Window)
+		}
+	}
+	return ""
+}
+
+// MetricAlertEvents is a slice of MetricAlertEvent objects.
+type MetricAlertEvents []MetricAlertEvent
+
+// Proto converts a slice of events to its protobuf representation.
+func (es MetricAlertEvents) Proto() []*webhookv1.MetricAlertEvent {
+	out := make([]*webhookv1.MetricAlertEvent, len(es))
+	for i, e := range es {
+		out[i] = e.Proto()
+	}
+	return out
+}
+
+// MetricAlertEvent corresponds to a row in the "metric_alert_events" DB table. A rule fires at
+// most once per trial.
+type MetricAlertEvent struct {
+	bun.BaseModel `bun:"table:metric_alert_events"`
+
+	ID           int               `bun:"id,pk,autoincrement"`
+	RuleID       MetricAlertRuleID `bun:"rule_id,notnull"`
+	TrialID      int               `bun:"trial_id,notnull"`
+	TotalBatches int               `bun:"total_batches,notnull"`
+	Value        float64           `bun:"value,notnull"`
+	Message      string            `bun:"message,notnull"`
+	FiredAt      time.Time         `bun:"fired_at,notnull,default:current_timestamp"`
+}
+
+// Proto converts an event to its protobuf representation.
+func (e *MetricAlertEvent) Proto() *webhookv1.MetricAlertEvent {
+	return &webhookv1.MetricAlertEvent{
+		Id:           int32(e.ID),
+		RuleId:       int32(e.RuleID),
+		TrialId:      int32(e.TrialID),
+		TotalBatches: int32(e.TotalBatches),
+		Value:        e.Value,
+		Message:      e.Message,
+		FiredAt:      timestamppb.New(e.FiredAt),
+	}
+}
+
+// FiredMetricAlert is a rule that fired for a trial, returned so callers can apply actions
+// that need to reach the trial itself.
+type FiredMetricAlert struct {
+	Rule  MetricAlertRule
+	Event MetricAlertEvent
+}
@@ -0,0 +1,156 @@
+package webhooks
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestMetricAlertRuleEvaluate(t *testing.T) {
+	cases := []struct {
+		name      string
+		rule      MetricAlertRule
+		recent    []float64
+		priorBest *float64
+		fires     bool
+	}{
+		{
+			name:   "nan fires on nan",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionNaN},
+			recent: []float64{math.NaN()},
+			fires:  true,
+		},
+		{
+			name:   "nan fires on infinity",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionNaN},
+			recent: []float64{math.Inf(-1)},
+			fires:  true,
+		},
+		{
+			name:   "nan only checks the latest value",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionNaN},
+			recent: []float64{1, math.NaN()},
+		},
+		{
+			name:   "above",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionAbove, Threshold: 1},
+			recent: []float64{1.5},
+			fires:  true,
+		},
+		{
+			name:   "not above",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionAbove, Threshold: 1},
+			recent: []float64{1},
+		},
+		{
+			name:   "below",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionBelow, Threshold: 1},
+			recent: []float64{0.5},
+			fires:  true,
+		},
+		{
+			name: "no improvement",
+			rule: MetricAlertRule{
+				Condition: MetricAlertConditionNoImprovement, Window: 3, SmallerIsBetter: true,
+			},
+			recent:    []float64{0.5, 0.6, 0.5},
+			priorBest: ptrs.Ptr(0.5),
+			fires:     true,
+		},
+		{
+			name: "improvement",
+			rule: MetricAlertRule{
+				Condition: MetricAlertConditionNoImprovement, Window: 3, SmallerIsBetter: true,
+			},
+			recent:    []float64{0.6, 0.4, 0.7},
+			priorBest: ptrs.Ptr(0.5),
+		},
+		{
+			name: "improvement when larger is better",
+			rule: MetricAlertRule{
+				Condition: MetricAlertConditionNoImprovement, Window: 2, SmallerIsBetter: false,
+			},
+			recent:    []float64{0.4, 0.6},
+			priorBest: ptrs.Ptr(0.5),
+		},
+		{
+			name: "no improvement needs a full window",
+			rule: MetricAlertRule{
+				Condition: MetricAlertConditionNoImprovement, Window: 3, SmallerIsBetter: true,
+			},
+			recent:    []float64{0.6, 0.6},
+			priorBest: ptrs.Ptr(0.5),
+		},
+		{
+			name: "no improvement needs a prior best",
+			rule: MetricAlertRule{
+				Condition: MetricAlertConditionNoImprovement, Window: 1, SmallerIsBetter: true,
+			},
+			recent: []float64{0.6},
+		},
+		{
+			name:   "drop percent",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionDropPercent, Threshold: 50, Window: 2},
+			recent: []float64{50, 100, 100},
+			fires:  true,
+		},
+		{
+			name:   "small drop",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionDropPercent, Threshold: 50, Window: 2},
+			recent: []float64{70, 100, 140},
+		},
+		{
+			name:   "drop percent needs a full window",
+			rule:   MetricAlertRule{Condition: MetricAlertConditionDropPercent, Threshold: 50, Window: 2},
+			recent: []float64{10, 100},
+		},
+		{
+			name: "no values",
+			rule: MetricAlertRule{Condition: MetricAlertConditionNaN},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := tc.rule.evaluate(tc.recent, tc.priorBest)
+			require.Equal(t, tc.fires, msg != "", msg)
+		})
+	}
+}
+
+func TestMetricAlertRuleValidate(t *testing.T) {
+	valid := func() MetricAlertRule {
+		return MetricAlertRule{
+			ProjectID:   ptrs.Ptr(1),
+			MetricName:  "loss",
+			MetricGroup: model.TrainingMetricGroup,
+			Condition:   MetricAlertConditionNaN,
+			Action:      MetricAlertActionKill,
+		}
+	}
+	r := valid()
+	require.NoError(t, r.Validate())
+
+	r = valid()
+	r.ExperimentID = ptrs.Ptr(1)
+	require.ErrorContains(t, r.Validate(), "exactly one")
+
+	r = valid()
+	r.Condition = MetricAlertConditionNoImprovement
+	require.ErrorContains(t, r.Validate(), "window")
+
+	r = valid()
+	r.Condition = MetricAlertConditionDropPercent
+	r.Window = 5
+	require.ErrorContains(t, r.Validate(), "percentage")
+
+	r = valid()
+	r.Action = MetricAlertActionWebhook
+	require.ErrorContains(t, r.Validate(), "webhook_id")
+
+	r.WebhookID = ptrs.Ptr(WebhookID(1))
+	require.NoError(t, r.Validate())
+}
@@ -0,0 +1,301 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddMetricAlertRule adds a MetricAlertRule to the DB.
+func AddMetricAlertRule(ctx context.Context, r *MetricAlertRule) error {
+	_, err := db.Bun().NewInsert().Model(r).Exec(ctx)
+	return err
+}
+
+// GetMetricAlertRule returns a single MetricAlertRule from the DB.
+func GetMetricAlertRule(ctx context.Context, id MetricAlertRuleID) (*MetricAlertRule, error) {
+	rule := MetricAlertRule{}
+	if err := db.Bun().NewSelect().Model(&rule).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetMetricAlertRules returns the MetricAlertRules from the DB, optionally only those attached
+// to the given project or experiment.
+func GetMetricAlertRules(
+	ctx context.Context, projectID, experimentID *int,
+) (MetricAlertRules, error) {
+	rules := MetricAlertRules{}
+	q := db.Bun().NewSelect().Model(&rules).Order("id")
+	if projectID != nil {
+		q.Where("project_id = ?", *projectID)
+	}
+	if experimentID != nil {
+		q.Where("experiment_id = ?", *experimentID)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// DeleteMetricAlertRule deletes a MetricAlertRule and its events from the DB.
+func DeleteMetricAlertRule(ctx context.Context, id MetricAlertRuleID) error {
+	_, err := db.Bun().NewDelete().Model((*MetricAlertRule)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+// GetTrialMetricAlertEvents returns the MetricAlertEvents for a trial, oldest first.
+func GetTrialMetricAlertEvents(ctx context.Context, trialID int) (MetricAlertEvents, error) {
+	events := MetricAlertEvents{}
+	if err := db.Bun().NewSelect().Model(&events).
+		Where("trial_id = ?", trialID).
+		Order("fired_at", "id").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// EvaluateMetricAlerts checks the alert rules that apply to a trial against its metrics in the
+// given group, after new metrics were reported. Rules fire at most once per trial. WEBHOOK and
+// NOTE actions are carried out here; every rule that fired is returned so that the caller can
+// carry out actions on the trial itself.
+func EvaluateMetricAlerts(
+	ctx context.Context, trialID int, group model.MetricGroup,
+) ([]FiredMetricAlert, error) {
+	var rules []MetricAlertRule
+	if err := db.Bun().NewSelect().Model(&rules).
+		ColumnExpr("r.*").
+		Join("JOIN trials t ON t.id = ?", trialID).
+		Join("JOIN experiments e ON e.id = t.experiment_id").
+		Where("r.metric_group = ?", group).
+		Where("r.experiment_id = e.id OR r.project_id = e.project_id").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM metric_alert_events ev WHERE ev.rule_id = r.id AND ev.trial_id = t.id
+		)`).
+		ModelTableExpr("metric_alert_rules AS r").
+		Order("r.id").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting metric alert rules for trial %d: %w", trialID, err)
+	}
+
+	var fired []FiredMetricAlert
+	for _, r := range rules {
+		event, err := evaluateMetricAlertRule(ctx, trialID, r)
+		if err != nil {
+			return fired, fmt.Errorf("evaluating metric alert rule %d: %w", r.ID, err)
+		}
+		if event == nil {
+			continue
+		}
+
+		recorded, err := recordMetricAlertEvent(ctx, r, event)
+		if err != nil {
+			return fired, fmt.Errorf("acting on metric alert rule %d: %w", r.ID, err)
+		}
+		if recorded {
+			fired = append(fired, FiredMetricAlert{Rule: r, Event: *event})
+		}
+	}
+	return fired, nil
+}
+
+type metricAlertValue struct {
+	TotalBatches int     `bun:"total_batches"`
+	Value        float64 `bun:"value"`
+}
+
+// metricAlertValues returns a query selecting the numeric values of a rule's metric for a
+// trial, newest first.
+func metricAlertValues(trialID int, r MetricAlertRule) *bun.SelectQuery {
+	path := model.TrialMetricsJSONPath(r.MetricGroup == model.ValidationMetricGroup)
+	return db.Bun().NewSelect().Table("metrics").
+		Column("total_batches").
+		ColumnExpr("(metrics->?->>?)::float8 AS value", path, r.MetricName).
+		Where("trial_id = ?", trialID).
+		Where("metric_group = ?", r.MetricGroup).
+		Where("archived = false").
+		Where("(jsonb_typeof(metrics->?->?) = 'number' OR metrics->?->>? IN (?, ?, ?))",
+			path, r.MetricName, path, r.MetricName,
+			db.NaNPostgresString, db.InfPostgresString, db.NegInfPostgresString).
+		Order("total_batches DESC")
+}
+
+// evaluateMetricAlertRule checks a rule against a trial's metrics and returns the event if it
+// fires, or nil if it did not.
+func evaluateMetricAlertRule(
+	ctx context.Context, trialID int, r MetricAlertRule,
+) (*MetricAlertEvent, error) {
+	var recent []metricAlertValue
+	if err := metricAlertValues(trialID, r).Limit(r.lookback()).Scan(ctx, &recent); err != nil {
+		return nil, fmt.Errorf("getting recent metric values: %w", err)
+	}
+	values := make([]float64, len(recent))
+	for i, v := range recent {
+		values[i] = v.Value
+	}
+
+	var priorBest *float64
+	if r.Condition == MetricAlertConditionNoImprovement && len(recent) == r.lookback() {
+		agg := "max"
+		if r.SmallerIsBetter {
+			agg = "min"
+		}
+		var best sql.NullFloat64
+		prior := metricAlertValues(trialID, r).Offset(r.Window)
+		if err := db.Bun().NewSelect().
+			ColumnExpr(agg+"(p.value)").
+			TableExpr("(?) AS p", prior).
+			Where("p.value <> 'NaN'::float8").
+			Scan(ctx, &best); err != nil {
+			return nil, fmt.Errorf("getting prior best metric value: %w", err)
+		}
+		if best.Valid {
+			priorBest = &best.Float64
+		}
+	}
+
+	message := r.evaluate(values, priorBest)
+	if message == "" {
+		return nil, nil
+	}
+
+	return &MetricAlertEvent{
+		RuleID:       r.ID,
+		TrialID:      trialID,
+		TotalBatches: recent[0].TotalBatches,
+		Value:        recent[0].Value,
+		Message:      message,
+		FiredAt:      time.Now().UTC(),
+	}, nil
+}
+
+// recordMetricAlertEvent records an event together with its WEBHOOK or NOTE action in a single
+// transaction, so that neither is kept without the other. It returns false if the rule had
+// already fired for the trial.
+func recordMetricAlertEvent(ctx context.Context, r MetricAlertRule, e *MetricAlertEvent) (
+	bool, error,
+) {
+	var webhookEvent *Event
+	if r.Action == MetricAlertActionWebhook {
+		var err error
+		if webhookEvent, err = metricAlertWebhookEvent(ctx, r, *e); err != nil {
+			return false, err
+		}
+	}
+
+	var recorded bool
+	if err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewInsert().Model(e).
+			On("CONFLICT (rule_id, trial_id) DO NOTHING").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("recording metric alert event: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return nil
+		}
+
+		switch r.Action {
+		case MetricAlertActionWebhook:
+			_, err = tx.NewInsert().Model(webhookEvent).Exec(ctx)
+		case MetricAlertActionNote:
+			_, err = tx.NewUpdate().Table("trials").
+				Set("notes = notes || ?", metricAlertNote(r, *e)).
+				Where("id = ?", e.TrialID).
+				Exec(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		recorded = true
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	if recorded && webhookEvent != nil {
+		singletonShipper.Wake()
+	}
+	return recorded, nil
+}
+
+// metricAlertWebhookEvent returns the webhook event to send for a rule that fired.
+func metricAlertWebhookEvent(ctx context.Context, r MetricAlertRule, e MetricAlertEvent) (
+	*Event, error,
+) {
+	w, err := GetWebhook(ctx, int(*r.WebhookID))
+	if err != nil {
+		return nil, fmt.Errorf("getting webhook %d: %w", *r.WebhookID, err)
+	}
+
+	var p []byte
+	switch w.WebhookType {
+	case WebhookTypeSlack:
+		p, err = json.Marshal(SlackMessageBody{
+			Blocks: []SlackBlock{{
+				Type: "section",
+				Text: SlackField{
+					Type: "plain_text",
+					Text: fmt.Sprintf("Trial %d: %s", e.TrialID, e.Message),
+				},
+			}},
+		})
+	default:
+		p, err = json.Marshal(EventPayload{
+			ID:        uuid.New(),
+			Type:      TriggerTypeMetricThresholdExceeded,
+			Timestamp: e.FiredAt.Unix(),
+			Data: EventData{
+				MetricAlert: &MetricAlertPayload{
+					RuleID:       r.ID,
+					TrialID:      e.TrialID,
+					MetricName:   r.MetricName,
+					MetricGroup:  r.MetricGroup,
+					Condition:    r.Condition,
+					TotalBatches: e.TotalBatches,
+					Value:        jsonFloat(e.Value),
+					Message:      e.Message,
+				},
+			},
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error generating event payload: %w", err)
+	}
+	return &Event{Payload: p, URL: w.URL}, nil
+}
+
+// metricAlertNote returns the line appended to a trial's notes when a NOTE rule fires for it.
+func metricAlertNote(r MetricAlertRule, e MetricAlertEvent) string {
+	return fmt.Sprintf("%s: metric alert rule %d fired after %d batches: %s\n",
+		e.FiredAt.Format(time.RFC3339), r.ID, e.TotalBatches, e.Message)
+}
+
+// jsonFloat returns a JSON-marshalable representation of f, spelling out special values the
+// way they are stored in metrics.
+func jsonFloat(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return db.NaNPostgresString
+	case math.IsInf(f, 1):
+		return db.InfPostgresString
+	case math.IsInf(f, -1):
+		return db.NegInfPostgresString
+	default:
+		return f
+	}
+}
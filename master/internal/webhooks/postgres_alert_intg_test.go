@@ -0,0 +1,128 @@
+//go:build integration
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+func TestEvaluateMetricAlerts(t *testing.T) {
+	require.NoError(t, etc.SetRootPath(db.RootFromDB))
+	ctx := context.Background()
+	pgDB := db.MustResolveTestPostgres(t)
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+	clearWebhooksTables(ctx, t)
+
+	singletonShipper = &shipper{wake: make(chan<- struct{})} // mock shipper
+
+	user := db.RequireMockUser(t, pgDB)
+	exp := db.RequireMockExperiment(t, pgDB, user)
+	trial, _ := db.RequireMockTrial(t, pgDB, exp)
+
+	report := func(step int, loss any) {
+		metrics, err := structpb.NewStruct(map[string]any{"loss": loss})
+		require.NoError(t, err)
+		require.NoError(t, pgDB.AddTrialMetrics(ctx, &trialv1.TrialMetrics{
+			TrialId:        int32(trial.ID),
+			StepsCompleted: int32(step),
+			Metrics:        &commonv1.Metrics{AvgMetrics: metrics},
+		}, model.TrainingMetricGroup))
+	}
+
+	w := mockWebhook()
+	require.NoError(t, AddWebhook(ctx, w))
+	above := &MetricAlertRule{
+		ExperimentID: &exp.ID,
+		MetricName:   "loss",
+		MetricGroup:  model.TrainingMetricGroup,
+		Condition:    MetricAlertConditionAbove,
+		Threshold:    10,
+		Action:       MetricAlertActionWebhook,
+		WebhookID:    &w.ID,
+	}
+	require.NoError(t, AddMetricAlertRule(ctx, above))
+	stalled := &MetricAlertRule{
+		ProjectID:       &exp.ProjectID,
+		MetricName:      "loss",
+		MetricGroup:     model.TrainingMetricGroup,
+		Condition:       MetricAlertConditionNoImprovement,
+		Window:          2,
+		SmallerIsBetter: true,
+		Action:          MetricAlertActionKill,
+	}
+	require.NoError(t, AddMetricAlertRule(ctx, stalled))
+	nan := &MetricAlertRule{
+		ExperimentID: &exp.ID,
+		MetricName:   "loss",
+		MetricGroup:  model.TrainingMetricGroup,
+		Condition:    MetricAlertConditionNaN,
+		Action:       MetricAlertActionNote,
+	}
+	require.NoError(t, AddMetricAlertRule(ctx, nan))
+
+	rules, err := GetMetricAlertRules(ctx, ptrs.Ptr(exp.ProjectID), nil)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, stalled.ID, rules[0].ID)
+
+	startCount, err := CountEvents(ctx)
+	require.NoError(t, err)
+
+	t.Log("improving values below the threshold fire nothing")
+	for i, loss := range []any{5, 4, 3} {
+		report(i+1, loss)
+		fired, err := EvaluateMetricAlerts(ctx, trial.ID, model.TrainingMetricGroup)
+		require.NoError(t, err)
+		require.Empty(t, fired)
+	}
+
+	t.Log("a value above the threshold fires the webhook rule")
+	report(4, 11)
+	fired, err := EvaluateMetricAlerts(ctx, trial.ID, model.TrainingMetricGroup)
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+	require.Equal(t, above.ID, fired[0].Rule.ID)
+	require.Equal(t, 4, fired[0].Event.TotalBatches)
+	endCount, err := CountEvents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, startCount+1, endCount)
+
+	t.Log("rules fire at most once per trial, and NaNs don't count as improvements")
+	report(5, db.NaNPostgresString)
+	fired, err = EvaluateMetricAlerts(ctx, trial.ID, model.TrainingMetricGroup)
+	require.NoError(t, err)
+	require.Len(t, fired, 2)
+	require.Equal(t, stalled.ID, fired[0].Rule.ID)
+	require.Equal(t, MetricAlertActionKill, fired[0].Rule.Action)
+	require.Equal(t, nan.ID, fired[1].Rule.ID)
+
+	t.Log("NOTE rules append to the trial's notes")
+	var notes string
+	require.NoError(t, db.Bun().NewSelect().Table("trials").Column("notes").
+		Where("id = ?", trial.ID).Scan(ctx, &notes))
+	require.Contains(t, notes, "loss is NaN")
+
+	events, err := GetTrialMetricAlertEvents(ctx, trial.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, above.ID, events[0].RuleID)
+	require.Equal(t, stalled.ID, events[1].RuleID)
+	require.Equal(t, nan.ID, events[2].RuleID)
+
+	t.Log("deleting a rule deletes its events")
+	require.NoError(t, DeleteMetricAlertRule(ctx, above.ID))
+	events, err = GetTrialMetricAlertEvents(ctx, trial.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
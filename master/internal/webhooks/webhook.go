@@ -258,8 +258,9 @@ type Condition struct {
 
 // EventData represents the event_data for a webhook event.
 type EventData struct {
-	TestData   *string            `json:"data,omitempty"`
-	Experiment *ExperimentPayload `json:"experiment,omitempty"`
+	TestData    *string             `json:"data,omitempty"`
+	Experiment  *ExperimentPayload  `json:"experiment,omitempty"`
+	MetricAlert *MetricAlertPayload `json:"metric_alert,omitempty"`
 }
 
 // ExperimentPayload is the webhook request representation of an experiment.
@@ -273,3 +274,16 @@ type ExperimentPayload struct {
 	WorkspaceName string       `json:"workspace"`
 	ProjectName   string       `json:"project"`
 }
+
+// MetricAlertPayload is the webhook request representation of a metric alert rule firing.
+type MetricAlertPayload struct {
+	RuleID       MetricAlertRuleID    `json:"rule_id"`
+	TrialID      int                  `json:"trial_id"`
+	MetricName   string               `json:"metric_name"`
+	MetricGroup  model.MetricGroup    `json:"metric_group"`
+	Condition    MetricAlertCondition `json:"condition"`
+	TotalBatches int                  `json:"total_batches"`
+	// Value is a number, or "NaN", "Infinity" or "-Infinity".
+	Value   interface{} `json:"value"`
+	Message string      `json:"message"`
+}
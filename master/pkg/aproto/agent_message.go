@@ -31,12 +31,16 @@ type MasterSetAgentOptions struct {
 	MasterInfo           MasterInfo
 	LoggingOptions       model.LoggingConfig
 	ContainersToReattach []ContainerReattach
+	// PrefetchImages are the images the agent should pull ahead of the tasks that need them.
+	PrefetchImages []string
 }
 
 // StartContainer notifies the agent to start a container with the provided spec.
 type StartContainer struct {
 	Container cproto.Container
 	Spec      cproto.Spec
+	// TraceContext propagates the trace of the container's allocation to the agent.
+	TraceContext map[string]string
 }
 
 // SignalContainer notifies the agent to send the requested signal to the container.
@@ -75,6 +75,16 @@ func NewContainerExit(code ExitCode) *ContainerFailure {
 	}
 }
 
+// NewContainerOOMKilled returns a container failure for a container that was killed, with the
+// exit code, because it ran out of memory.
+func NewContainerOOMKilled(code ExitCode) *ContainerFailure {
+	return &ContainerFailure{
+		FailureType: ContainerOOMKilled,
+		ErrMsg:      errors.Errorf("%s: %d", ContainerOOMKilled, code).Error(),
+		ExitCode:    &code,
+	}
+}
+
 // ContainerExited returns a container failure with the encoded exit code. If the exit code is a
 // the zero value, no failure is returned.
 func ContainerExited(code ExitCode) ContainerStopped {
@@ -118,4 +128,7 @@ const (
 
 	// AgentError denotes that the agent failed to launch the container.
 	AgentError FailureType = "agent failed to launch the container"
+
+	// ContainerOOMKilled denotes that the container was killed because it ran out of memory.
+	ContainerOOMKilled FailureType = "container was killed because it ran out of memory"
 )
@@ -40,6 +40,8 @@ type MasterMessage struct {
 	ContainerStateChanged *ContainerStateChanged
 	ContainerLog          *ContainerLog
 	ContainerStatsRecord  *ContainerStatsRecord
+	DevicesHealthChanged  *DevicesHealthChanged
+	ImagesCached          *ImagesCached
 }
 
 // ContainerReattach is a struct describing containers that can be reattached.
@@ -69,7 +71,30 @@ type ID string
 type AgentStarted struct {
 	Version              string
 	Devices              []device.Device
+	Labels               map[string]string
 	ContainersReattached []ContainerReattachAck
+	// CPUs and Memory, in bytes, are the capacity of the agent's host, or 0 if it is unknown.
+	CPUs   int
+	Memory int64
+}
+
+// DevicesHealthChanged notifies the master of the health of the devices of the agent, as last
+// probed by the agent.
+type DevicesHealthChanged struct {
+	Devices []DeviceHealth
+}
+
+// DeviceHealth is the health of a device of the agent. Reason describes why an unhealthy device
+// is unhealthy.
+type DeviceHealth struct {
+	Device  device.Device
+	Healthy bool
+	Reason  string
+}
+
+// ImagesCached notifies the master of the images cached by the container runtime of the agent.
+type ImagesCached struct {
+	Images []string
 }
 
 // ContainerStateChanged notifies the master that the agent transitioned the container state.
@@ -26,4 +26,5 @@ type GetRPConfig struct{}
 type GetRPResponse struct {
 	AgentReconnectWait    model.Duration
 	MaxZeroSlotContainers int
+	PrefetchImages        []string
 }
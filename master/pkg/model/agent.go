@@ -3,6 +3,8 @@ package model
 import (
 	"time"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
@@ -12,15 +14,21 @@ import (
 
 // AgentSummary summarizes the state on an agent.
 type AgentSummary struct {
-	ID             string       `json:"id"`
-	RegisteredTime time.Time    `json:"registered_time"`
-	Slots          SlotsSummary `json:"slots"`
-	NumContainers  int          `json:"num_containers"`
-	ResourcePool   []string     `json:"resource_pool"`
-	Addresses      []string     `json:"addresses"`
-	Enabled        bool         `json:"enabled"`
-	Draining       bool         `json:"draining"`
-	Version        string       `json:"version"`
+	ID             string            `json:"id"`
+	RegisteredTime time.Time         `json:"registered_time"`
+	Slots          SlotsSummary      `json:"slots"`
+	NumContainers  int               `json:"num_containers"`
+	ResourcePool   []string          `json:"resource_pool"`
+	Addresses      []string          `json:"addresses"`
+	Enabled        bool              `json:"enabled"`
+	Draining       bool              `json:"draining"`
+	Version        string            `json:"version"`
+	CachedImages   []string          `json:"cached_images"`
+	Labels         map[string]string `json:"labels"`
+	// ReadyForMaintenance is whether the agent is draining and no containers remain on it.
+	ReadyForMaintenance bool `json:"ready_for_maintenance"`
+	// MigrationDeadline is when the allocations still migrating off of the agent are killed.
+	MigrationDeadline *time.Time `json:"migration_deadline,omitempty"`
 }
 
 // ToProto converts an agent summary to a proto struct.
@@ -35,6 +43,11 @@ func (a AgentSummary) ToProto() *agentv1.Agent {
 		}
 	}
 
+	var migrationDeadline *timestamppb.Timestamp
+	if a.MigrationDeadline != nil {
+		migrationDeadline = protoutils.ToTimestamp(*a.MigrationDeadline)
+	}
+
 	return &agentv1.Agent{
 		Id:             a.ID,
 		RegisteredTime: protoutils.ToTimestamp(a.RegisteredTime),
@@ -45,6 +58,11 @@ func (a AgentSummary) ToProto() *agentv1.Agent {
 		Enabled:        a.Enabled,
 		Draining:       a.Draining,
 		Version:        a.Version,
+		CachedImages:   a.CachedImages,
+		Labels:         a.Labels,
+
+		ReadyForMaintenance: a.ReadyForMaintenance,
+		MigrationDeadline:   migrationDeadline,
 	}
 }
 
@@ -61,16 +79,19 @@ type SlotSummary struct {
 	Enabled   bool              `json:"enabled"`
 	Container *cproto.Container `json:"container"`
 	Draining  bool              `json:"draining"`
+	// UnhealthyReason is why the agent reported the slot's device unhealthy, while it is.
+	UnhealthyReason string `json:"unhealthy_reason,omitempty"`
 }
 
 // ToProto converts a SlotSummary to its protobuf representation.
 func (s SlotSummary) ToProto() *agentv1.Slot {
 	return &agentv1.Slot{
-		Id:        s.ID,
-		Device:    s.Device.Proto(),
-		Enabled:   s.Enabled,
-		Container: s.Container.ToProto(),
-		Draining:  s.Draining,
+		Id:              s.ID,
+		Device:          s.Device.Proto(),
+		Enabled:         s.Enabled,
+		Container:       s.Container.ToProto(),
+		Draining:        s.Draining,
+		UnhealthyReason: s.UnhealthyReason,
 	}
 }
 
@@ -41,6 +41,14 @@ func (r ResourcesConfig) ToExpconf() expconf.ResourcesConfig {
 		RawResourcePool:   ptrs.Ptr(r.ResourcePool),
 		RawPriority:       r.Priority,
 		RawDevices:        r.Devices.ToExpconf(),
+		RawLabelSelectors: &expconf.LabelSelectorsConfig{
+			RawRequired:  r.LabelSelectors.Required,
+			RawPreferred: r.LabelSelectors.Preferred,
+		},
+		RawCPURequest:    r.CPURequest,
+		RawCPULimit:      r.CPULimit,
+		RawMemoryRequest: r.MemoryRequest,
+		RawMemoryLimit:   r.MemoryLimit,
 	})
 }
 
@@ -117,14 +125,15 @@ func (e Environment) ToExpconf() expconf.EnvironmentConfig {
 	proxyConf := e.ProxyPorts.ToExpconf()
 
 	return schemas.WithDefaults(expconf.EnvironmentConfig{
-		RawImage:                &image,
-		RawEnvironmentVariables: &vars,
-		RawProxyPorts:           &proxyConf,
-		RawPorts:                e.Ports,
-		RawRegistryAuth:         e.RegistryAuth,
-		RawForcePullImage:       ptrs.Ptr(e.ForcePullImage),
-		RawPodSpec:              (*expconf.PodSpec)(e.PodSpec),
-		RawAddCapabilities:      e.AddCapabilities,
-		RawDropCapabilities:     e.DropCapabilities,
+		RawImage:                  &image,
+		RawEnvironmentVariables:   &vars,
+		RawProxyPorts:             &proxyConf,
+		RawPorts:                  e.Ports,
+		RawRegistryAuth:           e.RegistryAuth,
+		RawForcePullImage:         ptrs.Ptr(e.ForcePullImage),
+		RawPodSpec:                (*expconf.PodSpec)(e.PodSpec),
+		RawPersistentVolumeClaims: ptrs.Ptr(e.PersistentVolumeClaims),
+		RawAddCapabilities:        e.AddCapabilities,
+		RawDropCapabilities:       e.DropCapabilities,
 	})
 }
@@ -2,11 +2,11 @@ package model
 
 import (
 	"encoding/json"
-	"fmt"
 
 	k8sV1 "k8s.io/api/core/v1"
 
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 
 	"github.com/docker/docker/api/types"
@@ -35,6 +35,8 @@ type Environment struct {
 	ForcePullImage bool              `json:"force_pull_image"`
 	PodSpec        *k8sV1.Pod        `json:"pod_spec"`
 
+	PersistentVolumeClaims expconf.PersistentVolumeClaimsConfig `json:"persistent_volume_claims"`
+
 	AddCapabilities  []string `json:"add_capabilities"`
 	DropCapabilities []string `json:"drop_capabilities"`
 }
@@ -89,7 +91,8 @@ func (r RuntimeItem) For(deviceType device.Type) string {
 	case device.ROCM:
 		return r.ROCM
 	default:
-		panic(fmt.Sprintf("unexpected device type: %s", deviceType))
+		// Other device types, e.g. those of agents' device plugins, use the CPU image.
+		return r.CPU
 	}
 }
 
@@ -143,7 +146,8 @@ func (r *RuntimeItems) For(deviceType device.Type) []string {
 	case device.ROCM:
 		return r.ROCM
 	default:
-		panic(fmt.Sprintf("unexpected device type: %s", deviceType))
+		// As with images, other device types use the CPU variables.
+		return r.CPU
 	}
 }
 
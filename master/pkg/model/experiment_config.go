@@ -93,10 +93,23 @@ type ResourcesConfig struct {
 
 	Devices DevicesConfig `json:"devices"`
 
+	LabelSelectors LabelSelectorsConfig `json:"label_selectors"`
+
+	CPURequest    *float64 `json:"cpu_request,omitempty"`
+	CPULimit      *float64 `json:"cpu_limit,omitempty"`
+	MemoryRequest *int     `json:"memory_request,omitempty"`
+	MemoryLimit   *int     `json:"memory_limit,omitempty"`
+
 	// Deprecated: Use ResourcePool instead.
 	AgentLabel string `json:"agent_label,omitempty"`
 }
 
+// LabelSelectorsConfig selects the agents or Kubernetes nodes a task may run on by their labels.
+type LabelSelectorsConfig struct {
+	Required  map[string]string `json:"required"`
+	Preferred map[string]string `json:"preferred"`
+}
+
 // StorageSize is a named type for custom marshaling behavior for shm_size.
 type StorageSize int64
 
@@ -164,6 +177,14 @@ func (r ResourcesConfig) Validate() []error {
 	errs := []error{
 		check.GreaterThanOrEqualTo(r.Slots, 0, "slots must be >= 0"),
 		check.GreaterThan(r.Weight, float64(0), "weight must be > 0"),
+		check.GreaterThan(r.CPURequest, float64(0), "cpu_request must be > 0"),
+		check.GreaterThan(r.CPULimit, float64(0), "cpu_limit must be > 0"),
+		check.GreaterThan(r.MemoryRequest, 0, "memory_request must be > 0"),
+		check.GreaterThan(r.MemoryLimit, 0, "memory_limit must be > 0"),
+		check.LessThanOrEqualTo(r.CPURequest, r.CPULimit, "cpu_request must be <= cpu_limit"),
+		check.LessThanOrEqualTo(
+			r.MemoryRequest, r.MemoryLimit, "memory_request must be <= memory_limit",
+		),
 	}
 	errs = append(errs, ValidatePrioritySetting(r.Priority)...)
 	return errs
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+)
+
+// HistogramMetricType is the type of histogram metrics, both in the stored metric value and in
+// trial summary metrics.
+const HistogramMetricType = "histogram"
+
+// HistogramQuantiles are the quantiles of the last reported histogram recorded in trial summary
+// metrics, by summary key.
+var HistogramQuantiles = []struct {
+	Key      string
+	Quantile float64
+}{
+	{"p5", 0.05},
+	{"p25", 0.25},
+	{"p50", 0.5},
+	{"p75", 0.75},
+	{"p95", 0.95},
+}
+
+// Histogram is a metric holding the distribution of a set of values, such as the weights or
+// gradients of a layer. It is stored alongside scalar metrics as
+// {"type": "histogram", "bucket_edges": [...], "counts": [...]}.
+type Histogram struct {
+	BucketEdges []float64
+	Counts      []float64
+}
+
+// HistogramFromProto returns the histogram of a proto histogram.
+func HistogramFromProto(h *commonv1.Histogram) Histogram {
+	return Histogram{BucketEdges: h.BucketEdges, Counts: h.Counts}
+}
+
+// HistogramFromJSON returns the histogram stored as v, and false if v is not a histogram.
+func HistogramFromJSON(v any) (Histogram, bool) {
+	obj, ok := v.(map[string]any)
+	if !ok || obj["type"] != HistogramMetricType {
+		return Histogram{}, false
+	}
+	edges, ok := jsonFloats(obj["bucket_edges"])
+	if !ok {
+		return Histogram{}, false
+	}
+	counts, ok := jsonFloats(obj["counts"])
+	if !ok {
+		return Histogram{}, false
+	}
+	return Histogram{BucketEdges: edges, Counts: counts}, true
+}
+
+func jsonFloats(v any) ([]float64, bool) {
+	values, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	floats := make([]float64, 0, len(values))
+	for _, v := range values {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, false
+		}
+		floats = append(floats, f)
+	}
+	return floats, true
+}
+
+// Validate checks that the histogram has at least one bucket, finite and strictly increasing
+// edges, and a finite, non-negative count for each bucket.
+func (h Histogram) Validate() error {
+	if len(h.Counts) == 0 {
+		return errors.New("histogram must have at least one bucket")
+	}
+	if len(h.BucketEdges) != len(h.Counts)+1 {
+		return errors.Errorf("histogram has %d bucket edges for %d buckets, expected %d",
+			len(h.BucketEdges), len(h.Counts), len(h.Counts)+1)
+	}
+	for i, e := range h.BucketEdges {
+		if math.IsNaN(e) || math.IsInf(e, 0) {
+			return errors.New("histogram bucket edges must be finite")
+		}
+		if i > 0 && e <= h.BucketEdges[i-1] {
+			return errors.New("histogram bucket edges must be strictly increasing")
+		}
+	}
+	for _, c := range h.Counts {
+		if math.IsNaN(c) || math.IsInf(c, 0) || c < 0 {
+			return errors.New("histogram counts must be finite and non-negative")
+		}
+	}
+	return nil
+}
+
+// ToJSON returns the histogram in the form it is stored in.
+func (h Histogram) ToJSON() map[string]any {
+	edges := make([]any, 0, len(h.BucketEdges))
+	for _, e := range h.BucketEdges {
+		edges = append(edges, e)
+	}
+	counts := make([]any, 0, len(h.Counts))
+	for _, c := range h.Counts {
+		counts = append(counts, c)
+	}
+	return map[string]any{
+		"type":         HistogramMetricType,
+		"bucket_edges": edges,
+		"counts":       counts,
+	}
+}
+
+// ToProto returns the proto representation of the histogram.
+func (h Histogram) ToProto() *commonv1.Histogram {
+	return &commonv1.Histogram{BucketEdges: h.BucketEdges, Counts: h.Counts}
+}
+
+// Quantile estimates the q-th quantile of the histogram's values, assuming values are uniformly
+// distributed within each bucket. It returns NaN for an empty histogram.
+func (h Histogram) Quantile(q float64) float64 {
+	var total float64
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total == 0 || len(h.BucketEdges) != len(h.Counts)+1 {
+		return math.NaN()
+	}
+
+	target := q * total
+	var seen float64
+	for i, c := range h.Counts {
+		if c > 0 && seen+c >= target {
+			lo, hi := h.BucketEdges[i], h.BucketEdges[i+1]
+			return lo + (hi-lo)*math.Max(target-seen, 0)/c
+		}
+		seen += c
+	}
+	return h.BucketEdges[len(h.BucketEdges)-1]
+}
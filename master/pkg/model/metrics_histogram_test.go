@@ -0,0 +1,53 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramValidate(t *testing.T) {
+	valid := Histogram{BucketEdges: []float64{-1, 0, 1}, Counts: []float64{2, 0}}
+	require.NoError(t, valid.Validate())
+
+	for _, h := range []Histogram{
+		{},
+		{BucketEdges: []float64{0, 1}, Counts: []float64{1, 1}},
+		{BucketEdges: []float64{0, 0}, Counts: []float64{1}},
+		{BucketEdges: []float64{0, math.Inf(1)}, Counts: []float64{1}},
+		{BucketEdges: []float64{0, 1}, Counts: []float64{-1}},
+		{BucketEdges: []float64{0, 1}, Counts: []float64{math.NaN()}},
+	} {
+		require.Error(t, h.Validate(), "%+v", h)
+	}
+}
+
+func TestHistogramJSON(t *testing.T) {
+	h := Histogram{BucketEdges: []float64{0, 1, 2}, Counts: []float64{3, 4}}
+	parsed, ok := HistogramFromJSON(h.ToJSON())
+	require.True(t, ok)
+	require.Equal(t, h, parsed)
+
+	for _, v := range []any{
+		1.0,
+		map[string]any{"bucket_edges": []any{0.0, 1.0}, "counts": []any{1.0}},
+		map[string]any{"type": "histogram", "bucket_edges": []any{0.0, "1"}, "counts": []any{1.0}},
+	} {
+		_, ok := HistogramFromJSON(v)
+		require.False(t, ok, "%v", v)
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := Histogram{BucketEdges: []float64{0, 10, 20, 30}, Counts: []float64{5, 0, 15}}
+	require.Equal(t, 0.0, h.Quantile(0))
+	require.Equal(t, 4.0, h.Quantile(0.1))
+	require.Equal(t, 10.0, h.Quantile(0.25))
+	require.InDelta(t, 23.333, h.Quantile(0.5), 0.001)
+	require.Equal(t, 30.0, h.Quantile(1))
+
+	require.True(t, math.IsNaN(Histogram{
+		BucketEdges: []float64{0, 1}, Counts: []float64{0},
+	}.Quantile(0.5)))
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/uptrace/bun"
 )
 
 // Model represents a row from the `models` table.
@@ -34,6 +36,31 @@ type ModelVersion struct {
 	Comment         string    `db:"comment" json:"comment"`
 	Notes           string    `db:"readme" json:"notes"`
 	Username        string    `db:"username" json:"username"`
+	Stage           string    `db:"stage" json:"stage"`
+}
+
+// ModelVersionStageTransition represents a row from the `model_version_stage_transitions` table.
+type ModelVersionStageTransition struct {
+	bun.BaseModel `bun:"table:model_version_stage_transitions"`
+
+	ID             int       `bun:"id,pk,autoincrement"`
+	ModelVersionID int       `bun:"model_version_id"`
+	FromStage      string    `bun:"from_stage"`
+	ToStage        string    `bun:"to_stage"`
+	UserID         UserID    `bun:"user_id"`
+	Reason         string    `bun:"reason"`
+	TransitionTime time.Time `bun:"transition_time"`
+}
+
+// ModelVersionProvenance represents a row from the `model_version_provenance` table.
+type ModelVersionProvenance struct {
+	bun.BaseModel `bun:"table:model_version_provenance"`
+
+	ID             int                    `bun:"id,pk,autoincrement"`
+	ModelVersionID int                    `bun:"model_version_id"`
+	DatasetURI     string                 `bun:"dataset_uri"`
+	DatasetHash    string                 `bun:"dataset_hash"`
+	Metadata       map[string]interface{} `bun:"metadata"`
 }
 
 // InstanceState is an enum type that describes an instance state.
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// AllocationIDKey is the span attribute linking the spans of an allocation's lifecycle, on the
+// master and on agents, to the allocation.
+const AllocationIDKey = attribute.Key("determined.allocation_id")
+
+// InjectTraceContext returns the trace context of ctx in a form that can be sent to another
+// process. It is empty if tracing is not configured.
+func InjectTraceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// ExtractTraceContext returns ctx with the trace context produced by InjectTraceContext, so that
+// spans started from it continue the trace.
+func ExtractTraceContext(ctx context.Context, traceContext map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(traceContext))
+}
@@ -2,7 +2,6 @@ package expconf
 
 import (
 	"encoding/json"
-	"fmt"
 
 	k8sV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
@@ -68,6 +67,8 @@ type EnvironmentConfigV0 struct {
 	RawForcePullImage *bool             `json:"force_pull_image"`
 	RawPodSpec        *PodSpec          `json:"pod_spec"`
 
+	RawPersistentVolumeClaims *PersistentVolumeClaimsConfigV0 `json:"persistent_volume_claims"`
+
 	RawAddCapabilities  []string `json:"add_capabilities"`
 	RawDropCapabilities []string `json:"drop_capabilities"`
 }
@@ -143,7 +144,9 @@ func (e EnvironmentImageMapV0) For(deviceType device.Type) string {
 	case device.ROCM:
 		return *e.RawROCM
 	default:
-		panic(fmt.Sprintf("unexpected device type: %s", deviceType))
+		// Devices of types the master doesn't know, such as those discovered by agents' device
+		// plugins, use the CPU configuration.
+		return *e.RawCPU
 	}
 }
 
@@ -232,7 +235,8 @@ func (e EnvironmentVariablesMapV0) For(deviceType device.Type) []string {
 	case device.ROCM:
 		return e.RawROCM
 	default:
-		panic(fmt.Sprintf("unexpected device type: %s", deviceType))
+		// As with images, unknown device types use the CPU variables.
+		return e.RawCPU
 	}
 }
 
@@ -268,3 +272,40 @@ func (b ProxyPortsConfigV0) Merge(other ProxyPortsConfigV0) ProxyPortsConfigV0 {
 	}
 	return out
 }
+
+// PersistentVolumeClaimV0 configures a dynamically provisioned volume for a task on Kubernetes.
+//
+//go:generate ../gen.sh
+type PersistentVolumeClaimV0 struct {
+	RawName         string  `json:"name"`
+	RawMountPath    string  `json:"mount_path"`
+	RawSize         string  `json:"size"`
+	RawStorageClass *string `json:"storage_class"`
+	RawAccessMode   *string `json:"access_mode"`
+	RawScope        *string `json:"scope"`
+}
+
+// PersistentVolumeClaimsConfigV0 is the configuration for persistent volume claims.
+//
+//go:generate ../gen.sh
+type PersistentVolumeClaimsConfigV0 []PersistentVolumeClaimV0
+
+// Merge implemenets the mergable interface.
+func (p PersistentVolumeClaimsConfigV0) Merge(
+	other PersistentVolumeClaimsConfigV0,
+) PersistentVolumeClaimsConfigV0 {
+	out := PersistentVolumeClaimsConfigV0{}
+	out = append(out, p...)
+
+	// Prevent duplicate mount paths as a result of the merge.
+	paths := map[string]bool{}
+	for _, claim := range p {
+		paths[claim.MountPath()] = true
+	}
+	for _, claim := range other {
+		if _, ok := paths[claim.MountPath()]; !ok {
+			out = append(out, claim)
+		}
+	}
+	return out
+}
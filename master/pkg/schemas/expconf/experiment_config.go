@@ -218,6 +218,43 @@ type ResourcesConfigV0 struct {
 	RawPriority       *int     `json:"priority"`
 
 	RawDevices DevicesConfigV0 `json:"devices"`
+
+	RawLabelSelectors *LabelSelectorsConfigV0 `json:"label_selectors"`
+
+	// The CPUs and memory, in bytes, reserved for and available to each of the task's containers.
+	// Requests default to the limits; only the agent resource manager enforces them.
+	RawCPURequest    *float64 `json:"cpu_request"`
+	RawCPULimit      *float64 `json:"cpu_limit"`
+	RawMemoryRequest *int     `json:"memory_request"`
+	RawMemoryLimit   *int     `json:"memory_limit"`
+}
+
+// HostResourceRequests returns the CPUs and memory, in bytes, to reserve on the agent for each of
+// the task's containers; each request defaults to its limit, and is 0 if neither is set.
+func (r ResourcesConfigV0) HostResourceRequests() (cpus float64, memory int64) {
+	switch {
+	case r.RawCPURequest != nil:
+		cpus = *r.RawCPURequest
+	case r.RawCPULimit != nil:
+		cpus = *r.RawCPULimit
+	}
+	switch {
+	case r.RawMemoryRequest != nil:
+		memory = int64(*r.RawMemoryRequest)
+	case r.RawMemoryLimit != nil:
+		memory = int64(*r.RawMemoryLimit)
+	}
+	return cpus, memory
+}
+
+// LabelSelectorsConfigV0 selects the agents or Kubernetes nodes a task may run on by their labels.
+// A task only runs where all of its required labels match, and prefers where more of its
+// preferred labels match.
+//
+//go:generate ../gen.sh
+type LabelSelectorsConfigV0 struct {
+	RawRequired  map[string]string `json:"required"`
+	RawPreferred map[string]string `json:"preferred"`
 }
 
 // OptimizationsConfigV0 is a legacy config value.
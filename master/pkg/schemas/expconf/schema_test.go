@@ -143,6 +143,10 @@ func objectForURL(url string) interface{} {
 		return &EnvironmentConfigV0{}
 	case "http://determined.ai/schemas/expconf/v0/resources.json":
 		return &ResourcesConfigV0{}
+	case "http://determined.ai/schemas/expconf/v0/label-selectors.json":
+		return &LabelSelectorsConfigV0{}
+	case "http://determined.ai/schemas/expconf/v0/persistent-volume-claim.json":
+		return &PersistentVolumeClaimV0{}
 	// For union member schemas, just return the union type.
 	case "http://determined.ai/schemas/expconf/v0/searcher.json",
 		"http://determined.ai/schemas/expconf/v0/searcher-adaptive-asha.json",
@@ -611,6 +611,14 @@ var (
                 "type": "string"
             }
         },
+        "persistent_volume_claims": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/persistent-volume-claims.json"
+        },
         "pod_spec": {
             "type": [
                 "object",
@@ -1319,6 +1327,37 @@ var (
         }
     }
 }
+`)
+	textLabelSelectorsConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/label-selectors.json",
+    "title": "LabelSelectorsConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [],
+    "properties": {
+        "preferred": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": {},
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "required": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": {},
+            "additionalProperties": {
+                "type": "string"
+            }
+        }
+    }
+}
 `)
 	textLengthV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1457,6 +1496,80 @@ var (
         }
     }
 }
+`)
+	textPersistentVolumeClaimV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/persistent-volume-claim.json",
+    "title": "PersistentVolumeClaim",
+    "additionalProperties": false,
+    "required": [
+        "name",
+        "mount_path",
+        "size"
+    ],
+    "type": "object",
+    "properties": {
+        "name": {
+            "type": "string",
+            "checks": {
+                "name must be a lowercase RFC 1123 label": {
+                    "pattern": "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+                }
+            }
+        },
+        "mount_path": {
+            "type": "string",
+            "checks": {
+                "mount_path must be an absolute path": {
+                    "pattern": "^/"
+                }
+            }
+        },
+        "size": {
+            "type": "string",
+            "checks": {
+                "size must be a valid kubernetes quantity": {
+                    "pattern": "^([0-9]*[.])?[0-9]+([EPTGMK]i?|[eEPTGMk]|m)?$"
+                }
+            }
+        },
+        "storage_class": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "access_mode": {
+            "enum": [
+                null,
+                "ReadWriteOnce",
+                "ReadOnlyMany",
+                "ReadWriteMany",
+                "ReadWriteOncePod"
+            ],
+            "default": "ReadWriteOnce"
+        },
+        "scope": {
+            "enum": [
+                null,
+                "task",
+                "workspace"
+            ],
+            "default": "task"
+        }
+    }
+}
+`)
+	textPersistentVolumeClaimsConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/persistent-volume-claims.json",
+    "title": "PersistentVolumeClaimsConfig",
+    "type": "array",
+    "items": {
+        "$ref": "http://determined.ai/schemas/expconf/v0/persistent-volume-claim.json"
+    }
+}
 `)
 	textProfilingConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1648,6 +1761,22 @@ var (
             ],
             "default": null
         },
+        "cpu_limit": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "default": null
+        },
+        "cpu_request": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "default": null
+        },
         "devices": {
             "type": [
                 "array",
@@ -1656,6 +1785,14 @@ var (
             "default": [],
             "optionalRef": "http://determined.ai/schemas/expconf/v0/devices.json"
         },
+        "label_selectors": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": {},
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/label-selectors.json"
+        },
         "max_slots": {
             "type": [
                 "integer",
@@ -1663,6 +1800,22 @@ var (
             ],
             "default": null
         },
+        "memory_limit": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": null
+        },
+        "memory_request": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": null
+        },
         "native_parallel": {
             "type": [
                 "boolean",
@@ -1721,7 +1874,23 @@ var (
             ],
             "default": 1
         }
-    }
+    },
+    "allOf": [
+        {
+            "compareProperties": {
+                "type": "a<=b",
+                "a": "cpu_request",
+                "b": "cpu_limit"
+            }
+        },
+        {
+            "compareProperties": {
+                "type": "a<=b",
+                "a": "memory_request",
+                "b": "memory_limit"
+            }
+        }
+    ]
 }
 `)
 	textS3ConfigV0 = []byte(`{
@@ -3035,10 +3204,16 @@ var (
 
 	schemaKerberosConfigV0 interface{}
 
+	schemaLabelSelectorsConfigV0 interface{}
+
 	schemaLengthV0 interface{}
 
 	schemaOptimizationsConfigV0 interface{}
 
+	schemaPersistentVolumeClaimV0 interface{}
+
+	schemaPersistentVolumeClaimsConfigV0 interface{}
+
 	schemaProfilingConfigV0 interface{}
 
 	schemaProxyPortV0 interface{}
@@ -3598,6 +3773,26 @@ func ParsedKerberosConfigV0() interface{} {
 	return schemaKerberosConfigV0
 }
 
+func ParsedLabelSelectorsConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaLabelSelectorsConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaLabelSelectorsConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaLabelSelectorsConfigV0 != nil {
+		return schemaLabelSelectorsConfigV0
+	}
+	err := json.Unmarshal(textLabelSelectorsConfigV0, &schemaLabelSelectorsConfigV0)
+	if err != nil {
+		panic("invalid embedded json for LabelSelectorsConfigV0")
+	}
+	return schemaLabelSelectorsConfigV0
+}
+
 func ParsedLengthV0() interface{} {
 	cacheLock.RLock()
 	if schemaLengthV0 != nil {
@@ -3638,6 +3833,46 @@ func ParsedOptimizationsConfigV0() interface{} {
 	return schemaOptimizationsConfigV0
 }
 
+func ParsedPersistentVolumeClaimV0() interface{} {
+	cacheLock.RLock()
+	if schemaPersistentVolumeClaimV0 != nil {
+		cacheLock.RUnlock()
+		return schemaPersistentVolumeClaimV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaPersistentVolumeClaimV0 != nil {
+		return schemaPersistentVolumeClaimV0
+	}
+	err := json.Unmarshal(textPersistentVolumeClaimV0, &schemaPersistentVolumeClaimV0)
+	if err != nil {
+		panic("invalid embedded json for PersistentVolumeClaimV0")
+	}
+	return schemaPersistentVolumeClaimV0
+}
+
+func ParsedPersistentVolumeClaimsConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaPersistentVolumeClaimsConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaPersistentVolumeClaimsConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaPersistentVolumeClaimsConfigV0 != nil {
+		return schemaPersistentVolumeClaimsConfigV0
+	}
+	err := json.Unmarshal(textPersistentVolumeClaimsConfigV0, &schemaPersistentVolumeClaimsConfigV0)
+	if err != nil {
+		panic("invalid embedded json for PersistentVolumeClaimsConfigV0")
+	}
+	return schemaPersistentVolumeClaimsConfigV0
+}
+
 func ParsedProfilingConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaProfilingConfigV0 != nil {
@@ -4223,10 +4458,16 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textHyperparametersV0
 	url = "http://determined.ai/schemas/expconf/v0/kerberos.json"
 	cachedSchemaBytesMap[url] = textKerberosConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/label-selectors.json"
+	cachedSchemaBytesMap[url] = textLabelSelectorsConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/length.json"
 	cachedSchemaBytesMap[url] = textLengthV0
 	url = "http://determined.ai/schemas/expconf/v0/optimizations.json"
 	cachedSchemaBytesMap[url] = textOptimizationsConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/persistent-volume-claim.json"
+	cachedSchemaBytesMap[url] = textPersistentVolumeClaimV0
+	url = "http://determined.ai/schemas/expconf/v0/persistent-volume-claims.json"
+	cachedSchemaBytesMap[url] = textPersistentVolumeClaimsConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/profiling.json"
 	cachedSchemaBytesMap[url] = textProfilingConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/proxy-port.json"
@@ -44,3 +44,11 @@ const (
 
 	shellAuthorizedKeysFile = "/run/determined/ssh/authorized_keys_unmodified"
 )
+
+const (
+	// nanoCPUsPerCPU converts CPU limits to the units of Docker's NanoCPUs.
+	nanoCPUsPerCPU = 1e9
+	// cpuSharesPerCPU converts CPU requests to Docker's relative CPU shares, 1024 being the weight
+	// of a container with the default shares.
+	cpuSharesPerCPU = 1024
+)
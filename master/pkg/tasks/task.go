@@ -105,9 +105,10 @@ type TaskSpec struct {
 
 	ExtraProxyPorts expconf.ProxyPortsConfig
 
-	Workspace string
-	Project   string
-	Labels    []string
+	Workspace   string
+	WorkspaceID int
+	Project     string
+	Labels      []string
 	// Ports required by trial or commands and their respective base port values.
 	UniqueExposedPortRequests map[string]int
 }
@@ -257,6 +258,16 @@ func (t *TaskSpec) ToDockerSpec() cproto.Spec {
 		})
 	}
 
+	// Requests are converted to the relative CPU shares and the soft memory limit of the container.
+	cpuRequest, memoryRequest := resources.HostResourceRequests()
+	var nanoCPUs, memoryLimit int64
+	if cpuLimit := resources.CPULimit(); cpuLimit != nil {
+		nanoCPUs = int64(*cpuLimit * nanoCPUsPerCPU)
+	}
+	if limit := resources.MemoryLimit(); limit != nil {
+		memoryLimit = int64(*limit)
+	}
+
 	runArchives, rootArchives := t.Archives()
 	spec := cproto.Spec{
 		TaskType: string(t.TaskType),
@@ -282,7 +293,11 @@ func (t *TaskSpec) ToDockerSpec() cproto.Spec {
 				CapDrop:         env.DropCapabilities(),
 
 				Resources: docker.Resources{
-					Devices: devices,
+					Devices:           devices,
+					CPUShares:         int64(cpuRequest * cpuSharesPerCPU),
+					NanoCPUs:          nanoCPUs,
+					Memory:            memoryLimit,
+					MemoryReservation: memoryRequest,
 				},
 			},
 			Archives:   append(runArchives, rootArchives...),
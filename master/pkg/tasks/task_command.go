@@ -116,6 +116,8 @@ func (s GenericCommandSpec) ToTaskSpec() TaskSpec {
 
 	res.TaskType = s.TaskType
 
+	res.WorkspaceID = int(s.Metadata.WorkspaceID)
+
 	// Evict the context from memory after starting the command as it is no longer needed. We
 	// evict as soon as possible to prevent the master from hitting an OOM.
 	// TODO: Consider not storing the userFiles in memory at all.
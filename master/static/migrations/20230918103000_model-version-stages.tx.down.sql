@@ -0,0 +1,7 @@
+DROP TABLE public.model_version_stage_transitions;
+
+DROP INDEX IF EXISTS ix_model_versions_production_stage;
+
+ALTER TABLE public.model_versions DROP COLUMN stage;
+
+DROP TYPE public.model_version_stage;
@@ -0,0 +1,27 @@
+CREATE TYPE public.model_version_stage AS ENUM (
+    'MODEL_VERSION_STAGE_NONE',
+    'MODEL_VERSION_STAGE_STAGING',
+    'MODEL_VERSION_STAGE_PRODUCTION',
+    'MODEL_VERSION_STAGE_ARCHIVED'
+);
+
+ALTER TABLE public.model_versions
+    ADD COLUMN stage model_version_stage NOT NULL DEFAULT 'MODEL_VERSION_STAGE_NONE';
+
+-- A model may have at most one version in production at a time.
+CREATE UNIQUE INDEX ix_model_versions_production_stage ON public.model_versions
+    USING btree (model_id) WHERE stage = 'MODEL_VERSION_STAGE_PRODUCTION';
+
+-- History of who moved a model version between stages and why.
+CREATE TABLE public.model_version_stage_transitions (
+    id serial PRIMARY KEY,
+    model_version_id int REFERENCES public.model_versions(id) ON DELETE CASCADE NOT NULL,
+    from_stage model_version_stage NOT NULL,
+    to_stage model_version_stage NOT NULL,
+    user_id int REFERENCES public.users(id) NULL,
+    reason text NOT NULL DEFAULT '',
+    transition_time timestamptz NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX ix_model_version_stage_transitions_model_version_id
+    ON public.model_version_stage_transitions USING btree (model_version_id);
@@ -0,0 +1 @@
+DROP TABLE model_version_provenance;
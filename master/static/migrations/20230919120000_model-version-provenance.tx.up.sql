@@ -0,0 +1,10 @@
+CREATE TABLE model_version_provenance (
+    id serial PRIMARY KEY,
+    model_version_id integer NOT NULL REFERENCES model_versions(id) ON DELETE CASCADE,
+    dataset_uri text NOT NULL,
+    dataset_hash text NOT NULL DEFAULT '',
+    metadata jsonb NOT NULL DEFAULT '{}'::jsonb
+);
+
+CREATE INDEX ix_model_version_provenance_model_version_id
+    ON model_version_provenance USING btree (model_version_id);
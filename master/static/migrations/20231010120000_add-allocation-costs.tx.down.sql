@@ -0,0 +1 @@
+DROP TABLE allocation_costs;
@@ -0,0 +1,10 @@
+CREATE TABLE allocation_costs (
+    allocation_id text PRIMARY KEY REFERENCES allocations(allocation_id) ON DELETE CASCADE,
+    slot_hour_cost double precision NOT NULL,
+    user_id integer REFERENCES users(id) ON DELETE SET NULL,
+    workspace_id integer REFERENCES workspaces(id) ON DELETE SET NULL,
+    project_id integer REFERENCES projects(id) ON DELETE SET NULL,
+    labels text[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX ix_allocation_costs_workspace_id ON allocation_costs USING btree (workspace_id);
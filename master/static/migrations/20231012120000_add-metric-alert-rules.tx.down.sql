@@ -0,0 +1,4 @@
+DROP TABLE metric_alert_events;
+DROP TABLE metric_alert_rules;
+DROP TYPE public.metric_alert_action;
+DROP TYPE public.metric_alert_condition;
@@ -0,0 +1,46 @@
+CREATE TYPE public.metric_alert_condition AS ENUM (
+  'NAN',
+  'ABOVE',
+  'BELOW',
+  'NO_IMPROVEMENT',
+  'DROP_PERCENT'
+);
+
+CREATE TYPE public.metric_alert_action AS ENUM (
+  'WEBHOOK',
+  'NOTE',
+  'PAUSE',
+  'KILL'
+);
+
+CREATE TABLE metric_alert_rules (
+  id SERIAL PRIMARY KEY,
+  project_id integer REFERENCES projects(id) ON DELETE CASCADE,
+  experiment_id integer REFERENCES experiments(id) ON DELETE CASCADE,
+  metric_name text NOT NULL,
+  metric_group text NOT NULL,
+  condition public.metric_alert_condition NOT NULL,
+  action public.metric_alert_action NOT NULL,
+  threshold double precision NOT NULL DEFAULT 0,
+  "window" integer NOT NULL DEFAULT 0,
+  smaller_is_better boolean NOT NULL DEFAULT true,
+  webhook_id integer REFERENCES webhooks(id) ON DELETE CASCADE,
+  CONSTRAINT metric_alert_rules_one_scope
+    CHECK ((project_id IS NULL) <> (experiment_id IS NULL)),
+  CONSTRAINT metric_alert_rules_webhook_action
+    CHECK ((action = 'WEBHOOK') = (webhook_id IS NOT NULL))
+);
+
+CREATE INDEX ix_metric_alert_rules_project_id ON metric_alert_rules USING btree (project_id);
+CREATE INDEX ix_metric_alert_rules_experiment_id ON metric_alert_rules USING btree (experiment_id);
+
+CREATE TABLE metric_alert_events (
+  id SERIAL PRIMARY KEY,
+  rule_id integer NOT NULL REFERENCES metric_alert_rules(id) ON DELETE CASCADE,
+  trial_id integer NOT NULL REFERENCES trials(id) ON DELETE CASCADE,
+  total_batches integer NOT NULL,
+  value double precision NOT NULL,
+  message text NOT NULL,
+  fired_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (rule_id, trial_id)
+);
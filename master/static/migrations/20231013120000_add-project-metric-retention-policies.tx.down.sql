@@ -0,0 +1 @@
+DROP TABLE project_metric_retention_policies;
@@ -0,0 +1,5 @@
+CREATE TABLE project_metric_retention_policies (
+  project_id integer PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+  raw_retention_days integer NOT NULL CHECK (raw_retention_days >= 0),
+  bucket_size integer NOT NULL CHECK (bucket_size >= 2)
+);
@@ -0,0 +1 @@
+ALTER TABLE cluster_id DROP COLUMN master_migration_version;
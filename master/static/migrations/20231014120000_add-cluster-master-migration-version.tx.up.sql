@@ -0,0 +1 @@
+ALTER TABLE cluster_id ADD COLUMN master_migration_version bigint;
@@ -0,0 +1 @@
+ALTER TABLE trials DROP COLUMN notes;
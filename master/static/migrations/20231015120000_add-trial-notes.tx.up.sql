@@ -0,0 +1 @@
+ALTER TABLE trials ADD COLUMN notes text NOT NULL DEFAULT '';
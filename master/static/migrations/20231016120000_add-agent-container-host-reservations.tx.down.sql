@@ -0,0 +1,3 @@
+ALTER TABLE resourcemanagers_agent_containers
+  DROP COLUMN cpus,
+  DROP COLUMN memory;
@@ -0,0 +1,3 @@
+ALTER TABLE resourcemanagers_agent_containers
+  ADD COLUMN cpus float8 NOT NULL DEFAULT 0,
+  ADD COLUMN memory bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE resourcemanagers_agent_agentstate
+  DROP COLUMN migration_deadline;
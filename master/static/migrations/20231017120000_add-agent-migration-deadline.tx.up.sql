@@ -0,0 +1,2 @@
+ALTER TABLE resourcemanagers_agent_agentstate
+  ADD COLUMN migration_deadline timestamptz;
@@ -7,6 +7,7 @@ SELECT
 		WHEN sum(entries) FILTER (WHERE metric_type = 'number') THEN 'number'
 		WHEN sum(entries) FILTER (WHERE metric_type = 'string') THEN 'string'
 		WHEN sum(entries) FILTER (WHERE metric_type = 'date') THEN 'date'
+		WHEN sum(entries) FILTER (WHERE metric_type = 'histogram') THEN 'histogram'
 		WHEN sum(entries) FILTER (WHERE metric_type = 'object') THEN 'object'
 		WHEN sum(entries) FILTER (WHERE metric_type = 'boolean') THEN 'boolean'
 		WHEN sum(entries) FILTER (WHERE metric_type = 'array') THEN 'array'
@@ -26,6 +27,8 @@ FROM (
 					'^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?$' THEN 'date'
 				ELSE 'string'
 			END
+		WHEN jsonb_typeof(metrics->$2->name) = 'object'
+			AND metrics->$2->name->>'type' = 'histogram' THEN 'histogram'
 		ELSE jsonb_typeof(metrics->$2->name)
 	END as metric_type,
 	trial_id,
@@ -1,7 +1,7 @@
 #!/usr/bin/env bash
 
-source /run/determined/task-signal-handling.sh
-source /run/determined/task-logging-setup.sh
+source "${DET_TASK_ROOT:-}/run/determined/task-signal-handling.sh"
+source "${DET_TASK_ROOT:-}/run/determined/task-logging-setup.sh"
 
 set -e
 
@@ -1,12 +1,12 @@
 #!/bin/bash
 
-source /run/determined/task-signal-handling.sh
-source /run/determined/task-logging-setup.sh
+source "${DET_TASK_ROOT:-}/run/determined/task-signal-handling.sh"
+source "${DET_TASK_ROOT:-}/run/determined/task-logging-setup.sh"
 
 set -e
 
 STARTUP_HOOK="startup-hook.sh"
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_TASK_ROOT:-}/run/determined/pythonuserbase/bin:$PATH"
 
 # If HOME is not explicitly set for a container, libcontainer (Docker) will
 # try to guess it by reading /etc/password directly, which will not work with
@@ -1,11 +1,11 @@
 #!/usr/bin/env bash
 
-source /run/determined/task-signal-handling.sh
-source /run/determined/task-logging-setup.sh
+source "${DET_TASK_ROOT:-}/run/determined/task-signal-handling.sh"
+source "${DET_TASK_ROOT:-}/run/determined/task-logging-setup.sh"
 
 set -e
 
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_TASK_ROOT:-}/run/determined/pythonuserbase/bin:$PATH"
 if [ -z "$DET_PYTHON_EXECUTABLE" ]; then
     export DET_PYTHON_EXECUTABLE="python3"
 fi
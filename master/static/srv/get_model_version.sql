@@ -11,7 +11,8 @@ WITH mv AS (
         notes,
         username,
         user_id,
-        last_updated_time
+        last_updated_time,
+        stage
     FROM model_versions
     LEFT JOIN users ON users.id = model_versions.user_id
     WHERE model_id = $1 AND model_versions.version = $2
@@ -59,5 +60,6 @@ SELECT
     mv.metadata,
     mv.username,
     mv.user_id,
-    mv.last_updated_time
+    mv.last_updated_time,
+    mv.stage
 FROM c, m, mv;
@@ -0,0 +1,61 @@
+WITH RECURSIVE chain AS (
+    SELECT
+        0 AS depth,
+        c.uuid,
+        c.trial_id,
+        c.experiment_id,
+        c.hparams,
+        c.metadata,
+        t.warm_start_checkpoint_id
+    FROM model_versions mv
+    JOIN checkpoints_view c ON c.uuid = mv.checkpoint_uuid
+    LEFT JOIN trials t ON t.id = c.trial_id
+    WHERE mv.id = $1
+    UNION ALL
+    SELECT
+        chain.depth + 1,
+        c.uuid,
+        c.trial_id,
+        c.experiment_id,
+        c.hparams,
+        c.metadata,
+        t.warm_start_checkpoint_id
+    FROM chain
+    JOIN checkpoints_view c ON c.id = chain.warm_start_checkpoint_id
+    LEFT JOIN trials t ON t.id = c.trial_id
+    -- Guard against pathological chains; real warm-start chains are short.
+    WHERE chain.depth < 100
+)
+
+SELECT
+    root.trial_id,
+    root.experiment_id,
+    e.config AS experiment_config,
+    root.hparams,
+    root.metadata AS checkpoint_metadata,
+    e.git_remote,
+    e.git_commit,
+    e.git_committer,
+    e.git_commit_date,
+    (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object(
+            'checkpoint_uuid', ch.uuid,
+            'trial_id', ch.trial_id,
+            'experiment_id', ch.experiment_id,
+            'hparams', ch.hparams
+        ) ORDER BY ch.depth), '[]'::jsonb)
+        FROM chain ch
+        WHERE ch.depth > 0
+    ) AS warm_start_chain,
+    (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object(
+            'dataset_uri', p.dataset_uri,
+            'dataset_hash', p.dataset_hash,
+            'metadata', p.metadata
+        ) ORDER BY p.id), '[]'::jsonb)
+        FROM model_version_provenance p
+        WHERE p.model_version_id = $1
+    ) AS provenance
+FROM chain root
+LEFT JOIN experiments e ON e.id = root.experiment_id
+WHERE root.depth = 0;
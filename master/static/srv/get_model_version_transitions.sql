@@ -0,0 +1,13 @@
+SELECT
+    t.id,
+    t.model_version_id,
+    t.from_stage,
+    t.to_stage,
+    t.reason,
+    t.transition_time,
+    t.user_id,
+    u.username
+FROM model_version_stage_transitions AS t
+LEFT JOIN users AS u ON u.id = t.user_id
+WHERE t.model_version_id = $1
+ORDER BY t.transition_time ASC, t.id ASC;
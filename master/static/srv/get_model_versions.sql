@@ -11,10 +11,12 @@ WITH mv AS (
         notes,
         username,
         user_id,
-        last_updated_time
+        last_updated_time,
+        stage
     FROM model_versions
     LEFT JOIN users ON users.id = model_versions.user_id
     WHERE model_id = $1
+    AND ($2 = '' OR stage::text = ANY(string_to_array($2, ',')))
 ),
 
 m AS (
@@ -52,6 +54,7 @@ SELECT
     mv.name,
     mv.comment,
     mv.metadata,
-    mv.last_updated_time
+    mv.last_updated_time,
+    mv.stage
 FROM proto_checkpoints_view c, mv, m
 WHERE c.uuid = mv.checkpoint_uuid::text;
@@ -1,12 +1,12 @@
 #!/usr/bin/env bash
 
-source /run/determined/task-signal-handling.sh
-source /run/determined/task-logging-setup.sh
+source "${DET_TASK_ROOT:-}/run/determined/task-signal-handling.sh"
+source "${DET_TASK_ROOT:-}/run/determined/task-logging-setup.sh"
 
 set -e
 
 STARTUP_HOOK="startup-hook.sh"
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_TASK_ROOT:-}/run/determined/pythonuserbase/bin:$PATH"
 if [ -z "$DET_PYTHON_EXECUTABLE" ]; then
     export DET_PYTHON_EXECUTABLE="python3"
 fi
@@ -38,7 +38,7 @@ set -x
 test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
 set +x
 
-"$DET_PYTHON_EXECUTABLE" /run/determined/jupyter/check_idle.py &
+"$DET_PYTHON_EXECUTABLE" "${DET_TASK_ROOT:-}/run/determined/jupyter/check_idle.py" &
 
 JUPYTER_LAB_LOG_FORMAT="%(levelname)s: [%(name)s] %(message)s"
 READINESS_REGEX='^.*Jupyter Server .* is running.*$'
@@ -48,8 +48,8 @@ jupyter lab --ServerApp.port=${NOTEBOOK_PORT} \
     --ServerApp.allow_origin="*" \
     --ServerApp.base_url="/proxy/${DET_TASK_ID}/" \
     --ServerApp.allow_root=True \
-    --ServerApp.certfile=/run/determined/jupyter/jupyterCert.pem \
-    --ServerApp.keyfile=/run/determined/jupyter/jupyterKey.key \
+    --ServerApp.certfile="${DET_TASK_ROOT:-}/run/determined/jupyter/jupyterCert.pem" \
+    --ServerApp.keyfile="${DET_TASK_ROOT:-}/run/determined/jupyter/jupyterKey.key" \
     --ServerApp.ip="0.0.0.0" \
     --ServerApp.open_browser=False \
     --ServerApp.token="" \
@@ -60,5 +60,5 @@ jupyter lab --ServerApp.port=${NOTEBOOK_PORT} \
     --LabServerApp.log_format="$JUPYTER_LAB_LOG_FORMAT" \
     --LabApp.log_format="$JUPYTER_LAB_LOG_FORMAT" \
     --ServerApp.log_format="$JUPYTER_LAB_LOG_FORMAT" \
-    2> >(tee -p >("$DET_PYTHON_EXECUTABLE" /run/determined/check_ready_logs.py --ready-regex "${READINESS_REGEX}") >&2)
+    2> >(tee -p >("$DET_PYTHON_EXECUTABLE" "${DET_TASK_ROOT:-}/run/determined/check_ready_logs.py" --ready-regex "${READINESS_REGEX}") >&2)
 wait_and_handle_signals $!
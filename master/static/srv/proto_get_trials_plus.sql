@@ -132,6 +132,7 @@ SELECT
   t.total_batches AS total_batches_processed,
    t.runner_state,
    t.summary_metrics AS summary_metrics,
+  t.notes,
   (
     SELECT extract(epoch from sum(coalesce(a.end_time, now()) - a.start_time))
     FROM allocations a
@@ -1,12 +1,12 @@
 #!/usr/bin/env bash
 
-source /run/determined/task-signal-handling.sh
-source /run/determined/task-logging-setup.sh
+source "${DET_TASK_ROOT:-}/run/determined/task-signal-handling.sh"
+source "${DET_TASK_ROOT:-}/run/determined/task-logging-setup.sh"
 
 set -e
 
 STARTUP_HOOK="startup-hook.sh"
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_TASK_ROOT:-}/run/determined/pythonuserbase/bin:$PATH"
 if [ -z "$DET_PYTHON_EXECUTABLE" ]; then
     export DET_PYTHON_EXECUTABLE="python3"
 fi
@@ -85,15 +85,25 @@ options="$(
 # In k8s, the files we inject into the container are injected via individual
 # file-level bind mounts, which are effectively read-only in docker, so we are
 # unable to edit authorized_keys in place.
-unmodified="/run/determined/ssh/authorized_keys_unmodified"
-modified="/run/determined/ssh/authorized_keys"
+unmodified="${DET_TASK_ROOT:-}/run/determined/ssh/authorized_keys_unmodified"
+modified="${DET_TASK_ROOT:-}/run/determined/ssh/authorized_keys"
 sed -e "s/^/$options /" "$unmodified" >"$modified"
 # Ensure permissions are restrictive enough for ssh
 chmod 600 "$modified"
 
 READINESS_REGEX="Server listening on"
 
+# sshd_config points at the keys in /run/determined, which are under the task root instead for
+# tasks run directly on the agent's host.
+sshd_task_root_options=()
+if [ -n "${DET_TASK_ROOT:-}" ]; then
+    sshd_task_root_options=(
+        -o "HostKey=$DET_TASK_ROOT/run/determined/ssh/id_rsa"
+        -o "AuthorizedKeysFile=$DET_TASK_ROOT/run/determined/ssh/authorized_keys"
+    )
+fi
+
 trap_and_forward_signals
-/usr/sbin/sshd "$@" \
-    2> >(tee -p >("$DET_PYTHON_EXECUTABLE" /run/determined/check_ready_logs.py --ready-regex "$READINESS_REGEX") >&2) &
+/usr/sbin/sshd "$@" "${sshd_task_root_options[@]}" \
+    2> >(tee -p >("$DET_PYTHON_EXECUTABLE" "${DET_TASK_ROOT:-}/run/determined/check_ready_logs.py" --ready-regex "$READINESS_REGEX") >&2) &
 wait_and_handle_signals $!
@@ -1,7 +1,9 @@
 #!/usr/bin/env bash
 
-STDOUT_FILE=/run/determined/train/logs/stdout.log
-STDERR_FILE=/run/determined/train/logs/stderr.log
+# Tasks that the agent runs directly on its host, rather than in a container, find the files placed
+# at /run/determined and /opt/determined under the task root the agent sets in DET_TASK_ROOT.
+STDOUT_FILE="${DET_TASK_ROOT:-}/run/determined/train/logs/stdout.log"
+STDERR_FILE="${DET_TASK_ROOT:-}/run/determined/train/logs/stderr.log"
 
 mkdir -p "$(dirname "$STDOUT_FILE")" "$(dirname "$STDERR_FILE")"
 
@@ -14,7 +16,7 @@ ln -sf /proc/$$/fd/2 "$STDERR_FILE"
 
 # Create a FIFO to monitor process substitution exits, and a count to know how
 # many to wait on.
-DET_LOG_WAIT_FIFO=/run/determined/train/logs/wait.fifo
+DET_LOG_WAIT_FIFO="${DET_TASK_ROOT:-}/run/determined/train/logs/wait.fifo"
 DET_LOG_WAIT_COUNT=0
 mkfifo $DET_LOG_WAIT_FIFO
 
@@ -51,7 +53,7 @@ if [ -n "$DET_K8S_LOG_TO_FILE" ]; then
     ((DET_LOG_WAIT_COUNT += 2))
 fi
 
-export PATH="/run/determined/pythonuserbase/bin:$PATH"
+export PATH="${DET_TASK_ROOT:-}/run/determined/pythonuserbase/bin:$PATH"
 if [ -z "$DET_PYTHON_EXECUTABLE" ]; then
     export DET_PYTHON_EXECUTABLE="python3"
 fi
@@ -63,7 +65,7 @@ if ! "$DET_PYTHON_EXECUTABLE" --version >/dev/null 2>&1; then
 fi
 
 if [ -z "$DET_SKIP_PIP_INSTALL" ]; then
-    "$DET_PYTHON_EXECUTABLE" -m pip install -q --user /opt/determined/wheels/determined*.whl
+    "$DET_PYTHON_EXECUTABLE" -m pip install -q --user "${DET_TASK_ROOT:-}"/opt/determined/wheels/determined*.whl
 else
     if ! "$DET_PYTHON_EXECUTABLE" -c "import determined" >/dev/null 2>&1; then
         echo "{\"log\": \"error: unable run without determined package\n\", \"timestamp\": \"$(date --rfc-3339=seconds)\"}" >&2
@@ -75,11 +77,11 @@ fi
 # When completed, write a single character to the DET_LOG_WAIT_FIFO to signal
 # completion of one procesor.
 exec 1> >(
-    "$DET_PYTHON_EXECUTABLE" /run/determined/enrich_task_logs.py --stdtype stdout >&1
+    "$DET_PYTHON_EXECUTABLE" "${DET_TASK_ROOT:-}/run/determined/enrich_task_logs.py" --stdtype stdout >&1
     printf x >$DET_LOG_WAIT_FIFO
 ) \
 2> >(
-    "$DET_PYTHON_EXECUTABLE" /run/determined/enrich_task_logs.py --stdtype stderr >&2
+    "$DET_PYTHON_EXECUTABLE" "${DET_TASK_ROOT:-}/run/determined/enrich_task_logs.py" --stdtype stderr >&2
     printf x >$DET_LOG_WAIT_FIFO
 )
 
@@ -120,4 +122,4 @@ exec > >(
 ((DET_LOG_WAIT_COUNT += 2))
 
 # As shell exits, wait for stdout/stderr processors to complete
-trap 'source /run/determined/task-logging-teardown.sh' EXIT
+trap 'source "${DET_TASK_ROOT:-}/run/determined/task-logging-teardown.sh"' EXIT
@@ -14,7 +14,8 @@ WITH mv AS (
     comment,
     notes,
     labels,
-    metadata
+    metadata,
+    stage
 ),
 
 m AS (
@@ -54,5 +55,6 @@ SELECT
     mv.name,
     mv.comment,
     mv.notes,
-    mv.metadata
+    mv.metadata,
+    mv.stage
 FROM c, m, mv;